@@ -227,7 +227,7 @@ function FrameworkListPageContent({
                         ref={fileInputRef}
                         onChange={handleFileChange}
                         disabled={isUploading}
-                        accept=".json"
+                        accept=".json,.yaml,.yml"
                       />
                       <p className="text-sm text-muted-foreground">
                         Upload a JSON file containing your framework definition.
@@ -58,7 +58,8 @@ function FrameworkOverviewPageContent({
   const navigate = useNavigate();
   const { organizationId } = useParams();
 
-  // Group controls by their category
+  // Group controls by their category, keeping the categories in the
+  // order of their first control.
   const controlsByCategory = controls.reduce((acc, control) => {
     if (!control?.category) return acc;
     if (!acc[control.category]) {
@@ -68,14 +69,14 @@ function FrameworkOverviewPageContent({
     return acc;
   }, {} as Record<string, typeof controls>);
 
-  const controlCards = Object.entries(controlsByCategory)
-    .sort(([categoryA], [categoryB]) => categoryA.localeCompare(categoryB))
-    .map(([category, controls]) => ({
+  const controlCards = Object.entries(controlsByCategory).map(
+    ([category, controls]) => ({
       title: category,
       controls,
       completed: controls.filter((c) => c?.state === "IMPLEMENTED").length,
       total: controls.length,
-    }));
+    })
+  );
 
   const totalImplemented = controls.filter(
     (c) => c?.state === "IMPLEMENTED"
@@ -0,0 +1,359 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package coredata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/getprobo/probo/pkg/gid"
+	"github.com/jackc/pgx/v5"
+	"go.gearno.de/kit/pg"
+)
+
+type (
+	// APIToken grants its user access to the GraphQL API of a single
+	// organization without a session.
+	APIToken struct {
+		ID             gid.GID      `db:"id"`
+		TenantID       gid.TenantID `db:"tenant_id"`
+		OrganizationID gid.GID      `db:"organization_id"`
+		UserID         gid.GID      `db:"user_id"`
+		Name           string       `db:"name"`
+		HashedToken    []byte       `db:"hashed_token"`
+		ExpiresAt      *time.Time   `db:"expires_at"`
+		LastUsedAt     *time.Time   `db:"last_used_at"`
+		CreatedAt      time.Time    `db:"created_at"`
+		UpdatedAt      time.Time    `db:"updated_at"`
+	}
+
+	APITokens []*APIToken
+
+	ErrAPITokenNotFound struct {
+		message string
+	}
+)
+
+func (e ErrAPITokenNotFound) Error() string {
+	return e.message
+}
+
+func (t APIToken) Expired(now time.Time) bool {
+	return t.ExpiresAt != nil && !now.Before(*t.ExpiresAt)
+}
+
+func (t *APIToken) LoadByID(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+	apiTokenID gid.GID,
+) error {
+	q := `
+SELECT
+    tenant_id,
+    id,
+    organization_id,
+    user_id,
+    name,
+    hashed_token,
+    expires_at,
+    last_used_at,
+    created_at,
+    updated_at
+FROM
+    api_tokens
+WHERE
+    %s
+    AND id = @api_token_id
+LIMIT 1;
+`
+
+	q = fmt.Sprintf(q, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{"api_token_id": apiTokenID}
+	maps.Copy(args, scope.SQLArguments())
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query api token: %w", err)
+	}
+
+	apiToken, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[APIToken])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &ErrAPITokenNotFound{message: fmt.Sprintf("api token %q not found", apiTokenID)}
+		}
+
+		return fmt.Errorf("cannot collect api token: %w", err)
+	}
+
+	*t = apiToken
+
+	return nil
+}
+
+// LoadByHashedToken loads the token matching a digest. It is not scoped
+// as the tenant is only known once the token is resolved.
+func (t *APIToken) LoadByHashedToken(
+	ctx context.Context,
+	conn pg.Conn,
+	hashedToken []byte,
+) error {
+	q := `
+SELECT
+    tenant_id,
+    id,
+    organization_id,
+    user_id,
+    name,
+    hashed_token,
+    expires_at,
+    last_used_at,
+    created_at,
+    updated_at
+FROM
+    api_tokens
+WHERE
+    hashed_token = @hashed_token
+LIMIT 1;
+`
+
+	args := pgx.StrictNamedArgs{"hashed_token": hashedToken}
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query api token: %w", err)
+	}
+
+	apiToken, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[APIToken])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &ErrAPITokenNotFound{message: "api token not found"}
+		}
+
+		return fmt.Errorf("cannot collect api token: %w", err)
+	}
+
+	*t = apiToken
+
+	return nil
+}
+
+func (t APIToken) Insert(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+) error {
+	q := `
+INSERT INTO
+    api_tokens (
+        tenant_id,
+        id,
+        organization_id,
+        user_id,
+        name,
+        hashed_token,
+        expires_at,
+        last_used_at,
+        created_at,
+        updated_at
+    )
+VALUES (
+    @tenant_id,
+    @id,
+    @organization_id,
+    @user_id,
+    @name,
+    @hashed_token,
+    @expires_at,
+    @last_used_at,
+    @created_at,
+    @updated_at
+)
+`
+
+	args := pgx.StrictNamedArgs{
+		"tenant_id":       scope.GetTenantID(),
+		"id":              t.ID,
+		"organization_id": t.OrganizationID,
+		"user_id":         t.UserID,
+		"name":            t.Name,
+		"hashed_token":    t.HashedToken,
+		"expires_at":      t.ExpiresAt,
+		"last_used_at":    t.LastUsedAt,
+		"created_at":      t.CreatedAt,
+		"updated_at":      t.UpdatedAt,
+	}
+
+	_, err := conn.Exec(ctx, q, args)
+	return err
+}
+
+func (t *APIToken) UpdateLastUsedAt(
+	ctx context.Context,
+	conn pg.Conn,
+	lastUsedAt time.Time,
+) error {
+	q := `
+UPDATE api_tokens SET last_used_at = @last_used_at WHERE id = @id
+`
+
+	args := pgx.StrictNamedArgs{"id": t.ID, "last_used_at": lastUsedAt}
+
+	if _, err := conn.Exec(ctx, q, args); err != nil {
+		return err
+	}
+
+	t.LastUsedAt = &lastUsedAt
+
+	return nil
+}
+
+func (t APIToken) Delete(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+) error {
+	q := `
+DELETE FROM api_tokens WHERE %s AND id = @id
+`
+
+	q = fmt.Sprintf(q, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{"id": t.ID}
+	maps.Copy(args, scope.SQLArguments())
+
+	_, err := conn.Exec(ctx, q, args)
+	return err
+}
+
+// DeleteUserOrganizationAPITokens revokes the API tokens of the user for
+// the organization.
+func DeleteUserOrganizationAPITokens(
+	ctx context.Context,
+	conn pg.Conn,
+	userID gid.GID,
+	organizationID gid.GID,
+) error {
+	q := `
+DELETE FROM
+    api_tokens
+WHERE
+    user_id = @user_id
+    AND organization_id = @organization_id
+`
+
+	args := pgx.StrictNamedArgs{
+		"user_id":         userID,
+		"organization_id": organizationID,
+	}
+
+	_, err := conn.Exec(ctx, q, args)
+	return err
+}
+
+func (t *APITokens) LoadByOrganizationID(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+	organizationID gid.GID,
+) error {
+	q := `
+SELECT
+    tenant_id,
+    id,
+    organization_id,
+    user_id,
+    name,
+    hashed_token,
+    expires_at,
+    last_used_at,
+    created_at,
+    updated_at
+FROM
+    api_tokens
+WHERE
+    %s
+    AND organization_id = @organization_id
+ORDER BY
+    created_at DESC
+`
+
+	q = fmt.Sprintf(q, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{"organization_id": organizationID}
+	maps.Copy(args, scope.SQLArguments())
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query api tokens: %w", err)
+	}
+
+	apiTokens, err := pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[APIToken])
+	if err != nil {
+		return fmt.Errorf("cannot collect api tokens: %w", err)
+	}
+
+	*t = apiTokens
+
+	return nil
+}
+
+// LoadByUserID loads the tokens of the user across organizations. It is
+// not scoped as it looks across tenants.
+func (t *APITokens) LoadByUserID(
+	ctx context.Context,
+	conn pg.Conn,
+	userID gid.GID,
+) error {
+	q := `
+SELECT
+    tenant_id,
+    id,
+    organization_id,
+    user_id,
+    name,
+    hashed_token,
+    expires_at,
+    last_used_at,
+    created_at,
+    updated_at
+FROM
+    api_tokens
+WHERE
+    user_id = @user_id
+ORDER BY
+    created_at DESC
+`
+
+	args := pgx.StrictNamedArgs{"user_id": userID}
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query api tokens: %w", err)
+	}
+
+	apiTokens, err := pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[APIToken])
+	if err != nil {
+		return fmt.Errorf("cannot collect api tokens: %w", err)
+	}
+
+	*t = apiTokens
+
+	return nil
+}
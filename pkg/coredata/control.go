@@ -37,10 +37,15 @@ type (
 		Importance  ControlImportance `db:"importance"`
 		State       ControlState      `db:"state"`
 		ContentRef  string            `db:"content_ref"`
-		CreatedAt   time.Time         `db:"created_at"`
-		UpdatedAt   time.Time         `db:"updated_at"`
-		Version     int               `db:"version"`
-		Standards   []string          `db:"standards"`
+		// ContentDigest is the digest of the template content the
+		// control was last imported or upgraded from.
+		ContentDigest *string   `db:"content_digest"`
+		CreatedAt     time.Time `db:"created_at"`
+		UpdatedAt     time.Time `db:"updated_at"`
+		Version       int       `db:"version"`
+		Standards     []string  `db:"standards"`
+		// Position orders the controls of a framework, starting at 0.
+		Position int `db:"position"`
 	}
 
 	Controls []*Control
@@ -59,6 +64,10 @@ func (c Control) CursorKey(orderBy ControlOrderField) page.CursorKey {
 	switch orderBy {
 	case ControlOrderFieldCreatedAt:
 		return page.NewCursorKey(c.ID, c.CreatedAt)
+	case ControlOrderFieldName:
+		return page.NewCursorKey(c.ID, c.Name)
+	case ControlOrderFieldPosition:
+		return page.NewCursorKey(c.ID, c.Position)
 	}
 
 	panic(fmt.Sprintf("unsupported order by: %s", orderBy))
@@ -80,10 +89,12 @@ SELECT
     state,
 	importance,
     content_ref,
+    content_digest,
     created_at,
     updated_at,
 	standards,
-	version
+	version,
+	position
 FROM
     controls
 WHERE
@@ -129,10 +140,12 @@ INSERT INTO
 		state,
         description,
         content_ref,
+        content_digest,
         created_at,
         updated_at,
 		standards,
-		version
+		version,
+		position
     )
 VALUES (
     @tenant_id,
@@ -144,27 +157,31 @@ VALUES (
 	@state,
     @description,
     @content_ref,
+    @content_digest,
     @created_at,
     @updated_at,
 	@standards,
-    @version
+    @version,
+    @position
 );
 `
 
 	args := pgx.StrictNamedArgs{
-		"tenant_id":    scope.GetTenantID(),
-		"control_id":   c.ID,
-		"framework_id": c.FrameworkID,
-		"category":     c.Category,
-		"name":         c.Name,
-		"version":      0,
-		"description":  c.Description,
-		"content_ref":  c.ContentRef,
-		"created_at":   c.CreatedAt,
-		"updated_at":   c.UpdatedAt,
-		"state":        c.State,
-		"importance":   c.Importance,
-		"standards":    c.Standards,
+		"tenant_id":      scope.GetTenantID(),
+		"control_id":     c.ID,
+		"framework_id":   c.FrameworkID,
+		"category":       c.Category,
+		"name":           c.Name,
+		"version":        0,
+		"description":    c.Description,
+		"content_ref":    c.ContentRef,
+		"content_digest": c.ContentDigest,
+		"created_at":     c.CreatedAt,
+		"updated_at":     c.UpdatedAt,
+		"state":          c.State,
+		"importance":     c.Importance,
+		"standards":      c.Standards,
+		"position":       c.Position,
 	}
 	_, err := conn.Exec(ctx, q, args)
 	return err
@@ -187,10 +204,12 @@ SELECT
     state,
 	importance,
     content_ref,
+    content_digest,
     created_at,
     updated_at,
 	standards,
-	version
+	version,
+	position
 FROM
     controls
 WHERE
@@ -219,6 +238,58 @@ WHERE
 	return nil
 }
 
+// LoadAllByFrameworkID loads every control of the framework, without
+// pagination, in position order.
+func (c *Controls) LoadAllByFrameworkID(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+	frameworkID gid.GID,
+) error {
+	q := `
+SELECT
+    id,
+    framework_id,
+    category,
+    name,
+    description,
+    state,
+    importance,
+    content_ref,
+    content_digest,
+    created_at,
+    updated_at,
+    standards,
+    version,
+    position
+FROM
+    controls
+WHERE
+    %s
+    AND framework_id = @framework_id
+ORDER BY
+    position, id
+`
+	q = fmt.Sprintf(q, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{"framework_id": frameworkID}
+	maps.Copy(args, scope.SQLArguments())
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query controls: %w", err)
+	}
+
+	controls, err := pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[Control])
+	if err != nil {
+		return fmt.Errorf("cannot collect controls: %w", err)
+	}
+
+	*c = controls
+
+	return nil
+}
+
 func (c *Control) Update(
 	ctx context.Context,
 	conn pg.Conn,
@@ -246,10 +317,12 @@ RETURNING
 	importance,
 	state,
     content_ref,
+    content_digest,
     created_at,
     updated_at,
     version,
-	standards
+	standards,
+	position
 `
 	q = fmt.Sprintf(q, scope.SQLFragment())
 
@@ -280,3 +353,245 @@ RETURNING
 
 	return nil
 }
+
+// UpdateTemplateContent overwrites the template provided fields of the
+// control with the ones currently set on c, leaving its state untouched.
+func (c *Control) UpdateTemplateContent(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+) error {
+	q := `
+UPDATE controls SET
+    category = @category,
+    name = @name,
+    description = @description,
+    importance = @importance,
+    standards = @standards,
+    content_digest = @content_digest,
+    updated_at = @updated_at,
+    version = version + 1
+WHERE %s
+    AND id = @control_id
+    AND version = @expected_version
+RETURNING
+    version
+`
+	q = fmt.Sprintf(q, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{
+		"control_id":       c.ID,
+		"expected_version": c.Version,
+		"category":         c.Category,
+		"name":             c.Name,
+		"description":      c.Description,
+		"importance":       c.Importance,
+		"standards":        c.Standards,
+		"content_digest":   c.ContentDigest,
+		"updated_at":       c.UpdatedAt,
+	}
+	maps.Copy(args, scope.SQLArguments())
+
+	return conn.QueryRow(ctx, q, args).Scan(&c.Version)
+}
+
+// UpdatePosition moves the control to the position within its framework,
+// shifting the controls in between to keep positions contiguous. c must
+// be loaded as its current position is needed.
+func (c *Control) UpdatePosition(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+	expectedVersion int,
+	position int,
+) error {
+	shiftQuery := `
+UPDATE controls SET
+    position = CASE
+        WHEN @position < @current_position THEN position + 1
+        ELSE position - 1
+    END
+WHERE %s
+    AND framework_id = @framework_id
+    AND id <> @control_id
+    AND position BETWEEN LEAST(@position, @current_position) AND GREATEST(@position, @current_position)
+`
+	shiftQuery = fmt.Sprintf(shiftQuery, scope.SQLFragment())
+
+	shiftArgs := pgx.StrictNamedArgs{
+		"control_id":       c.ID,
+		"framework_id":     c.FrameworkID,
+		"position":         position,
+		"current_position": c.Position,
+	}
+	maps.Copy(shiftArgs, scope.SQLArguments())
+
+	if _, err := conn.Exec(ctx, shiftQuery, shiftArgs); err != nil {
+		return fmt.Errorf("cannot shift controls: %w", err)
+	}
+
+	q := `
+UPDATE controls SET
+    position = @position,
+    updated_at = @updated_at,
+    version = version + 1
+WHERE %s
+    AND id = @control_id
+    AND version = @expected_version
+RETURNING
+    id,
+    framework_id,
+    category,
+    name,
+    description,
+    importance,
+    state,
+    content_ref,
+    content_digest,
+    created_at,
+    updated_at,
+    version,
+    standards,
+    position
+`
+	q = fmt.Sprintf(q, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{
+		"control_id":       c.ID,
+		"expected_version": expectedVersion,
+		"position":         position,
+		"updated_at":       time.Now(),
+	}
+	maps.Copy(args, scope.SQLArguments())
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query controls: %w", err)
+	}
+
+	control, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[Control])
+	if err != nil {
+		return fmt.Errorf("cannot collect controls: %w", err)
+	}
+
+	*c = control
+
+	return nil
+}
+
+// UpdateFramework re-parents the control, and its tasks with it, under
+// another framework at the given position.
+func (c *Control) UpdateFramework(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+	expectedVersion int,
+	frameworkID gid.GID,
+	position int,
+) error {
+	q := `
+UPDATE controls SET
+    framework_id = @framework_id,
+    position = @position,
+    updated_at = @updated_at,
+    version = version + 1
+WHERE %s
+    AND id = @control_id
+    AND version = @expected_version
+RETURNING
+    id,
+    framework_id,
+    category,
+    name,
+    description,
+    importance,
+    state,
+    content_ref,
+    content_digest,
+    created_at,
+    updated_at,
+    version,
+    standards,
+    position
+`
+	q = fmt.Sprintf(q, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{
+		"control_id":       c.ID,
+		"expected_version": expectedVersion,
+		"framework_id":     frameworkID,
+		"position":         position,
+		"updated_at":       time.Now(),
+	}
+	maps.Copy(args, scope.SQLArguments())
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query controls: %w", err)
+	}
+
+	control, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[Control])
+	if err != nil {
+		return fmt.Errorf("cannot collect controls: %w", err)
+	}
+
+	*c = control
+
+	return nil
+}
+
+// Delete removes the control with its tasks and the evidences attached
+// to them. Evidence files stored outside of the database are left to the
+// caller.
+func (c Control) Delete(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+	expectedVersion int,
+) error {
+	evidencesQuery := `
+DELETE
+FROM
+    evidences
+WHERE
+    %s
+    AND task_id IN (
+        SELECT id FROM tasks WHERE control_id = @control_id
+    );
+`
+	evidencesQuery = fmt.Sprintf(evidencesQuery, scope.SQLFragment())
+
+	evidencesArgs := pgx.StrictNamedArgs{"control_id": c.ID}
+	maps.Copy(evidencesArgs, scope.SQLArguments())
+
+	if _, err := conn.Exec(ctx, evidencesQuery, evidencesArgs); err != nil {
+		return fmt.Errorf("cannot delete evidences: %w", err)
+	}
+
+	// Tasks are removed by the controls foreign key cascade.
+	q := `
+DELETE
+FROM
+    controls
+WHERE
+    %s
+    AND id = @control_id
+    AND version = @expected_version
+RETURNING
+    id
+`
+	q = fmt.Sprintf(q, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{
+		"control_id":       c.ID,
+		"expected_version": expectedVersion,
+	}
+	maps.Copy(args, scope.SQLArguments())
+
+	var controlID gid.GID
+	if err := conn.QueryRow(ctx, q, args).Scan(&controlID); err != nil {
+		return fmt.Errorf("cannot delete control: %w", err)
+	}
+
+	return nil
+}
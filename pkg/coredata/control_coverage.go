@@ -0,0 +1,236 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package coredata
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"github.com/getprobo/probo/pkg/gid"
+	"github.com/jackc/pgx/v5"
+	"go.gearno.de/kit/pg"
+)
+
+type (
+	// StandardCoverage is how many controls of a framework reference a
+	// standard and how many of them are covered, either implemented
+	// themselves or through a mapped control of another framework.
+	StandardCoverage struct {
+		Standard            string `db:"standard"`
+		ControlCount        int    `db:"control_count"`
+		CoveredControlCount int    `db:"covered_control_count"`
+	}
+
+	StandardCoverages []*StandardCoverage
+)
+
+// mappedControlSQL matches the controls o mapped to the control c: the
+// controls of the other frameworks of the organization whose standard
+// references are mapped to the ones of c, either by the organization or by
+// a crosswalk between framework templates. References are only compared
+// as is between frameworks of the same template, such as a clone.
+const mappedControlSQL = `
+o.framework_id <> c.framework_id
+AND o.framework_id IN (
+    SELECT id FROM frameworks
+    WHERE organization_id = (SELECT organization_id FROM frameworks WHERE id = c.framework_id)
+)
+AND (
+    (
+        o.standards && c.standards
+        AND (SELECT content_ref FROM frameworks WHERE id = c.framework_id) <> ''
+        AND (SELECT content_ref FROM frameworks WHERE id = o.framework_id) =
+            (SELECT content_ref FROM frameworks WHERE id = c.framework_id)
+    )
+    OR EXISTS (
+        SELECT 1
+        FROM standard_mappings m
+        WHERE
+            (
+                m.source_framework_id = c.framework_id
+                AND m.source_standard = ANY(c.standards)
+                AND m.target_framework_id = o.framework_id
+                AND m.target_standard = ANY(o.standards)
+            )
+            OR (
+                m.source_framework_id = o.framework_id
+                AND m.source_standard = ANY(o.standards)
+                AND m.target_framework_id = c.framework_id
+                AND m.target_standard = ANY(c.standards)
+            )
+    )
+    OR EXISTS (
+        SELECT 1
+        FROM standard_crosswalks x
+        WHERE
+            (
+                x.source_framework_ref = (SELECT content_ref FROM frameworks WHERE id = c.framework_id)
+                AND x.source_standard = ANY(c.standards)
+                AND x.target_framework_ref = (SELECT content_ref FROM frameworks WHERE id = o.framework_id)
+                AND x.target_standard = ANY(o.standards)
+            )
+            OR (
+                x.source_framework_ref = (SELECT content_ref FROM frameworks WHERE id = o.framework_id)
+                AND x.source_standard = ANY(o.standards)
+                AND x.target_framework_ref = (SELECT content_ref FROM frameworks WHERE id = c.framework_id)
+                AND x.target_standard = ANY(c.standards)
+            )
+    )
+)`
+
+// controlCoveredSQL is true when the control c is implemented, or when
+// one of its mapped controls is.
+const controlCoveredSQL = `(
+    c.state = @implemented_state
+    OR EXISTS (
+        SELECT 1 FROM controls o WHERE o.state = @implemented_state AND ` + mappedControlSQL + `
+    )
+)`
+
+// LoadMappedByControlID loads the controls of the other frameworks of the
+// organization mapped to the control.
+func (c *Controls) LoadMappedByControlID(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+	controlID gid.GID,
+) error {
+	q := `
+SELECT
+    id,
+    framework_id,
+    category,
+    name,
+    description,
+    state,
+    importance,
+    content_ref,
+    content_digest,
+    created_at,
+    updated_at,
+    standards,
+    version,
+    position
+FROM
+    controls o
+WHERE
+    %s
+    AND EXISTS (
+        SELECT 1 FROM controls c WHERE c.id = @control_id AND %s
+    )
+ORDER BY
+    framework_id, position, id
+`
+	q = fmt.Sprintf(q, scope.SQLFragment(), mappedControlSQL)
+
+	args := pgx.StrictNamedArgs{"control_id": controlID}
+	maps.Copy(args, scope.SQLArguments())
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query controls: %w", err)
+	}
+
+	controls, err := pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[Control])
+	if err != nil {
+		return fmt.Errorf("cannot collect controls: %w", err)
+	}
+
+	*c = controls
+
+	return nil
+}
+
+// IsCovered tells whether the control is implemented, either itself or
+// through one of its mapped controls.
+func (c Control) IsCovered(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+) (bool, error) {
+	q := `
+SELECT
+    %s
+FROM
+    controls c
+WHERE
+    %s
+    AND c.id = @control_id
+`
+	q = fmt.Sprintf(q, controlCoveredSQL, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{
+		"control_id":        c.ID,
+		"implemented_state": ControlStateImplemented,
+	}
+	maps.Copy(args, scope.SQLArguments())
+
+	var covered bool
+	if err := conn.QueryRow(ctx, q, args).Scan(&covered); err != nil {
+		return false, fmt.Errorf("cannot query control coverage: %w", err)
+	}
+
+	return covered, nil
+}
+
+func (sc *StandardCoverages) LoadByFrameworkID(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+	frameworkID gid.GID,
+) error {
+	q := `
+SELECT
+    standard,
+    COUNT(*) AS control_count,
+    COUNT(*) FILTER (WHERE covered) AS covered_control_count
+FROM (
+    SELECT
+        unnest(c.standards) AS standard,
+        %s AS covered
+    FROM
+        controls c
+    WHERE
+        %s
+        AND c.framework_id = @framework_id
+) AS control_coverages
+GROUP BY
+    standard
+ORDER BY
+    standard
+`
+	q = fmt.Sprintf(q, controlCoveredSQL, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{
+		"framework_id":      frameworkID,
+		"implemented_state": ControlStateImplemented,
+	}
+	maps.Copy(args, scope.SQLArguments())
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query standard coverages: %w", err)
+	}
+
+	coverages, err := pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[StandardCoverage])
+	if err != nil {
+		return fmt.Errorf("cannot collect standard coverages: %w", err)
+	}
+
+	*sc = coverages
+
+	return nil
+}
@@ -20,6 +20,8 @@ type (
 
 const (
 	ControlOrderFieldCreatedAt ControlOrderField = "CREATED_AT"
+	ControlOrderFieldName      ControlOrderField = "NAME"
+	ControlOrderFieldPosition  ControlOrderField = "POSITION"
 )
 
 func (p ControlOrderField) Column() string {
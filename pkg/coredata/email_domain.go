@@ -0,0 +1,312 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package coredata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/getprobo/probo/pkg/gid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.gearno.de/kit/pg"
+)
+
+type (
+	// EmailDomain is an email domain claimed by an organization. The
+	// organization proves it owns the domain by publishing the
+	// verification token in a DNS TXT record.
+	EmailDomain struct {
+		ID                gid.GID    `db:"id"`
+		OrganizationID    gid.GID    `db:"organization_id"`
+		Domain            string     `db:"domain"`
+		VerificationToken string     `db:"verification_token"`
+		VerifiedAt        *time.Time `db:"verified_at"`
+		CreatedAt         time.Time  `db:"created_at"`
+		UpdatedAt         time.Time  `db:"updated_at"`
+	}
+
+	EmailDomains []*EmailDomain
+
+	ErrEmailDomainNotFound struct {
+		message string
+	}
+
+	ErrEmailDomainAlreadyExists struct {
+		message string
+	}
+)
+
+func (e ErrEmailDomainNotFound) Error() string {
+	return e.message
+}
+
+func (e ErrEmailDomainAlreadyExists) Error() string {
+	return e.message
+}
+
+func (ed EmailDomain) Verified() bool {
+	return ed.VerifiedAt != nil
+}
+
+func (ed *EmailDomain) LoadByID(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+	emailDomainID gid.GID,
+) error {
+	q := `
+SELECT
+    id,
+    organization_id,
+    domain,
+    verification_token,
+    verified_at,
+    created_at,
+    updated_at
+FROM
+    email_domains
+WHERE
+    %s
+    AND id = @email_domain_id
+LIMIT 1;
+`
+
+	q = fmt.Sprintf(q, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{"email_domain_id": emailDomainID}
+	maps.Copy(args, scope.SQLArguments())
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query email domain: %w", err)
+	}
+
+	emailDomain, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[EmailDomain])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &ErrEmailDomainNotFound{message: fmt.Sprintf("email domain %q not found", emailDomainID)}
+		}
+
+		return fmt.Errorf("cannot collect email domain: %w", err)
+	}
+
+	*ed = emailDomain
+
+	return nil
+}
+
+// LoadVerifiedByOrganizationIDAndDomain loads the domain the organization
+// verified it owns. It is not scoped as it is used at sign-in, before
+// any tenant is known.
+func (ed *EmailDomain) LoadVerifiedByOrganizationIDAndDomain(
+	ctx context.Context,
+	conn pg.Conn,
+	organizationID gid.GID,
+	domain string,
+) error {
+	q := `
+SELECT
+    id,
+    organization_id,
+    domain,
+    verification_token,
+    verified_at,
+    created_at,
+    updated_at
+FROM
+    email_domains
+WHERE
+    organization_id = @organization_id
+    AND domain = @domain
+    AND verified_at IS NOT NULL
+LIMIT 1;
+`
+
+	args := pgx.StrictNamedArgs{
+		"organization_id": organizationID,
+		"domain":          domain,
+	}
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query email domain: %w", err)
+	}
+
+	emailDomain, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[EmailDomain])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &ErrEmailDomainNotFound{message: fmt.Sprintf("verified email domain %q not found", domain)}
+		}
+
+		return fmt.Errorf("cannot collect email domain: %w", err)
+	}
+
+	*ed = emailDomain
+
+	return nil
+}
+
+func (ed EmailDomain) Insert(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+) error {
+	q := `
+INSERT INTO
+    email_domains (
+        tenant_id,
+        id,
+        organization_id,
+        domain,
+        verification_token,
+        verified_at,
+        created_at,
+        updated_at
+    )
+VALUES (
+    @tenant_id,
+    @id,
+    @organization_id,
+    @domain,
+    @verification_token,
+    @verified_at,
+    @created_at,
+    @updated_at
+)
+`
+
+	args := pgx.StrictNamedArgs{
+		"tenant_id":          scope.GetTenantID(),
+		"id":                 ed.ID,
+		"organization_id":    ed.OrganizationID,
+		"domain":             ed.Domain,
+		"verification_token": ed.VerificationToken,
+		"verified_at":        ed.VerifiedAt,
+		"created_at":         ed.CreatedAt,
+		"updated_at":         ed.UpdatedAt,
+	}
+
+	_, err := conn.Exec(ctx, q, args)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return &ErrEmailDomainAlreadyExists{
+				message: fmt.Sprintf("email domain %q is already claimed by the organization", ed.Domain),
+			}
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (ed *EmailDomain) MarkAsVerified(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+	now time.Time,
+) error {
+	q := `
+UPDATE email_domains
+SET
+    verified_at = @verified_at,
+    updated_at = @updated_at
+WHERE
+    %s
+    AND id = @email_domain_id
+`
+
+	q = fmt.Sprintf(q, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{
+		"email_domain_id": ed.ID,
+		"verified_at":     now,
+		"updated_at":      now,
+	}
+	maps.Copy(args, scope.SQLArguments())
+
+	if _, err := conn.Exec(ctx, q, args); err != nil {
+		return fmt.Errorf("cannot mark email domain as verified: %w", err)
+	}
+
+	ed.VerifiedAt = &now
+	ed.UpdatedAt = now
+
+	return nil
+}
+
+func (ed EmailDomain) Delete(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+) error {
+	q := `DELETE FROM email_domains WHERE %s AND id = @email_domain_id`
+	q = fmt.Sprintf(q, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{"email_domain_id": ed.ID}
+	maps.Copy(args, scope.SQLArguments())
+
+	_, err := conn.Exec(ctx, q, args)
+	return err
+}
+
+func (ed *EmailDomains) LoadByOrganizationID(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+	organizationID gid.GID,
+) error {
+	q := `
+SELECT
+    id,
+    organization_id,
+    domain,
+    verification_token,
+    verified_at,
+    created_at,
+    updated_at
+FROM
+    email_domains
+WHERE
+    %s
+    AND organization_id = @organization_id
+ORDER BY
+    domain
+`
+
+	q = fmt.Sprintf(q, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{"organization_id": organizationID}
+	maps.Copy(args, scope.SQLArguments())
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query email domains: %w", err)
+	}
+
+	emailDomains, err := pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[EmailDomain])
+	if err != nil {
+		return fmt.Errorf("cannot collect email domains: %w", err)
+	}
+
+	*ed = emailDomains
+
+	return nil
+}
@@ -29,4 +29,15 @@ const (
 	UserEntityType
 	SessionEntityType
 	EmailEntityType
+	RecoveryCodeEntityType
+	WebAuthnCredentialEntityType
+	OIDCConfigurationEntityType
+	SAMLConfigurationEntityType
+	SCIMConfigurationEntityType
+	SCIMUserEntityType
+	SCIMGroupEntityType
+	APITokenEntityType
+	InvitationEntityType
+	StandardMappingEntityType
+	EmailDomainEntityType
 )
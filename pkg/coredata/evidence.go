@@ -192,6 +192,153 @@ WHERE
 	return nil
 }
 
+func (e *Evidence) UpdateState(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+	state EvidenceState,
+) error {
+	q := `
+UPDATE evidences SET
+    state = @state,
+    updated_at = @updated_at
+WHERE
+    %s
+    AND id = @evidence_id
+RETURNING
+    id,
+    task_id,
+    state,
+    object_key,
+    mime_type,
+    size,
+    filename,
+    created_at,
+    updated_at
+`
+
+	q = fmt.Sprintf(q, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{
+		"evidence_id": e.ID,
+		"state":       state,
+		"updated_at":  time.Now(),
+	}
+	maps.Copy(args, scope.SQLArguments())
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query evidence: %w", err)
+	}
+
+	evidence, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[Evidence])
+	if err != nil {
+		return fmt.Errorf("cannot collect evidence: %w", err)
+	}
+
+	*e = evidence
+
+	return nil
+}
+
+func (e *Evidences) LoadAllByFrameworkID(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+	frameworkID gid.GID,
+) error {
+	q := `
+SELECT
+    id,
+    task_id,
+    state,
+    object_key,
+    mime_type,
+    size,
+    filename,
+    created_at,
+    updated_at
+FROM
+    evidences
+WHERE
+    %s
+    AND task_id IN (
+        SELECT id FROM tasks WHERE control_id IN (
+            SELECT id FROM controls WHERE framework_id = @framework_id
+        )
+    )
+ORDER BY
+    created_at, id
+`
+
+	q = fmt.Sprintf(q, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{"framework_id": frameworkID}
+	maps.Copy(args, scope.SQLArguments())
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query evidence: %w", err)
+	}
+
+	evidences, err := pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[Evidence])
+	if err != nil {
+		return fmt.Errorf("cannot collect evidence: %w", err)
+	}
+
+	*e = evidences
+
+	return nil
+}
+
+func (e *Evidences) LoadAllByControlID(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+	controlID gid.GID,
+) error {
+	q := `
+SELECT
+    id,
+    task_id,
+    state,
+    object_key,
+    mime_type,
+    size,
+    filename,
+    created_at,
+    updated_at
+FROM
+    evidences
+WHERE
+    %s
+    AND task_id IN (
+        SELECT id FROM tasks WHERE control_id = @control_id
+    )
+ORDER BY
+    created_at, id
+`
+
+	q = fmt.Sprintf(q, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{"control_id": controlID}
+	maps.Copy(args, scope.SQLArguments())
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query evidence: %w", err)
+	}
+
+	evidences, err := pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[Evidence])
+	if err != nil {
+		return fmt.Errorf("cannot collect evidence: %w", err)
+	}
+
+	*e = evidences
+
+	return nil
+}
+
 func (e Evidence) Delete(
 	ctx context.Context,
 	conn pg.Conn,
@@ -191,12 +191,38 @@ VALUES (
 	return err
 }
 
+// Delete removes the framework together with its controls, their tasks
+// and the evidences attached to those tasks. Evidence files stored
+// outside of the database are left to the caller.
 func (f Framework) Delete(
 	ctx context.Context,
 	conn pg.Conn,
 	scope Scoper,
 ) error {
-	q := `
+	evidencesQuery := `
+DELETE
+FROM
+    evidences
+WHERE
+    %s
+    AND task_id IN (
+        SELECT id FROM tasks WHERE control_id IN (
+            SELECT id FROM controls WHERE framework_id = @framework_id
+        )
+    );
+`
+
+	// Tasks are removed by the controls foreign key cascade.
+	controlsQuery := `
+DELETE
+FROM
+    controls
+WHERE
+    %s
+    AND framework_id = @framework_id;
+`
+
+	frameworkQuery := `
 DELETE
 FROM
     frameworks
@@ -207,8 +233,78 @@ WHERE
 
 	args := pgx.StrictNamedArgs{"framework_id": f.ID}
 	maps.Copy(args, scope.SQLArguments())
+
+	for _, q := range []string{evidencesQuery, controlsQuery, frameworkQuery} {
+		q = fmt.Sprintf(q, scope.SQLFragment())
+
+		if _, err := conn.Exec(ctx, q, args); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// NextControlPosition returns the position following the last control
+// of the framework.
+func (f Framework) NextControlPosition(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+) (int, error) {
+	q := `
+SELECT
+    COALESCE(MAX(position) + 1, 0)
+FROM
+    controls
+WHERE
+    %s
+    AND framework_id = @framework_id;
+`
+
 	q = fmt.Sprintf(q, scope.SQLFragment())
 
+	args := pgx.StrictNamedArgs{"framework_id": f.ID}
+	maps.Copy(args, scope.SQLArguments())
+
+	var position int
+	if err := conn.QueryRow(ctx, q, args).Scan(&position); err != nil {
+		return 0, fmt.Errorf("cannot query control position: %w", err)
+	}
+
+	return position, nil
+}
+
+// CompactControlPositions renumbers the controls of the framework from 0,
+// keeping their order, to close the gap left by a removed control.
+func (f Framework) CompactControlPositions(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+) error {
+	q := `
+UPDATE controls SET
+    position = ordered.position
+FROM (
+    SELECT
+        id,
+        row_number() OVER (ORDER BY position, id) - 1 AS position
+    FROM
+        controls
+    WHERE
+        %s
+        AND framework_id = @framework_id
+) AS ordered
+WHERE
+    controls.id = ordered.id
+    AND controls.position <> ordered.position;
+`
+
+	q = fmt.Sprintf(q, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{"framework_id": f.ID}
+	maps.Copy(args, scope.SQLArguments())
+
 	_, err := conn.Exec(ctx, q, args)
 	return err
 }
@@ -0,0 +1,297 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package coredata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/getprobo/probo/pkg/gid"
+	"github.com/jackc/pgx/v5"
+	"go.gearno.de/kit/pg"
+)
+
+type (
+	Invitation struct {
+		ID             gid.GID          `db:"id"`
+		TenantID       gid.TenantID     `db:"tenant_id"`
+		OrganizationID gid.GID          `db:"organization_id"`
+		EmailAddress   string           `db:"email_address"`
+		FullName       string           `db:"full_name"`
+		Status         InvitationStatus `db:"status"`
+		ExpiresAt      time.Time        `db:"expires_at"`
+		AcceptedAt     *time.Time       `db:"accepted_at"`
+		CreatedAt      time.Time        `db:"created_at"`
+		UpdatedAt      time.Time        `db:"updated_at"`
+	}
+
+	Invitations []*Invitation
+
+	ErrInvitationNotFound struct {
+		message string
+	}
+)
+
+func (e ErrInvitationNotFound) Error() string {
+	return e.message
+}
+
+func (i Invitation) Expired(now time.Time) bool {
+	return !now.Before(i.ExpiresAt)
+}
+
+func (i *Invitation) LoadByID(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+	invitationID gid.GID,
+) error {
+	q := `
+SELECT
+    tenant_id,
+    id,
+    organization_id,
+    email_address,
+    full_name,
+    status,
+    expires_at,
+    accepted_at,
+    created_at,
+    updated_at
+FROM
+    invitations
+WHERE
+    %s
+    AND id = @invitation_id
+LIMIT 1
+FOR UPDATE;
+`
+
+	q = fmt.Sprintf(q, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{"invitation_id": invitationID}
+	maps.Copy(args, scope.SQLArguments())
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query invitation: %w", err)
+	}
+
+	invitation, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[Invitation])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &ErrInvitationNotFound{message: fmt.Sprintf("invitation %q not found", invitationID)}
+		}
+
+		return fmt.Errorf("cannot collect invitation: %w", err)
+	}
+
+	*i = invitation
+
+	return nil
+}
+
+// LoadPendingByEmailAddress loads the pending invitation sent to the
+// email address for the organization, there is at most one.
+func (i *Invitation) LoadPendingByEmailAddress(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+	organizationID gid.GID,
+	emailAddress string,
+) error {
+	q := `
+SELECT
+    tenant_id,
+    id,
+    organization_id,
+    email_address,
+    full_name,
+    status,
+    expires_at,
+    accepted_at,
+    created_at,
+    updated_at
+FROM
+    invitations
+WHERE
+    %s
+    AND organization_id = @organization_id
+    AND email_address = @email_address
+    AND status = 'PENDING'
+LIMIT 1
+FOR UPDATE;
+`
+
+	q = fmt.Sprintf(q, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{
+		"organization_id": organizationID,
+		"email_address":   emailAddress,
+	}
+	maps.Copy(args, scope.SQLArguments())
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query invitation: %w", err)
+	}
+
+	invitation, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[Invitation])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &ErrInvitationNotFound{message: "pending invitation not found"}
+		}
+
+		return fmt.Errorf("cannot collect invitation: %w", err)
+	}
+
+	*i = invitation
+
+	return nil
+}
+
+func (i Invitation) Insert(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+) error {
+	q := `
+INSERT INTO
+    invitations (
+        tenant_id,
+        id,
+        organization_id,
+        email_address,
+        full_name,
+        status,
+        expires_at,
+        accepted_at,
+        created_at,
+        updated_at
+    )
+VALUES (
+    @tenant_id,
+    @id,
+    @organization_id,
+    @email_address,
+    @full_name,
+    @status,
+    @expires_at,
+    @accepted_at,
+    @created_at,
+    @updated_at
+)
+`
+
+	args := pgx.StrictNamedArgs{
+		"tenant_id":       scope.GetTenantID(),
+		"id":              i.ID,
+		"organization_id": i.OrganizationID,
+		"email_address":   i.EmailAddress,
+		"full_name":       i.FullName,
+		"status":          i.Status,
+		"expires_at":      i.ExpiresAt,
+		"accepted_at":     i.AcceptedAt,
+		"created_at":      i.CreatedAt,
+		"updated_at":      i.UpdatedAt,
+	}
+
+	_, err := conn.Exec(ctx, q, args)
+	return err
+}
+
+func (i Invitation) Update(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+) error {
+	q := `
+UPDATE invitations
+SET
+    full_name = @full_name,
+    status = @status,
+    expires_at = @expires_at,
+    accepted_at = @accepted_at,
+    updated_at = @updated_at
+WHERE
+    %s
+    AND id = @id
+`
+
+	q = fmt.Sprintf(q, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{
+		"id":          i.ID,
+		"full_name":   i.FullName,
+		"status":      i.Status,
+		"expires_at":  i.ExpiresAt,
+		"accepted_at": i.AcceptedAt,
+		"updated_at":  i.UpdatedAt,
+	}
+	maps.Copy(args, scope.SQLArguments())
+
+	_, err := conn.Exec(ctx, q, args)
+	return err
+}
+
+func (i *Invitations) LoadByOrganizationID(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+	organizationID gid.GID,
+) error {
+	q := `
+SELECT
+    tenant_id,
+    id,
+    organization_id,
+    email_address,
+    full_name,
+    status,
+    expires_at,
+    accepted_at,
+    created_at,
+    updated_at
+FROM
+    invitations
+WHERE
+    %s
+    AND organization_id = @organization_id
+ORDER BY
+    created_at DESC
+`
+
+	q = fmt.Sprintf(q, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{"organization_id": organizationID}
+	maps.Copy(args, scope.SQLArguments())
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query invitations: %w", err)
+	}
+
+	invitations, err := pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[Invitation])
+	if err != nil {
+		return fmt.Errorf("cannot collect invitations: %w", err)
+	}
+
+	*i = invitations
+
+	return nil
+}
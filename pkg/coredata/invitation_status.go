@@ -0,0 +1,79 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package coredata
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+type (
+	InvitationStatus uint8
+)
+
+const (
+	InvitationStatusPending InvitationStatus = iota
+	InvitationStatusAccepted
+	InvitationStatusRevoked
+)
+
+func (is InvitationStatus) MarshalText() ([]byte, error) {
+	return []byte(is.String()), nil
+}
+
+func (is *InvitationStatus) UnmarshalText(data []byte) error {
+	val := string(data)
+
+	switch val {
+	case InvitationStatusPending.String():
+		*is = InvitationStatusPending
+	case InvitationStatusAccepted.String():
+		*is = InvitationStatusAccepted
+	case InvitationStatusRevoked.String():
+		*is = InvitationStatusRevoked
+	default:
+		return fmt.Errorf("invalid InvitationStatus value: %q", val)
+	}
+
+	return nil
+}
+
+func (is InvitationStatus) String() string {
+	var val string
+
+	switch is {
+	case InvitationStatusPending:
+		val = "PENDING"
+	case InvitationStatusAccepted:
+		val = "ACCEPTED"
+	case InvitationStatusRevoked:
+		val = "REVOKED"
+	}
+
+	return val
+}
+
+func (is *InvitationStatus) Scan(value any) error {
+	val, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("invalid scan source for InvitationStatus, expected string got %T", value)
+	}
+
+	return is.UnmarshalText([]byte(val))
+}
+
+func (is InvitationStatus) Value() (driver.Value, error) {
+	return is.String(), nil
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package coredata
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+type (
+	MembershipRole uint8
+)
+
+// MembershipRoleMember is the zero value so a membership created without
+// an explicit role never grants administrative rights.
+const (
+	MembershipRoleMember MembershipRole = iota
+	MembershipRoleOwner
+	MembershipRoleAdmin
+	MembershipRoleViewer
+	MembershipRoleAuditor
+)
+
+func (mr MembershipRole) MarshalText() ([]byte, error) {
+	return []byte(mr.String()), nil
+}
+
+func (mr *MembershipRole) UnmarshalText(data []byte) error {
+	val := string(data)
+
+	switch val {
+	case MembershipRoleOwner.String():
+		*mr = MembershipRoleOwner
+	case MembershipRoleAdmin.String():
+		*mr = MembershipRoleAdmin
+	case MembershipRoleMember.String():
+		*mr = MembershipRoleMember
+	case MembershipRoleViewer.String():
+		*mr = MembershipRoleViewer
+	case MembershipRoleAuditor.String():
+		*mr = MembershipRoleAuditor
+	default:
+		return fmt.Errorf("invalid MembershipRole value: %q", val)
+	}
+
+	return nil
+}
+
+func (mr MembershipRole) String() string {
+	var val string
+
+	switch mr {
+	case MembershipRoleOwner:
+		val = "OWNER"
+	case MembershipRoleAdmin:
+		val = "ADMIN"
+	case MembershipRoleMember:
+		val = "MEMBER"
+	case MembershipRoleViewer:
+		val = "VIEWER"
+	case MembershipRoleAuditor:
+		val = "AUDITOR"
+	}
+
+	return val
+}
+
+func (mr *MembershipRole) Scan(value any) error {
+	val, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("invalid scan source for MembershipRole, expected string got %T", value)
+	}
+
+	return mr.UnmarshalText([]byte(val))
+}
+
+func (mr MembershipRole) Value() (driver.Value, error) {
+	return mr.String(), nil
+}
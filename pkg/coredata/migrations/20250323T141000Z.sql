@@ -0,0 +1,13 @@
+CREATE TABLE saml_configurations (
+    tenant_id TEXT NOT NULL,
+    id TEXT PRIMARY KEY,
+    organization_id TEXT NOT NULL UNIQUE REFERENCES organizations(id) ON DELETE CASCADE,
+    idp_entity_id TEXT NOT NULL,
+    idp_metadata TEXT NOT NULL,
+    email_attribute TEXT NOT NULL,
+    full_name_attribute TEXT NOT NULL,
+    allowed_email_domains TEXT[] NOT NULL DEFAULT '{}',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
@@ -0,0 +1,42 @@
+CREATE TABLE scim_configurations (
+    tenant_id TEXT NOT NULL,
+    id TEXT PRIMARY KEY,
+    organization_id TEXT NOT NULL UNIQUE REFERENCES organizations(id) ON DELETE CASCADE,
+    hashed_token BYTEA NOT NULL UNIQUE,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE TABLE scim_users (
+    tenant_id TEXT NOT NULL,
+    id TEXT PRIMARY KEY,
+    organization_id TEXT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    people_id TEXT NOT NULL REFERENCES peoples(id) ON DELETE CASCADE,
+    external_id TEXT,
+    user_name TEXT NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    UNIQUE (organization_id, user_name),
+    UNIQUE (organization_id, user_id)
+);
+
+CREATE TABLE scim_groups (
+    tenant_id TEXT NOT NULL,
+    id TEXT PRIMARY KEY,
+    organization_id TEXT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    external_id TEXT,
+    display_name TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    UNIQUE (organization_id, display_name)
+);
+
+CREATE TABLE scim_group_members (
+    scim_group_id TEXT NOT NULL REFERENCES scim_groups(id) ON DELETE CASCADE,
+    scim_user_id TEXT NOT NULL REFERENCES scim_users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (scim_group_id, scim_user_id)
+);
@@ -0,0 +1,14 @@
+ALTER TABLE users_organizations ADD COLUMN role TEXT NOT NULL DEFAULT 'MEMBER';
+
+-- Members had full control over their organization so far: the earliest
+-- member of each organization becomes its owner and the others admins.
+UPDATE users_organizations SET role = 'ADMIN';
+
+UPDATE users_organizations uo SET role = 'OWNER'
+FROM (
+    SELECT DISTINCT ON (organization_id) organization_id, user_id
+    FROM users_organizations
+    ORDER BY organization_id, created_at, user_id
+) first_members
+WHERE uo.organization_id = first_members.organization_id
+    AND uo.user_id = first_members.user_id;
@@ -0,0 +1,10 @@
+ALTER TABLE sessions
+    ADD COLUMN user_agent TEXT NOT NULL DEFAULT '',
+    ADD COLUMN ip_address TEXT NOT NULL DEFAULT '',
+    ADD COLUMN last_activity_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE sessions SET last_activity_at = updated_at;
+
+ALTER TABLE sessions ALTER COLUMN last_activity_at SET NOT NULL;
+
+CREATE INDEX sessions_user_id_idx ON sessions (user_id);
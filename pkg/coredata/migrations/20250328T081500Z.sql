@@ -0,0 +1,8 @@
+CREATE TABLE sign_in_throttles (
+    key TEXT PRIMARY KEY,
+    failed_attempts INTEGER NOT NULL,
+    last_failed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    locked_until TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
@@ -0,0 +1,18 @@
+CREATE TABLE invitations (
+    tenant_id TEXT NOT NULL,
+    id TEXT PRIMARY KEY,
+    organization_id TEXT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    email_address TEXT NOT NULL,
+    full_name TEXT NOT NULL,
+    status TEXT NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    accepted_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX invitations_organization_id_idx ON invitations (organization_id);
+
+CREATE UNIQUE INDEX invitations_pending_email_address_idx
+    ON invitations (organization_id, email_address)
+    WHERE status = 'PENDING';
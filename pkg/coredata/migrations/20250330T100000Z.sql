@@ -0,0 +1,5 @@
+ALTER TABLE organizations
+    ADD COLUMN auto_join_email_domains TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX organizations_auto_join_email_domains_idx
+    ON organizations USING GIN (auto_join_email_domains);
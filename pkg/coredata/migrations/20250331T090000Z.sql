@@ -0,0 +1,2 @@
+ALTER TABLE controls ADD COLUMN content_digest TEXT;
+ALTER TABLE tasks ADD COLUMN content_digest TEXT;
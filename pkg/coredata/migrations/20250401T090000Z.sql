@@ -0,0 +1,17 @@
+CREATE TABLE standard_mappings (
+    tenant_id TEXT NOT NULL,
+    id TEXT PRIMARY KEY,
+    organization_id TEXT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    source_standard TEXT NOT NULL,
+    target_standard TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    CHECK (source_standard < target_standard)
+);
+
+CREATE UNIQUE INDEX standard_mappings_organization_id_standards_idx
+    ON standard_mappings (organization_id, source_standard, target_standard);
+
+CREATE INDEX standard_mappings_organization_id_target_standard_idx
+    ON standard_mappings (organization_id, target_standard);
+
+CREATE INDEX controls_standards_idx ON controls USING GIN (standards);
@@ -0,0 +1,15 @@
+ALTER TABLE controls ADD COLUMN position INTEGER;
+
+UPDATE controls
+SET position = ordered.position
+FROM (
+    SELECT
+        id,
+        row_number() OVER (PARTITION BY framework_id ORDER BY created_at, id) - 1 AS position
+    FROM controls
+) AS ordered
+WHERE controls.id = ordered.id;
+
+ALTER TABLE controls ALTER COLUMN position SET NOT NULL;
+
+CREATE INDEX controls_framework_id_position_idx ON controls (framework_id, position);
@@ -0,0 +1,41 @@
+CREATE TABLE control_state_transitions (
+    tenant_id TEXT NOT NULL,
+    id TEXT PRIMARY KEY,
+    control_id TEXT NOT NULL REFERENCES controls(id) ON DELETE CASCADE,
+    from_state control_state NOT NULL,
+    to_state control_state NOT NULL,
+    reason TEXT,
+    changed_by_id TEXT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX control_state_transitions_control_id_idx
+    ON control_state_transitions (control_id, created_at);
+
+CREATE TABLE task_state_transitions (
+    tenant_id TEXT NOT NULL,
+    id TEXT PRIMARY KEY,
+    task_id TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    from_state task_state NOT NULL,
+    to_state task_state NOT NULL,
+    reason TEXT,
+    changed_by_id TEXT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX task_state_transitions_task_id_idx
+    ON task_state_transitions (task_id, created_at);
+
+CREATE TABLE evidence_state_transitions (
+    tenant_id TEXT NOT NULL,
+    id TEXT PRIMARY KEY,
+    evidence_id TEXT NOT NULL REFERENCES evidences(id) ON DELETE CASCADE,
+    from_state evidence_state NOT NULL,
+    to_state evidence_state NOT NULL,
+    reason TEXT,
+    changed_by_id TEXT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX evidence_state_transitions_evidence_id_idx
+    ON evidence_state_transitions (evidence_id, created_at);
@@ -0,0 +1,9 @@
+CREATE TABLE used_saml_assertions (
+    organization_id TEXT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    assertion_id TEXT NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (organization_id, assertion_id)
+);
+
+CREATE INDEX used_saml_assertions_expires_at_idx ON used_saml_assertions (expires_at);
@@ -0,0 +1,148 @@
+-- Standard references only make sense within their framework, "6.1" of a
+-- framework has nothing to do with "6.1" of another one. Mappings now
+-- link the standards of two frameworks.
+ALTER TABLE standard_mappings
+    ADD COLUMN source_framework_id TEXT REFERENCES frameworks(id) ON DELETE CASCADE,
+    ADD COLUMN target_framework_id TEXT REFERENCES frameworks(id) ON DELETE CASCADE;
+
+-- Existing mappings are kept when each of their references is used by
+-- the controls of a single framework of the organization.
+UPDATE standard_mappings m
+SET
+    source_framework_id = (
+        SELECT MIN(c.framework_id)
+        FROM controls c
+        WHERE
+            c.framework_id IN (SELECT id FROM frameworks WHERE organization_id = m.organization_id)
+            AND m.source_standard = ANY(c.standards)
+        HAVING COUNT(DISTINCT c.framework_id) = 1
+    ),
+    target_framework_id = (
+        SELECT MIN(c.framework_id)
+        FROM controls c
+        WHERE
+            c.framework_id IN (SELECT id FROM frameworks WHERE organization_id = m.organization_id)
+            AND m.target_standard = ANY(c.standards)
+        HAVING COUNT(DISTINCT c.framework_id) = 1
+    );
+
+DELETE FROM standard_mappings
+WHERE
+    source_framework_id IS NULL
+    OR target_framework_id IS NULL
+    OR source_framework_id = target_framework_id;
+
+-- The smallest reference is no longer necessarily the source, the order
+-- is enforced by the service which compares framework IDs first.
+ALTER TABLE standard_mappings
+    DROP CONSTRAINT standard_mappings_check,
+    ALTER COLUMN source_framework_id SET NOT NULL,
+    ALTER COLUMN target_framework_id SET NOT NULL;
+
+DROP INDEX standard_mappings_organization_id_standards_idx;
+DROP INDEX standard_mappings_organization_id_target_standard_idx;
+
+CREATE UNIQUE INDEX standard_mappings_frameworks_standards_idx
+    ON standard_mappings (source_framework_id, source_standard, target_framework_id, target_standard);
+
+CREATE INDEX standard_mappings_target_framework_id_target_standard_idx
+    ON standard_mappings (target_framework_id, target_standard);
+
+-- Crosswalks map the standards of framework templates, identified by
+-- their content-ref, for every organization. They are symmetric.
+CREATE TABLE standard_crosswalks (
+    source_framework_ref TEXT NOT NULL,
+    source_standard TEXT NOT NULL,
+    target_framework_ref TEXT NOT NULL,
+    target_standard TEXT NOT NULL,
+    PRIMARY KEY (source_framework_ref, source_standard, target_framework_ref, target_standard)
+);
+
+CREATE INDEX standard_crosswalks_target_idx
+    ON standard_crosswalks (target_framework_ref, target_standard);
+
+-- SOC 2 (2017 trust services criteria) to ISO/IEC 27001:2022 Annex A.
+INSERT INTO standard_crosswalks (source_framework_ref, source_standard, target_framework_ref, target_standard)
+SELECT
+    '8d133718-f74b-4393-b072-e5fc89bcb952',
+    soc2,
+    'f369faf9-9145-4f88-86a1-390e23763802',
+    iso27001
+FROM (
+    VALUES
+        ('CC 1.2', 'A.5.4'),
+        ('CC 1.3', 'A.5.2'),
+        ('CC 1.3', 'A.5.3'),
+        ('CC 1.4', 'A.6.1'),
+        ('CC 1.4', 'A.6.2'),
+        ('CC 1.4', 'A.6.3'),
+        ('CC 1.5', 'A.5.4'),
+        ('CC 1.5', 'A.6.4'),
+        ('CC 2.1', 'A.5.9'),
+        ('CC 2.1', 'A.5.12'),
+        ('CC 2.2', 'A.5.1'),
+        ('CC 2.2', 'A.6.3'),
+        ('CC 2.2', 'A.6.8'),
+        ('CC 2.3', 'A.5.5'),
+        ('CC 2.3', 'A.5.6'),
+        ('CC 2.3', 'A.5.14'),
+        ('CC 2.3', 'A.6.6'),
+        ('CC 3.2', 'A.5.7'),
+        ('CC 3.2', 'A.5.8'),
+        ('CC 4.1', 'A.5.35'),
+        ('CC 4.1', 'A.5.36'),
+        ('CC 4.2', 'A.5.35'),
+        ('CC 5.1', 'A.5.3'),
+        ('CC 5.2', 'A.5.37'),
+        ('CC 5.3', 'A.5.1'),
+        ('CC 5.3', 'A.5.37'),
+        ('CC 6.1', 'A.5.15'),
+        ('CC 6.1', 'A.5.17'),
+        ('CC 6.1', 'A.8.2'),
+        ('CC 6.1', 'A.8.3'),
+        ('CC 6.1', 'A.8.5'),
+        ('CC 6.1', 'A.8.24'),
+        ('CC 6.2', 'A.5.16'),
+        ('CC 6.2', 'A.5.18'),
+        ('CC 6.3', 'A.5.15'),
+        ('CC 6.3', 'A.5.18'),
+        ('CC 6.3', 'A.8.2'),
+        ('CC 6.4', 'A.7.1'),
+        ('CC 6.4', 'A.7.2'),
+        ('CC 6.4', 'A.7.3'),
+        ('CC 6.4', 'A.7.4'),
+        ('CC 6.5', 'A.7.10'),
+        ('CC 6.5', 'A.7.14'),
+        ('CC 6.5', 'A.8.10'),
+        ('CC 6.6', 'A.8.20'),
+        ('CC 6.6', 'A.8.21'),
+        ('CC 6.6', 'A.8.22'),
+        ('CC 6.6', 'A.8.23'),
+        ('CC 6.7', 'A.5.14'),
+        ('CC 6.7', 'A.7.10'),
+        ('CC 6.7', 'A.8.1'),
+        ('CC 6.7', 'A.8.24'),
+        ('CC 6.8', 'A.8.7'),
+        ('CC 6.8', 'A.8.19'),
+        ('CC 7.1', 'A.8.8'),
+        ('CC 7.1', 'A.8.9'),
+        ('CC 7.2', 'A.8.15'),
+        ('CC 7.2', 'A.8.16'),
+        ('CC 7.3', 'A.5.25'),
+        ('CC 7.3', 'A.6.8'),
+        ('CC 7.5', 'A.5.26'),
+        ('CC 7.5', 'A.5.27'),
+        ('CC 7.5', 'A.5.29'),
+        ('CC 8.1', 'A.8.25'),
+        ('CC 8.1', 'A.8.29'),
+        ('CC 8.1', 'A.8.31'),
+        ('CC 8.1', 'A.8.32'),
+        ('CC 9.1', 'A.5.29'),
+        ('CC 9.1', 'A.5.30'),
+        ('CC 9.1', 'A.8.13'),
+        ('CC 9.1', 'A.8.14'),
+        ('CC 9.2', 'A.5.19'),
+        ('CC 9.2', 'A.5.20'),
+        ('CC 9.2', 'A.5.21'),
+        ('CC 9.2', 'A.5.22')
+) AS crosswalk (soc2, iso27001);
@@ -0,0 +1,2 @@
+CREATE INDEX sign_in_throttles_last_failed_at_idx
+    ON sign_in_throttles (last_failed_at);
@@ -0,0 +1,180 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package coredata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/getprobo/probo/pkg/gid"
+	"github.com/jackc/pgx/v5"
+	"go.gearno.de/kit/pg"
+)
+
+type (
+	OIDCConfiguration struct {
+		ID                  gid.GID      `db:"id"`
+		TenantID            gid.TenantID `db:"tenant_id"`
+		OrganizationID      gid.GID      `db:"organization_id"`
+		IssuerURL           string       `db:"issuer_url"`
+		ClientID            string       `db:"client_id"`
+		ClientSecret        string       `db:"client_secret"`
+		AllowedEmailDomains []string     `db:"allowed_email_domains"`
+		Enabled             bool         `db:"enabled"`
+		CreatedAt           time.Time    `db:"created_at"`
+		UpdatedAt           time.Time    `db:"updated_at"`
+	}
+
+	ErrOIDCConfigurationNotFound struct {
+		OrganizationID gid.GID
+	}
+)
+
+func (e ErrOIDCConfigurationNotFound) Error() string {
+	return fmt.Sprintf("oidc configuration not found for organization %q", e.OrganizationID)
+}
+
+func (oc *OIDCConfiguration) LoadByOrganizationID(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+	organizationID gid.GID,
+) error {
+	q := `
+SELECT
+    tenant_id,
+    id,
+    organization_id,
+    issuer_url,
+    client_id,
+    client_secret,
+    allowed_email_domains,
+    enabled,
+    created_at,
+    updated_at
+FROM
+    oidc_configurations
+WHERE
+    %s
+    AND organization_id = @organization_id
+LIMIT 1;
+`
+
+	q = fmt.Sprintf(q, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{"organization_id": organizationID}
+	maps.Copy(args, scope.SQLArguments())
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query oidc configuration: %w", err)
+	}
+
+	configuration, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[OIDCConfiguration])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &ErrOIDCConfigurationNotFound{OrganizationID: organizationID}
+		}
+
+		return fmt.Errorf("cannot collect oidc configuration: %w", err)
+	}
+
+	*oc = configuration
+
+	return nil
+}
+
+func (oc *OIDCConfiguration) Upsert(
+	ctx context.Context,
+	conn pg.Conn,
+) error {
+	q := `
+INSERT INTO
+    oidc_configurations (
+        tenant_id,
+        id,
+        organization_id,
+        issuer_url,
+        client_id,
+        client_secret,
+        allowed_email_domains,
+        enabled,
+        created_at,
+        updated_at
+    )
+VALUES (
+    @tenant_id,
+    @id,
+    @organization_id,
+    @issuer_url,
+    @client_id,
+    @client_secret,
+    @allowed_email_domains,
+    @enabled,
+    @created_at,
+    @updated_at
+)
+ON CONFLICT (organization_id) DO UPDATE SET
+    issuer_url = EXCLUDED.issuer_url,
+    client_id = EXCLUDED.client_id,
+    client_secret = EXCLUDED.client_secret,
+    allowed_email_domains = EXCLUDED.allowed_email_domains,
+    enabled = EXCLUDED.enabled,
+    updated_at = EXCLUDED.updated_at
+RETURNING
+    id,
+    created_at
+`
+
+	args := pgx.StrictNamedArgs{
+		"tenant_id":             oc.TenantID,
+		"id":                    oc.ID,
+		"organization_id":       oc.OrganizationID,
+		"issuer_url":            oc.IssuerURL,
+		"client_id":             oc.ClientID,
+		"client_secret":         oc.ClientSecret,
+		"allowed_email_domains": oc.AllowedEmailDomains,
+		"enabled":               oc.Enabled,
+		"created_at":            oc.CreatedAt,
+		"updated_at":            oc.UpdatedAt,
+	}
+
+	return conn.QueryRow(ctx, q, args).Scan(&oc.ID, &oc.CreatedAt)
+}
+
+func (oc *OIDCConfiguration) Delete(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+) error {
+	q := `
+DELETE FROM
+    oidc_configurations
+WHERE
+    %s
+    AND id = @id
+`
+
+	q = fmt.Sprintf(q, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{"id": oc.ID}
+	maps.Copy(args, scope.SQLArguments())
+
+	_, err := conn.Exec(ctx, q, args)
+	return err
+}
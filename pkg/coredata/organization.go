@@ -32,8 +32,12 @@ type (
 		TenantID      gid.TenantID `db:"tenant_id"`
 		Name          string       `db:"name"`
 		LogoObjectKey string       `db:"logo_object_key"`
-		CreatedAt     time.Time    `db:"created_at"`
-		UpdatedAt     time.Time    `db:"updated_at"`
+		MFARequired   bool         `db:"mfa_required"`
+		// AutoJoinEmailDomains lists the email domains whose users join
+		// the organization on their own once their address is verified.
+		AutoJoinEmailDomains []string  `db:"auto_join_email_domains"`
+		CreatedAt            time.Time `db:"created_at"`
+		UpdatedAt            time.Time `db:"updated_at"`
 	}
 
 	Organizations []*Organization
@@ -60,6 +64,8 @@ SELECT
     id,
     name,
     logo_object_key,
+    mfa_required,
+    auto_join_email_domains,
     created_at,
     updated_at
 FROM
@@ -90,6 +96,96 @@ LIMIT 1;
 	return nil
 }
 
+func (o *Organizations) LoadMFARequiredByUserID(
+	ctx context.Context,
+	conn pg.Conn,
+	userID gid.GID,
+) error {
+	q := `
+SELECT
+    tenant_id,
+    id,
+    name,
+    logo_object_key,
+    mfa_required,
+    auto_join_email_domains,
+    created_at,
+    updated_at
+FROM
+    organizations
+WHERE
+    mfa_required = TRUE
+    AND id IN (
+        SELECT organization_id FROM users_organizations WHERE user_id = @user_id
+    )
+`
+
+	args := pgx.StrictNamedArgs{"user_id": userID}
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query organizations: %w", err)
+	}
+
+	organizations, err := pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[Organization])
+	if err != nil {
+		return fmt.Errorf("cannot collect organizations: %w", err)
+	}
+
+	*o = organizations
+
+	return nil
+}
+
+// LoadByAutoJoinEmailDomain loads the organizations users of the email
+// domain automatically join. Only organizations which verified they own
+// the domain are returned. It is not scoped as it looks across tenants.
+func (o *Organizations) LoadByAutoJoinEmailDomain(
+	ctx context.Context,
+	conn pg.Conn,
+	domain string,
+) error {
+	q := `
+SELECT
+    tenant_id,
+    id,
+    name,
+    logo_object_key,
+    mfa_required,
+    auto_join_email_domains,
+    created_at,
+    updated_at
+FROM
+    organizations
+WHERE
+    auto_join_email_domains @> ARRAY[@domain]::TEXT[]
+    AND EXISTS (
+        SELECT 1
+        FROM email_domains
+        WHERE
+            email_domains.organization_id = organizations.id
+            AND email_domains.domain = @domain
+            AND email_domains.verified_at IS NOT NULL
+    )
+`
+
+	args := pgx.StrictNamedArgs{"domain": domain}
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query organizations: %w", err)
+	}
+
+	organizations, err := pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[Organization])
+	if err != nil {
+		return fmt.Errorf("cannot collect organizations: %w", err)
+	}
+
+	*o = organizations
+
+	return nil
+}
+
 func (o *Organization) Insert(
 	ctx context.Context,
 	conn pg.Conn,
@@ -100,18 +196,22 @@ INSERT INTO organizations (
     id,
     name,
     logo_object_key,
+    mfa_required,
+    auto_join_email_domains,
     created_at,
     updated_at
-) VALUES (@tenant_id, @id, @name, @logo_object_key, @created_at, @updated_at)
+) VALUES (@tenant_id, @id, @name, @logo_object_key, @mfa_required, @auto_join_email_domains, @created_at, @updated_at)
 `
 
 	args := pgx.StrictNamedArgs{
-		"tenant_id":       o.TenantID,
-		"id":              o.ID,
-		"name":            o.Name,
-		"logo_object_key": o.LogoObjectKey,
-		"created_at":      o.CreatedAt,
-		"updated_at":      o.UpdatedAt,
+		"tenant_id":               o.TenantID,
+		"id":                      o.ID,
+		"name":                    o.Name,
+		"logo_object_key":         o.LogoObjectKey,
+		"mfa_required":            o.MFARequired,
+		"auto_join_email_domains": o.AutoJoinEmailDomains,
+		"created_at":              o.CreatedAt,
+		"updated_at":              o.UpdatedAt,
 	}
 
 	_, err := conn.Exec(ctx, q, args)
@@ -132,6 +232,8 @@ UPDATE organizations
 SET
     name = @name,
     logo_object_key = @logo_object_key,
+    mfa_required = @mfa_required,
+    auto_join_email_domains = @auto_join_email_domains,
     updated_at = @updated_at
 WHERE
     %s
@@ -141,10 +243,12 @@ WHERE
 	q = fmt.Sprintf(q, scope.SQLFragment())
 
 	args := pgx.StrictNamedArgs{
-		"id":              o.ID,
-		"name":            o.Name,
-		"logo_object_key": o.LogoObjectKey,
-		"updated_at":      o.UpdatedAt,
+		"id":                      o.ID,
+		"name":                    o.Name,
+		"logo_object_key":         o.LogoObjectKey,
+		"mfa_required":            o.MFARequired,
+		"auto_join_email_domains": o.AutoJoinEmailDomains,
+		"updated_at":              o.UpdatedAt,
 	}
 
 	maps.Copy(args, scope.SQLArguments())
@@ -16,6 +16,7 @@ package coredata
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"maps"
 	"time"
@@ -48,8 +49,16 @@ type (
 		AdditionalEmailAddresses *[]string
 		Kind                     *PeopleKind
 	}
+
+	ErrPeopleNotFound struct {
+		message string
+	}
 )
 
+func (e ErrPeopleNotFound) Error() string {
+	return e.message
+}
+
 func (p People) CursorKey(orderBy PeopleOrderField) page.CursorKey {
 	switch orderBy {
 	case PeopleOrderFieldCreatedAt:
@@ -106,6 +115,112 @@ LIMIT 1;
 	return nil
 }
 
+func (p *People) LoadByPrimaryEmailAddress(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+	organizationID gid.GID,
+	emailAddress string,
+) error {
+	q := `
+SELECT
+    id,
+    organization_id,
+    kind,
+    full_name,
+    primary_email_address,
+    additional_email_addresses,
+    created_at,
+    updated_at,
+    version
+FROM
+    peoples
+WHERE
+    %s
+    AND organization_id = @organization_id
+    AND lower(primary_email_address) = lower(@primary_email_address)
+ORDER BY
+    created_at
+LIMIT 1;
+`
+
+	q = fmt.Sprintf(q, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{"organization_id": organizationID, "primary_email_address": emailAddress}
+	maps.Copy(args, scope.SQLArguments())
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query people: %w", err)
+	}
+
+	people, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[People])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &ErrPeopleNotFound{message: fmt.Sprintf("people with email %q not found", emailAddress)}
+		}
+
+		return fmt.Errorf("cannot collect people: %w", err)
+	}
+
+	*p = people
+
+	return nil
+}
+
+// LoadByEmailAddressForUserID loads the people records matching the email
+// address in every organization the user is a member of. It is not scoped
+// as it looks across tenants.
+func (p *Peoples) LoadByEmailAddressForUserID(
+	ctx context.Context,
+	conn pg.Conn,
+	userID gid.GID,
+	emailAddress string,
+) error {
+	q := `
+SELECT
+    id,
+    organization_id,
+    kind,
+    full_name,
+    primary_email_address,
+    additional_email_addresses,
+    created_at,
+    updated_at,
+    version
+FROM
+    peoples
+WHERE
+    organization_id IN (
+        SELECT organization_id FROM users_organizations WHERE user_id = @user_id
+    )
+    AND (
+        lower(primary_email_address) = lower(@email_address)
+        OR lower(@email_address) = ANY(
+            SELECT lower(address) FROM unnest(additional_email_addresses) AS address
+        )
+    )
+ORDER BY
+    created_at
+`
+
+	args := pgx.StrictNamedArgs{"user_id": userID, "email_address": emailAddress}
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query peoples: %w", err)
+	}
+
+	peoples, err := pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[People])
+	if err != nil {
+		return fmt.Errorf("cannot collect peoples: %w", err)
+	}
+
+	*p = peoples
+
+	return nil
+}
+
 func (p People) Insert(
 	ctx context.Context,
 	conn pg.Conn,
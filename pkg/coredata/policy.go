@@ -143,6 +143,53 @@ WHERE
 	return nil
 }
 
+func (p *Policies) LoadByOwnerID(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+	ownerID gid.GID,
+) error {
+	q := `
+SELECT
+    id,
+    organization_id,
+    owner_id,
+    name,
+    status,
+    content,
+    review_date,
+    created_at,
+    updated_at,
+    version
+FROM
+    policies
+WHERE
+    %s
+    AND owner_id = @owner_id
+ORDER BY
+    created_at
+`
+
+	q = fmt.Sprintf(q, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{"owner_id": ownerID}
+	maps.Copy(args, scope.SQLArguments())
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query policies: %w", err)
+	}
+
+	policies, err := pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[Policy])
+	if err != nil {
+		return fmt.Errorf("cannot collect policies: %w", err)
+	}
+
+	*p = policies
+
+	return nil
+}
+
 func (p Policy) Insert(
 	ctx context.Context,
 	conn pg.Conn,
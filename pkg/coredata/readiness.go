@@ -0,0 +1,206 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package coredata
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/getprobo/probo/pkg/gid"
+	"github.com/jackc/pgx/v5"
+	"go.gearno.de/kit/pg"
+)
+
+type (
+	// Readiness summarises the compliance posture of a set of controls:
+	// the controls of a framework or of all the frameworks of an
+	// organization. Tasks and evidences of not applicable controls are
+	// left out as there is nothing to do about them.
+	Readiness struct {
+		ControlCount                     int           `db:"control_count"`
+		NotStartedControlCount           int           `db:"not_started_control_count"`
+		InProgressControlCount           int           `db:"in_progress_control_count"`
+		NotApplicableControlCount        int           `db:"not_applicable_control_count"`
+		ImplementedControlCount          int           `db:"implemented_control_count"`
+		MandatoryControlCount            int           `db:"mandatory_control_count"`
+		PreferredControlCount            int           `db:"preferred_control_count"`
+		AdvancedControlCount             int           `db:"advanced_control_count"`
+		ApplicableMandatoryControlCount  int           `db:"applicable_mandatory_control_count"`
+		ImplementedMandatoryControlCount int           `db:"implemented_mandatory_control_count"`
+		TaskCount                        int           `db:"task_count"`
+		TodoTaskCount                    int           `db:"todo_task_count"`
+		DoneTaskCount                    int           `db:"done_task_count"`
+		RemainingTimeEstimate            time.Duration `db:"remaining_time_estimate"`
+		EvidenceCount                    int           `db:"evidence_count"`
+		ValidEvidenceCount               int           `db:"valid_evidence_count"`
+		InvalidEvidenceCount             int           `db:"invalid_evidence_count"`
+		ExpiredEvidenceCount             int           `db:"expired_evidence_count"`
+	}
+)
+
+const readinessSQL = `
+WITH
+    scoped_controls AS (
+        SELECT id, state, importance FROM controls WHERE %s AND %s
+    ),
+    scoped_tasks AS (
+        SELECT id, state, time_estimate
+        FROM tasks
+        WHERE %s
+            AND control_id IN (
+                SELECT id FROM scoped_controls WHERE state <> @control_not_applicable
+            )
+    ),
+    scoped_evidences AS (
+        SELECT state
+        FROM evidences
+        WHERE %s
+            AND task_id IN (SELECT id FROM scoped_tasks)
+    )
+SELECT
+    c.*,
+    t.*,
+    e.*
+FROM
+    (
+        SELECT
+            COUNT(*) AS control_count,
+            COUNT(*) FILTER (WHERE state = @control_not_started) AS not_started_control_count,
+            COUNT(*) FILTER (WHERE state = @control_in_progress) AS in_progress_control_count,
+            COUNT(*) FILTER (WHERE state = @control_not_applicable) AS not_applicable_control_count,
+            COUNT(*) FILTER (WHERE state = @control_implemented) AS implemented_control_count,
+            COUNT(*) FILTER (WHERE importance = @importance_mandatory) AS mandatory_control_count,
+            COUNT(*) FILTER (WHERE importance = @importance_preferred) AS preferred_control_count,
+            COUNT(*) FILTER (WHERE importance = @importance_advanced) AS advanced_control_count,
+            COUNT(*) FILTER (
+                WHERE importance = @importance_mandatory AND state <> @control_not_applicable
+            ) AS applicable_mandatory_control_count,
+            COUNT(*) FILTER (
+                WHERE importance = @importance_mandatory AND state = @control_implemented
+            ) AS implemented_mandatory_control_count
+        FROM
+            scoped_controls
+    ) AS c,
+    (
+        SELECT
+            COUNT(*) AS task_count,
+            COUNT(*) FILTER (WHERE state = @task_todo) AS todo_task_count,
+            COUNT(*) FILTER (WHERE state = @task_done) AS done_task_count,
+            COALESCE(
+                SUM(time_estimate) FILTER (WHERE state = @task_todo),
+                INTERVAL '0'
+            ) AS remaining_time_estimate
+        FROM
+            scoped_tasks
+    ) AS t,
+    (
+        SELECT
+            COUNT(*) AS evidence_count,
+            COUNT(*) FILTER (WHERE state = @evidence_valid) AS valid_evidence_count,
+            COUNT(*) FILTER (WHERE state = @evidence_invalid) AS invalid_evidence_count,
+            COUNT(*) FILTER (WHERE state = @evidence_expired) AS expired_evidence_count
+        FROM
+            scoped_evidences
+    ) AS e
+`
+
+// MandatoryImplementedPercentage is the share of the applicable mandatory
+// controls which are implemented, between 0 and 100.
+func (r Readiness) MandatoryImplementedPercentage() float64 {
+	if r.ApplicableMandatoryControlCount == 0 {
+		return 0
+	}
+
+	return float64(r.ImplementedMandatoryControlCount) * 100 / float64(r.ApplicableMandatoryControlCount)
+}
+
+func (r *Readiness) LoadByFrameworkID(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+	frameworkID gid.GID,
+) error {
+	return r.load(
+		ctx,
+		conn,
+		scope,
+		"framework_id = @framework_id",
+		pgx.StrictNamedArgs{"framework_id": frameworkID},
+	)
+}
+
+func (r *Readiness) LoadByOrganizationID(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+	organizationID gid.GID,
+) error {
+	return r.load(
+		ctx,
+		conn,
+		scope,
+		"framework_id IN (SELECT id FROM frameworks WHERE organization_id = @organization_id)",
+		pgx.StrictNamedArgs{"organization_id": organizationID},
+	)
+}
+
+func (r *Readiness) load(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+	controlsFragment string,
+	controlsArgs pgx.StrictNamedArgs,
+) error {
+	q := fmt.Sprintf(
+		readinessSQL,
+		scope.SQLFragment(),
+		controlsFragment,
+		scope.SQLFragment(),
+		scope.SQLFragment(),
+	)
+
+	args := pgx.StrictNamedArgs{
+		"control_not_started":    ControlStateNotStarted,
+		"control_in_progress":    ControlStateInProgress,
+		"control_not_applicable": ControlStateNotApplicable,
+		"control_implemented":    ControlStateImplemented,
+		"importance_mandatory":   ControlImportanceMandatory,
+		"importance_preferred":   ControlImportancePreferred,
+		"importance_advanced":    ControlImportanceAdvanced,
+		"task_todo":              TaskStateTodo,
+		"task_done":              TaskStateDone,
+		"evidence_valid":         EvidenceStateValid,
+		"evidence_invalid":       EvidenceStateInvalid,
+		"evidence_expired":       EvidenceStateExpired,
+	}
+	maps.Copy(args, controlsArgs)
+	maps.Copy(args, scope.SQLArguments())
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query readiness: %w", err)
+	}
+
+	readiness, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[Readiness])
+	if err != nil {
+		return fmt.Errorf("cannot collect readiness: %w", err)
+	}
+
+	*r = readiness
+
+	return nil
+}
@@ -0,0 +1,153 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package coredata
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/getprobo/probo/pkg/gid"
+	"github.com/jackc/pgx/v5"
+	"go.gearno.de/kit/pg"
+)
+
+type (
+	RecoveryCode struct {
+		ID         gid.GID    `db:"id"`
+		UserID     gid.GID    `db:"user_id"`
+		HashedCode []byte     `db:"hashed_code"`
+		UsedAt     *time.Time `db:"used_at"`
+		CreatedAt  time.Time  `db:"created_at"`
+	}
+
+	RecoveryCodes []*RecoveryCode
+)
+
+func (rcs *RecoveryCodes) LoadUnusedByUserID(
+	ctx context.Context,
+	conn pg.Conn,
+	userID gid.GID,
+) error {
+	q := `
+SELECT
+    id,
+    user_id,
+    hashed_code,
+    used_at,
+    created_at
+FROM
+    recovery_codes
+WHERE
+    user_id = @user_id
+    AND used_at IS NULL
+`
+
+	args := pgx.StrictNamedArgs{"user_id": userID}
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query recovery codes: %w", err)
+	}
+
+	recoveryCodes, err := pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[RecoveryCode])
+	if err != nil {
+		return fmt.Errorf("cannot collect recovery codes: %w", err)
+	}
+
+	*rcs = recoveryCodes
+
+	return nil
+}
+
+func (rc *RecoveryCode) Insert(
+	ctx context.Context,
+	conn pg.Conn,
+) error {
+	q := `
+INSERT INTO
+    recovery_codes (id, user_id, hashed_code, used_at, created_at)
+VALUES (
+    @id,
+    @user_id,
+    @hashed_code,
+    @used_at,
+    @created_at
+)
+`
+
+	args := pgx.StrictNamedArgs{
+		"id":          rc.ID,
+		"user_id":     rc.UserID,
+		"hashed_code": rc.HashedCode,
+		"used_at":     rc.UsedAt,
+		"created_at":  rc.CreatedAt,
+	}
+
+	_, err := conn.Exec(ctx, q, args)
+	return err
+}
+
+func (rc *RecoveryCode) MarkAsUsed(
+	ctx context.Context,
+	conn pg.Conn,
+) error {
+	q := `
+UPDATE
+    recovery_codes
+SET
+    used_at = @used_at
+WHERE
+    id = @id
+    AND used_at IS NULL
+`
+
+	now := time.Now()
+	args := pgx.StrictNamedArgs{
+		"id":      rc.ID,
+		"used_at": now,
+	}
+
+	result, err := conn.Exec(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot update recovery code: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("recovery code already used")
+	}
+
+	rc.UsedAt = &now
+
+	return nil
+}
+
+func DeleteUserRecoveryCodes(
+	ctx context.Context,
+	conn pg.Conn,
+	userID gid.GID,
+) error {
+	q := `
+DELETE FROM
+    recovery_codes
+WHERE
+    user_id = @user_id
+`
+
+	args := pgx.StrictNamedArgs{"user_id": userID}
+
+	_, err := conn.Exec(ctx, q, args)
+	return err
+}
@@ -0,0 +1,186 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package coredata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/getprobo/probo/pkg/gid"
+	"github.com/jackc/pgx/v5"
+	"go.gearno.de/kit/pg"
+)
+
+type (
+	SAMLConfiguration struct {
+		ID                  gid.GID      `db:"id"`
+		TenantID            gid.TenantID `db:"tenant_id"`
+		OrganizationID      gid.GID      `db:"organization_id"`
+		IdPEntityID         string       `db:"idp_entity_id"`
+		IdPMetadata         string       `db:"idp_metadata"`
+		EmailAttribute      string       `db:"email_attribute"`
+		FullNameAttribute   string       `db:"full_name_attribute"`
+		AllowedEmailDomains []string     `db:"allowed_email_domains"`
+		Enabled             bool         `db:"enabled"`
+		CreatedAt           time.Time    `db:"created_at"`
+		UpdatedAt           time.Time    `db:"updated_at"`
+	}
+
+	ErrSAMLConfigurationNotFound struct {
+		OrganizationID gid.GID
+	}
+)
+
+func (e ErrSAMLConfigurationNotFound) Error() string {
+	return fmt.Sprintf("saml configuration not found for organization %q", e.OrganizationID)
+}
+
+func (sc *SAMLConfiguration) LoadByOrganizationID(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+	organizationID gid.GID,
+) error {
+	q := `
+SELECT
+    tenant_id,
+    id,
+    organization_id,
+    idp_entity_id,
+    idp_metadata,
+    email_attribute,
+    full_name_attribute,
+    allowed_email_domains,
+    enabled,
+    created_at,
+    updated_at
+FROM
+    saml_configurations
+WHERE
+    %s
+    AND organization_id = @organization_id
+LIMIT 1;
+`
+
+	q = fmt.Sprintf(q, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{"organization_id": organizationID}
+	maps.Copy(args, scope.SQLArguments())
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query saml configuration: %w", err)
+	}
+
+	configuration, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[SAMLConfiguration])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &ErrSAMLConfigurationNotFound{OrganizationID: organizationID}
+		}
+
+		return fmt.Errorf("cannot collect saml configuration: %w", err)
+	}
+
+	*sc = configuration
+
+	return nil
+}
+
+func (sc *SAMLConfiguration) Upsert(
+	ctx context.Context,
+	conn pg.Conn,
+) error {
+	q := `
+INSERT INTO
+    saml_configurations (
+        tenant_id,
+        id,
+        organization_id,
+        idp_entity_id,
+        idp_metadata,
+        email_attribute,
+        full_name_attribute,
+        allowed_email_domains,
+        enabled,
+        created_at,
+        updated_at
+    )
+VALUES (
+    @tenant_id,
+    @id,
+    @organization_id,
+    @idp_entity_id,
+    @idp_metadata,
+    @email_attribute,
+    @full_name_attribute,
+    @allowed_email_domains,
+    @enabled,
+    @created_at,
+    @updated_at
+)
+ON CONFLICT (organization_id) DO UPDATE SET
+    idp_entity_id = EXCLUDED.idp_entity_id,
+    idp_metadata = EXCLUDED.idp_metadata,
+    email_attribute = EXCLUDED.email_attribute,
+    full_name_attribute = EXCLUDED.full_name_attribute,
+    allowed_email_domains = EXCLUDED.allowed_email_domains,
+    enabled = EXCLUDED.enabled,
+    updated_at = EXCLUDED.updated_at
+RETURNING
+    id,
+    created_at
+`
+
+	args := pgx.StrictNamedArgs{
+		"tenant_id":             sc.TenantID,
+		"id":                    sc.ID,
+		"organization_id":       sc.OrganizationID,
+		"idp_entity_id":         sc.IdPEntityID,
+		"idp_metadata":          sc.IdPMetadata,
+		"email_attribute":       sc.EmailAttribute,
+		"full_name_attribute":   sc.FullNameAttribute,
+		"allowed_email_domains": sc.AllowedEmailDomains,
+		"enabled":               sc.Enabled,
+		"created_at":            sc.CreatedAt,
+		"updated_at":            sc.UpdatedAt,
+	}
+
+	return conn.QueryRow(ctx, q, args).Scan(&sc.ID, &sc.CreatedAt)
+}
+
+func (sc *SAMLConfiguration) Delete(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+) error {
+	q := `
+DELETE FROM
+    saml_configurations
+WHERE
+    %s
+    AND id = @id
+`
+
+	q = fmt.Sprintf(q, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{"id": sc.ID}
+	maps.Copy(args, scope.SQLArguments())
+
+	_, err := conn.Exec(ctx, q, args)
+	return err
+}
@@ -0,0 +1,228 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package coredata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/getprobo/probo/pkg/gid"
+	"github.com/jackc/pgx/v5"
+	"go.gearno.de/kit/pg"
+)
+
+type (
+	SCIMConfiguration struct {
+		ID             gid.GID      `db:"id"`
+		TenantID       gid.TenantID `db:"tenant_id"`
+		OrganizationID gid.GID      `db:"organization_id"`
+		HashedToken    []byte       `db:"hashed_token"`
+		LastUsedAt     *time.Time   `db:"last_used_at"`
+		CreatedAt      time.Time    `db:"created_at"`
+		UpdatedAt      time.Time    `db:"updated_at"`
+	}
+
+	ErrSCIMConfigurationNotFound struct {
+		message string
+	}
+)
+
+func (e ErrSCIMConfigurationNotFound) Error() string {
+	return e.message
+}
+
+func (sc *SCIMConfiguration) LoadByOrganizationID(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+	organizationID gid.GID,
+) error {
+	q := `
+SELECT
+    tenant_id,
+    id,
+    organization_id,
+    hashed_token,
+    last_used_at,
+    created_at,
+    updated_at
+FROM
+    scim_configurations
+WHERE
+    %s
+    AND organization_id = @organization_id
+LIMIT 1;
+`
+
+	q = fmt.Sprintf(q, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{"organization_id": organizationID}
+	maps.Copy(args, scope.SQLArguments())
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query scim configuration: %w", err)
+	}
+
+	configuration, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[SCIMConfiguration])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &ErrSCIMConfigurationNotFound{
+				message: fmt.Sprintf("scim configuration not found for organization %q", organizationID),
+			}
+		}
+
+		return fmt.Errorf("cannot collect scim configuration: %w", err)
+	}
+
+	*sc = configuration
+
+	return nil
+}
+
+// LoadByHashedToken loads the configuration a bearer token belongs to. It
+// is not scoped as the tenant is only known once the token is resolved.
+func (sc *SCIMConfiguration) LoadByHashedToken(
+	ctx context.Context,
+	conn pg.Conn,
+	hashedToken []byte,
+) error {
+	q := `
+SELECT
+    tenant_id,
+    id,
+    organization_id,
+    hashed_token,
+    last_used_at,
+    created_at,
+    updated_at
+FROM
+    scim_configurations
+WHERE
+    hashed_token = @hashed_token
+LIMIT 1;
+`
+
+	args := pgx.StrictNamedArgs{"hashed_token": hashedToken}
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query scim configuration: %w", err)
+	}
+
+	configuration, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[SCIMConfiguration])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &ErrSCIMConfigurationNotFound{message: "scim configuration not found"}
+		}
+
+		return fmt.Errorf("cannot collect scim configuration: %w", err)
+	}
+
+	*sc = configuration
+
+	return nil
+}
+
+func (sc *SCIMConfiguration) Upsert(
+	ctx context.Context,
+	conn pg.Conn,
+) error {
+	q := `
+INSERT INTO
+    scim_configurations (
+        tenant_id,
+        id,
+        organization_id,
+        hashed_token,
+        last_used_at,
+        created_at,
+        updated_at
+    )
+VALUES (
+    @tenant_id,
+    @id,
+    @organization_id,
+    @hashed_token,
+    @last_used_at,
+    @created_at,
+    @updated_at
+)
+ON CONFLICT (organization_id) DO UPDATE SET
+    hashed_token = EXCLUDED.hashed_token,
+    last_used_at = EXCLUDED.last_used_at,
+    updated_at = EXCLUDED.updated_at
+RETURNING
+    id,
+    created_at
+`
+
+	args := pgx.StrictNamedArgs{
+		"tenant_id":       sc.TenantID,
+		"id":              sc.ID,
+		"organization_id": sc.OrganizationID,
+		"hashed_token":    sc.HashedToken,
+		"last_used_at":    sc.LastUsedAt,
+		"created_at":      sc.CreatedAt,
+		"updated_at":      sc.UpdatedAt,
+	}
+
+	return conn.QueryRow(ctx, q, args).Scan(&sc.ID, &sc.CreatedAt)
+}
+
+func (sc *SCIMConfiguration) UpdateLastUsedAt(
+	ctx context.Context,
+	conn pg.Conn,
+	lastUsedAt time.Time,
+) error {
+	q := `
+UPDATE scim_configurations SET last_used_at = @last_used_at WHERE id = @id
+`
+
+	args := pgx.StrictNamedArgs{"id": sc.ID, "last_used_at": lastUsedAt}
+
+	if _, err := conn.Exec(ctx, q, args); err != nil {
+		return err
+	}
+
+	sc.LastUsedAt = &lastUsedAt
+
+	return nil
+}
+
+func (sc *SCIMConfiguration) Delete(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+) error {
+	q := `
+DELETE FROM
+    scim_configurations
+WHERE
+    %s
+    AND id = @id
+`
+
+	q = fmt.Sprintf(q, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{"id": sc.ID}
+	maps.Copy(args, scope.SQLArguments())
+
+	_, err := conn.Exec(ctx, q, args)
+	return err
+}
@@ -0,0 +1,374 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package coredata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/getprobo/probo/pkg/gid"
+	"github.com/jackc/pgx/v5"
+	"go.gearno.de/kit/pg"
+)
+
+type (
+	SCIMGroup struct {
+		ID             gid.GID      `db:"id"`
+		TenantID       gid.TenantID `db:"tenant_id"`
+		OrganizationID gid.GID      `db:"organization_id"`
+		ExternalID     *string      `db:"external_id"`
+		DisplayName    string       `db:"display_name"`
+		CreatedAt      time.Time    `db:"created_at"`
+		UpdatedAt      time.Time    `db:"updated_at"`
+	}
+
+	SCIMGroups []*SCIMGroup
+
+	SCIMGroupFilter struct {
+		DisplayName *string
+		ExternalID  *string
+	}
+
+	ErrSCIMGroupNotFound struct {
+		message string
+	}
+)
+
+func (e ErrSCIMGroupNotFound) Error() string {
+	return e.message
+}
+
+func (f SCIMGroupFilter) SQLArguments() pgx.StrictNamedArgs {
+	return pgx.StrictNamedArgs{
+		"filter_display_name": f.DisplayName,
+		"filter_external_id":  f.ExternalID,
+	}
+}
+
+func (f SCIMGroupFilter) SQLFragment() string {
+	return `(@filter_display_name::text IS NULL OR lower(display_name) = lower(@filter_display_name::text))
+    AND (@filter_external_id::text IS NULL OR external_id = @filter_external_id::text)`
+}
+
+func (sg *SCIMGroup) LoadByID(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+	organizationID gid.GID,
+	scimGroupID gid.GID,
+) error {
+	q := `
+SELECT
+    tenant_id,
+    id,
+    organization_id,
+    external_id,
+    display_name,
+    created_at,
+    updated_at
+FROM
+    scim_groups
+WHERE
+    %s
+    AND organization_id = @organization_id
+    AND id = @scim_group_id
+LIMIT 1;
+`
+
+	q = fmt.Sprintf(q, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{"organization_id": organizationID, "scim_group_id": scimGroupID}
+	maps.Copy(args, scope.SQLArguments())
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query scim group: %w", err)
+	}
+
+	scimGroup, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[SCIMGroup])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &ErrSCIMGroupNotFound{message: fmt.Sprintf("scim group %q not found", scimGroupID)}
+		}
+
+		return fmt.Errorf("cannot collect scim group: %w", err)
+	}
+
+	*sg = scimGroup
+
+	return nil
+}
+
+func (sg SCIMGroup) Insert(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+) error {
+	q := `
+INSERT INTO
+    scim_groups (
+        tenant_id,
+        id,
+        organization_id,
+        external_id,
+        display_name,
+        created_at,
+        updated_at
+    )
+VALUES (
+    @tenant_id,
+    @id,
+    @organization_id,
+    @external_id,
+    @display_name,
+    @created_at,
+    @updated_at
+)
+`
+
+	args := pgx.StrictNamedArgs{
+		"tenant_id":       scope.GetTenantID(),
+		"id":              sg.ID,
+		"organization_id": sg.OrganizationID,
+		"external_id":     sg.ExternalID,
+		"display_name":    sg.DisplayName,
+		"created_at":      sg.CreatedAt,
+		"updated_at":      sg.UpdatedAt,
+	}
+
+	_, err := conn.Exec(ctx, q, args)
+	return err
+}
+
+func (sg SCIMGroup) Update(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+) error {
+	q := `
+UPDATE scim_groups SET
+    external_id = @external_id,
+    display_name = @display_name,
+    updated_at = @updated_at
+WHERE
+    %s
+    AND id = @id
+`
+
+	q = fmt.Sprintf(q, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{
+		"id":           sg.ID,
+		"external_id":  sg.ExternalID,
+		"display_name": sg.DisplayName,
+		"updated_at":   sg.UpdatedAt,
+	}
+	maps.Copy(args, scope.SQLArguments())
+
+	_, err := conn.Exec(ctx, q, args)
+	return err
+}
+
+func (sg SCIMGroup) Delete(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+) error {
+	q := `
+DELETE FROM scim_groups WHERE %s AND id = @id
+`
+
+	q = fmt.Sprintf(q, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{"id": sg.ID}
+	maps.Copy(args, scope.SQLArguments())
+
+	_, err := conn.Exec(ctx, q, args)
+	return err
+}
+
+// LoadMemberIDs returns the ids of the scim users member of the group.
+func (sg SCIMGroup) LoadMemberIDs(
+	ctx context.Context,
+	conn pg.Conn,
+) ([]gid.GID, error) {
+	q := `
+SELECT
+    scim_user_id
+FROM
+    scim_group_members
+WHERE
+    scim_group_id = @scim_group_id
+ORDER BY
+    created_at, scim_user_id
+`
+
+	rows, err := conn.Query(ctx, q, pgx.StrictNamedArgs{"scim_group_id": sg.ID})
+	if err != nil {
+		return nil, fmt.Errorf("cannot query scim group members: %w", err)
+	}
+
+	memberIDs, err := pgx.CollectRows(rows, pgx.RowTo[gid.GID])
+	if err != nil {
+		return nil, fmt.Errorf("cannot collect scim group members: %w", err)
+	}
+
+	return memberIDs, nil
+}
+
+// ReplaceMembers sets the members of the group. Ids which are not scim
+// users of the organization of the group are ignored.
+func (sg SCIMGroup) ReplaceMembers(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+	scimUserIDs []gid.GID,
+	createdAt time.Time,
+) error {
+	q := `
+DELETE FROM scim_group_members WHERE scim_group_id = @scim_group_id
+`
+
+	if _, err := conn.Exec(ctx, q, pgx.StrictNamedArgs{"scim_group_id": sg.ID}); err != nil {
+		return fmt.Errorf("cannot delete scim group members: %w", err)
+	}
+
+	if len(scimUserIDs) == 0 {
+		return nil
+	}
+
+	q = `
+INSERT INTO
+    scim_group_members (scim_group_id, scim_user_id, created_at)
+SELECT
+    @scim_group_id,
+    id,
+    @created_at
+FROM
+    scim_users
+WHERE
+    %s
+    AND organization_id = @organization_id
+    AND id = ANY(@scim_user_ids)
+ON CONFLICT DO NOTHING
+`
+
+	q = fmt.Sprintf(q, scope.SQLFragment())
+
+	ids := make([]string, len(scimUserIDs))
+	for i, id := range scimUserIDs {
+		ids[i] = id.String()
+	}
+
+	args := pgx.StrictNamedArgs{
+		"scim_group_id":   sg.ID,
+		"organization_id": sg.OrganizationID,
+		"scim_user_ids":   ids,
+		"created_at":      createdAt,
+	}
+	maps.Copy(args, scope.SQLArguments())
+
+	if _, err := conn.Exec(ctx, q, args); err != nil {
+		return fmt.Errorf("cannot insert scim group members: %w", err)
+	}
+
+	return nil
+}
+
+func (sg *SCIMGroups) LoadByOrganizationID(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+	organizationID gid.GID,
+	filter SCIMGroupFilter,
+	offset int,
+	limit int,
+) error {
+	q := `
+SELECT
+    tenant_id,
+    id,
+    organization_id,
+    external_id,
+    display_name,
+    created_at,
+    updated_at
+FROM
+    scim_groups
+WHERE
+    %s
+    AND organization_id = @organization_id
+    AND %s
+ORDER BY
+    created_at, id
+OFFSET @offset
+LIMIT @limit
+`
+
+	q = fmt.Sprintf(q, scope.SQLFragment(), filter.SQLFragment())
+
+	args := pgx.StrictNamedArgs{"organization_id": organizationID, "offset": offset, "limit": limit}
+	maps.Copy(args, filter.SQLArguments())
+	maps.Copy(args, scope.SQLArguments())
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query scim groups: %w", err)
+	}
+
+	scimGroups, err := pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[SCIMGroup])
+	if err != nil {
+		return fmt.Errorf("cannot collect scim groups: %w", err)
+	}
+
+	*sg = scimGroups
+
+	return nil
+}
+
+func (sg SCIMGroups) CountByOrganizationID(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+	organizationID gid.GID,
+	filter SCIMGroupFilter,
+) (int, error) {
+	q := `
+SELECT
+    COUNT(*)
+FROM
+    scim_groups
+WHERE
+    %s
+    AND organization_id = @organization_id
+    AND %s
+`
+
+	q = fmt.Sprintf(q, scope.SQLFragment(), filter.SQLFragment())
+
+	args := pgx.StrictNamedArgs{"organization_id": organizationID}
+	maps.Copy(args, filter.SQLArguments())
+	maps.Copy(args, scope.SQLArguments())
+
+	var count int
+	if err := conn.QueryRow(ctx, q, args).Scan(&count); err != nil {
+		return 0, fmt.Errorf("cannot count scim groups: %w", err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,362 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package coredata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/getprobo/probo/pkg/gid"
+	"github.com/jackc/pgx/v5"
+	"go.gearno.de/kit/pg"
+)
+
+type (
+	// SCIMUser links a user provisioned by the identity provider of an
+	// organization to its user account and its people record.
+	SCIMUser struct {
+		ID             gid.GID      `db:"id"`
+		TenantID       gid.TenantID `db:"tenant_id"`
+		OrganizationID gid.GID      `db:"organization_id"`
+		UserID         gid.GID      `db:"user_id"`
+		PeopleID       gid.GID      `db:"people_id"`
+		ExternalID     *string      `db:"external_id"`
+		UserName       string       `db:"user_name"`
+		Active         bool         `db:"active"`
+		CreatedAt      time.Time    `db:"created_at"`
+		UpdatedAt      time.Time    `db:"updated_at"`
+	}
+
+	SCIMUsers []*SCIMUser
+
+	SCIMUserFilter struct {
+		UserName   *string
+		ExternalID *string
+	}
+
+	ErrSCIMUserNotFound struct {
+		message string
+	}
+)
+
+func (e ErrSCIMUserNotFound) Error() string {
+	return e.message
+}
+
+func (f SCIMUserFilter) SQLArguments() pgx.StrictNamedArgs {
+	return pgx.StrictNamedArgs{
+		"filter_user_name":   f.UserName,
+		"filter_external_id": f.ExternalID,
+	}
+}
+
+func (f SCIMUserFilter) SQLFragment() string {
+	return `(@filter_user_name::text IS NULL OR lower(user_name) = lower(@filter_user_name::text))
+    AND (@filter_external_id::text IS NULL OR external_id = @filter_external_id::text)`
+}
+
+func (su *SCIMUser) LoadByID(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+	organizationID gid.GID,
+	scimUserID gid.GID,
+) error {
+	q := `
+SELECT
+    tenant_id,
+    id,
+    organization_id,
+    user_id,
+    people_id,
+    external_id,
+    user_name,
+    active,
+    created_at,
+    updated_at
+FROM
+    scim_users
+WHERE
+    %s
+    AND organization_id = @organization_id
+    AND id = @scim_user_id
+LIMIT 1;
+`
+
+	q = fmt.Sprintf(q, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{"organization_id": organizationID, "scim_user_id": scimUserID}
+	maps.Copy(args, scope.SQLArguments())
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query scim user: %w", err)
+	}
+
+	scimUser, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[SCIMUser])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &ErrSCIMUserNotFound{message: fmt.Sprintf("scim user %q not found", scimUserID)}
+		}
+
+		return fmt.Errorf("cannot collect scim user: %w", err)
+	}
+
+	*su = scimUser
+
+	return nil
+}
+
+func (su *SCIMUser) LoadByUserID(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+	organizationID gid.GID,
+	userID gid.GID,
+) error {
+	q := `
+SELECT
+    tenant_id,
+    id,
+    organization_id,
+    user_id,
+    people_id,
+    external_id,
+    user_name,
+    active,
+    created_at,
+    updated_at
+FROM
+    scim_users
+WHERE
+    %s
+    AND organization_id = @organization_id
+    AND user_id = @user_id
+LIMIT 1;
+`
+
+	q = fmt.Sprintf(q, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{"organization_id": organizationID, "user_id": userID}
+	maps.Copy(args, scope.SQLArguments())
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query scim user: %w", err)
+	}
+
+	scimUser, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[SCIMUser])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &ErrSCIMUserNotFound{message: fmt.Sprintf("scim user not found for user %q", userID)}
+		}
+
+		return fmt.Errorf("cannot collect scim user: %w", err)
+	}
+
+	*su = scimUser
+
+	return nil
+}
+
+func (su SCIMUser) Insert(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+) error {
+	q := `
+INSERT INTO
+    scim_users (
+        tenant_id,
+        id,
+        organization_id,
+        user_id,
+        people_id,
+        external_id,
+        user_name,
+        active,
+        created_at,
+        updated_at
+    )
+VALUES (
+    @tenant_id,
+    @id,
+    @organization_id,
+    @user_id,
+    @people_id,
+    @external_id,
+    @user_name,
+    @active,
+    @created_at,
+    @updated_at
+)
+`
+
+	args := pgx.StrictNamedArgs{
+		"tenant_id":       scope.GetTenantID(),
+		"id":              su.ID,
+		"organization_id": su.OrganizationID,
+		"user_id":         su.UserID,
+		"people_id":       su.PeopleID,
+		"external_id":     su.ExternalID,
+		"user_name":       su.UserName,
+		"active":          su.Active,
+		"created_at":      su.CreatedAt,
+		"updated_at":      su.UpdatedAt,
+	}
+
+	_, err := conn.Exec(ctx, q, args)
+	return err
+}
+
+func (su SCIMUser) Update(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+) error {
+	q := `
+UPDATE scim_users SET
+    user_id = @user_id,
+    external_id = @external_id,
+    user_name = @user_name,
+    active = @active,
+    updated_at = @updated_at
+WHERE
+    %s
+    AND id = @id
+`
+
+	q = fmt.Sprintf(q, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{
+		"id":          su.ID,
+		"user_id":     su.UserID,
+		"external_id": su.ExternalID,
+		"user_name":   su.UserName,
+		"active":      su.Active,
+		"updated_at":  su.UpdatedAt,
+	}
+	maps.Copy(args, scope.SQLArguments())
+
+	_, err := conn.Exec(ctx, q, args)
+	return err
+}
+
+func (su SCIMUser) Delete(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+) error {
+	q := `
+DELETE FROM scim_users WHERE %s AND id = @id
+`
+
+	q = fmt.Sprintf(q, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{"id": su.ID}
+	maps.Copy(args, scope.SQLArguments())
+
+	_, err := conn.Exec(ctx, q, args)
+	return err
+}
+
+func (su *SCIMUsers) LoadByOrganizationID(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+	organizationID gid.GID,
+	filter SCIMUserFilter,
+	offset int,
+	limit int,
+) error {
+	q := `
+SELECT
+    tenant_id,
+    id,
+    organization_id,
+    user_id,
+    people_id,
+    external_id,
+    user_name,
+    active,
+    created_at,
+    updated_at
+FROM
+    scim_users
+WHERE
+    %s
+    AND organization_id = @organization_id
+    AND %s
+ORDER BY
+    created_at, id
+OFFSET @offset
+LIMIT @limit
+`
+
+	q = fmt.Sprintf(q, scope.SQLFragment(), filter.SQLFragment())
+
+	args := pgx.StrictNamedArgs{"organization_id": organizationID, "offset": offset, "limit": limit}
+	maps.Copy(args, filter.SQLArguments())
+	maps.Copy(args, scope.SQLArguments())
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query scim users: %w", err)
+	}
+
+	scimUsers, err := pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[SCIMUser])
+	if err != nil {
+		return fmt.Errorf("cannot collect scim users: %w", err)
+	}
+
+	*su = scimUsers
+
+	return nil
+}
+
+func (su SCIMUsers) CountByOrganizationID(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+	organizationID gid.GID,
+	filter SCIMUserFilter,
+) (int, error) {
+	q := `
+SELECT
+    COUNT(*)
+FROM
+    scim_users
+WHERE
+    %s
+    AND organization_id = @organization_id
+    AND %s
+`
+
+	q = fmt.Sprintf(q, scope.SQLFragment(), filter.SQLFragment())
+
+	args := pgx.StrictNamedArgs{"organization_id": organizationID}
+	maps.Copy(args, filter.SQLArguments())
+	maps.Copy(args, scope.SQLArguments())
+
+	var count int
+	if err := conn.QueryRow(ctx, q, args).Scan(&count); err != nil {
+		return 0, fmt.Errorf("cannot count scim users: %w", err)
+	}
+
+	return count, nil
+}
@@ -27,14 +27,19 @@ import (
 
 type (
 	Session struct {
-		ID        gid.GID     `db:"id"`
-		UserID    gid.GID     `db:"user_id"`
-		Data      SessionData `db:"data"`
-		ExpiredAt time.Time   `db:"expired_at"`
-		CreatedAt time.Time   `db:"created_at"`
-		UpdatedAt time.Time   `db:"updated_at"`
+		ID             gid.GID     `db:"id"`
+		UserID         gid.GID     `db:"user_id"`
+		Data           SessionData `db:"data"`
+		UserAgent      string      `db:"user_agent"`
+		IPAddress      string      `db:"ip_address"`
+		LastActivityAt time.Time   `db:"last_activity_at"`
+		ExpiredAt      time.Time   `db:"expired_at"`
+		CreatedAt      time.Time   `db:"created_at"`
+		UpdatedAt      time.Time   `db:"updated_at"`
 	}
 
+	Sessions []*Session
+
 	SessionData struct{}
 )
 
@@ -56,7 +61,10 @@ func (s *Session) LoadByID(
 SELECT
     id,
     user_id,
-	data,
+    data,
+    user_agent,
+    ip_address,
+    last_activity_at,
     expired_at,
     created_at,
     updated_at
@@ -89,11 +97,14 @@ func (s *Session) Insert(
 ) error {
 	q := `
 INSERT INTO
-    sessions (id, user_id, data, expired_at, created_at, updated_at)
+    sessions (id, user_id, data, user_agent, ip_address, last_activity_at, expired_at, created_at, updated_at)
 VALUES (
     @session_id,
     @user_id,
     @data,
+    @user_agent,
+    @ip_address,
+    @last_activity_at,
     @expired_at,
     @created_at,
     @updated_at
@@ -101,12 +112,15 @@ VALUES (
 `
 
 	args := pgx.StrictNamedArgs{
-		"session_id": s.ID,
-		"user_id":    s.UserID,
-		"data":       s.Data,
-		"expired_at": s.ExpiredAt,
-		"created_at": s.CreatedAt,
-		"updated_at": s.UpdatedAt,
+		"session_id":       s.ID,
+		"user_id":          s.UserID,
+		"data":             s.Data,
+		"user_agent":       s.UserAgent,
+		"ip_address":       s.IPAddress,
+		"last_activity_at": s.LastActivityAt,
+		"expired_at":       s.ExpiredAt,
+		"created_at":       s.CreatedAt,
+		"updated_at":       s.UpdatedAt,
 	}
 
 	_, err := conn.Exec(ctx, q, args)
@@ -122,16 +136,22 @@ UPDATE sessions
 SET
     expired_at = @expired_at,
     updated_at = @updated_at,
+    user_agent = @user_agent,
+    ip_address = @ip_address,
+    last_activity_at = @last_activity_at,
     data = @data
 WHERE
     id = @session_id
 `
 
 	args := pgx.StrictNamedArgs{
-		"session_id": s.ID,
-		"data":       s.Data,
-		"expired_at": s.ExpiredAt,
-		"updated_at": s.UpdatedAt,
+		"session_id":       s.ID,
+		"data":             s.Data,
+		"user_agent":       s.UserAgent,
+		"ip_address":       s.IPAddress,
+		"last_activity_at": s.LastActivityAt,
+		"expired_at":       s.ExpiredAt,
+		"updated_at":       s.UpdatedAt,
 	}
 
 	_, err := conn.Exec(ctx, q, args)
@@ -155,3 +175,116 @@ WHERE
 	_, err := conn.Exec(ctx, q, args)
 	return err
 }
+
+func DeleteUserSessions(
+	ctx context.Context,
+	conn pg.Conn,
+	userID gid.GID,
+) error {
+	q := `
+DELETE FROM
+    sessions
+WHERE
+    user_id = @user_id
+`
+
+	args := pgx.StrictNamedArgs{"user_id": userID}
+
+	_, err := conn.Exec(ctx, q, args)
+	return err
+}
+
+func (s *Sessions) LoadActiveByUserID(
+	ctx context.Context,
+	conn pg.Conn,
+	userID gid.GID,
+	now time.Time,
+) error {
+	q := `
+SELECT
+    id,
+    user_id,
+    data,
+    user_agent,
+    ip_address,
+    last_activity_at,
+    expired_at,
+    created_at,
+    updated_at
+FROM
+    sessions
+WHERE
+    user_id = @user_id
+    AND expired_at > @now
+ORDER BY
+    last_activity_at DESC
+`
+
+	args := pgx.StrictNamedArgs{"user_id": userID, "now": now}
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query sessions: %w", err)
+	}
+
+	sessions, err := pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[Session])
+	if err != nil {
+		return fmt.Errorf("cannot collect sessions: %w", err)
+	}
+
+	*s = sessions
+
+	return nil
+}
+
+// DeleteUserSession deletes a session of the user and reports whether it
+// existed.
+func DeleteUserSession(
+	ctx context.Context,
+	conn pg.Conn,
+	userID gid.GID,
+	sessionID gid.GID,
+) (bool, error) {
+	q := `
+DELETE FROM
+    sessions
+WHERE
+    id = @session_id
+    AND user_id = @user_id
+`
+
+	args := pgx.StrictNamedArgs{"session_id": sessionID, "user_id": userID}
+
+	result, err := conn.Exec(ctx, q, args)
+	if err != nil {
+		return false, err
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+// DeleteOtherUserSessions deletes the sessions of the user but the given
+// one and returns how many were deleted.
+func DeleteOtherUserSessions(
+	ctx context.Context,
+	conn pg.Conn,
+	userID gid.GID,
+	sessionID gid.GID,
+) (int64, error) {
+	q := `
+DELETE FROM
+    sessions
+WHERE
+    user_id = @user_id
+    AND id != @session_id
+`
+
+	args := pgx.StrictNamedArgs{"user_id": userID, "session_id": sessionID}
+
+	result, err := conn.Exec(ctx, q, args)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,232 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package coredata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.gearno.de/kit/pg"
+)
+
+type (
+	// SignInThrottle tracks the failed sign-in attempts for a key, a key
+	// being an account, a client IP address or the codes of a
+	// multi-factor authentication challenge.
+	SignInThrottle struct {
+		Key            string     `db:"key"`
+		FailedAttempts int        `db:"failed_attempts"`
+		LastFailedAt   time.Time  `db:"last_failed_at"`
+		LockedUntil    *time.Time `db:"locked_until"`
+		CreatedAt      time.Time  `db:"created_at"`
+		UpdatedAt      time.Time  `db:"updated_at"`
+	}
+
+	ErrSignInThrottleNotFound struct {
+		message string
+	}
+)
+
+func (e ErrSignInThrottleNotFound) Error() string {
+	return e.message
+}
+
+func (st *SignInThrottle) LoadByKey(
+	ctx context.Context,
+	conn pg.Conn,
+	key string,
+) error {
+	q := `
+SELECT
+    key,
+    failed_attempts,
+    last_failed_at,
+    locked_until,
+    created_at,
+    updated_at
+FROM
+    sign_in_throttles
+WHERE
+    key = @key
+LIMIT 1;
+`
+
+	args := pgx.StrictNamedArgs{"key": key}
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query sign in throttle: %w", err)
+	}
+
+	throttle, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[SignInThrottle])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &ErrSignInThrottleNotFound{message: "sign in throttle not found"}
+		}
+
+		return fmt.Errorf("cannot collect sign in throttle: %w", err)
+	}
+
+	*st = throttle
+
+	return nil
+}
+
+// RecordFailure atomically counts a failed attempt for the key. Attempts
+// which happened before resetBefore are forgotten, so the counter starts
+// over once the key stayed quiet long enough.
+func (st *SignInThrottle) RecordFailure(
+	ctx context.Context,
+	conn pg.Conn,
+	key string,
+	now time.Time,
+	resetBefore time.Time,
+) error {
+	q := `
+INSERT INTO sign_in_throttles (
+    key,
+    failed_attempts,
+    last_failed_at,
+    locked_until,
+    created_at,
+    updated_at
+)
+VALUES (
+    @key,
+    1,
+    @now,
+    NULL,
+    @now,
+    @now
+)
+ON CONFLICT (key) DO UPDATE SET
+    failed_attempts = CASE
+        WHEN sign_in_throttles.last_failed_at < @reset_before THEN 1
+        ELSE sign_in_throttles.failed_attempts + 1
+    END,
+    last_failed_at = @now,
+    updated_at = @now
+RETURNING
+    key,
+    failed_attempts,
+    last_failed_at,
+    locked_until,
+    created_at,
+    updated_at;
+`
+
+	args := pgx.StrictNamedArgs{
+		"key":          key,
+		"now":          now,
+		"reset_before": resetBefore,
+	}
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot record sign in failure: %w", err)
+	}
+
+	throttle, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[SignInThrottle])
+	if err != nil {
+		return fmt.Errorf("cannot collect sign in throttle: %w", err)
+	}
+
+	*st = throttle
+
+	return nil
+}
+
+// Lock locks the key until the given time and resets its failed attempts
+// counter, so the backoff starts over once the lock expires.
+func (st *SignInThrottle) Lock(
+	ctx context.Context,
+	conn pg.Conn,
+	until time.Time,
+	now time.Time,
+) error {
+	q := `
+UPDATE sign_in_throttles
+SET
+    failed_attempts = 0,
+    locked_until = @locked_until,
+    updated_at = @updated_at
+WHERE
+    key = @key
+`
+
+	args := pgx.StrictNamedArgs{
+		"key":          st.Key,
+		"locked_until": until,
+		"updated_at":   now,
+	}
+
+	if _, err := conn.Exec(ctx, q, args); err != nil {
+		return fmt.Errorf("cannot lock sign in throttle: %w", err)
+	}
+
+	st.FailedAttempts = 0
+	st.LockedUntil = &until
+	st.UpdatedAt = now
+
+	return nil
+}
+
+func DeleteSignInThrottle(
+	ctx context.Context,
+	conn pg.Conn,
+	key string,
+) error {
+	q := `
+DELETE FROM sign_in_throttles
+WHERE key = @key
+`
+
+	args := pgx.StrictNamedArgs{"key": key}
+
+	_, err := conn.Exec(ctx, q, args)
+	return err
+}
+
+// DeleteExpiredSignInThrottles deletes the throttles which did not fail
+// since lastFailedBefore and are not locked anymore, their attempts would
+// be forgotten anyway.
+func DeleteExpiredSignInThrottles(
+	ctx context.Context,
+	conn pg.Conn,
+	lastFailedBefore time.Time,
+	now time.Time,
+) error {
+	q := `
+DELETE FROM sign_in_throttles
+WHERE
+    last_failed_at < @last_failed_before
+    AND (locked_until IS NULL OR locked_until < @now)
+`
+
+	args := pgx.StrictNamedArgs{
+		"last_failed_before": lastFailedBefore,
+		"now":                now,
+	}
+
+	_, err := conn.Exec(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot delete expired sign in throttles: %w", err)
+	}
+
+	return nil
+}
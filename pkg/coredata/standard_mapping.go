@@ -0,0 +1,223 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package coredata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/getprobo/probo/pkg/gid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.gearno.de/kit/pg"
+)
+
+type (
+	// StandardMapping declares that a standard reference of a framework
+	// covers the same requirement as a standard reference of another
+	// framework. Mappings are symmetric and stored with the smallest
+	// framework ID, then reference, as source.
+	StandardMapping struct {
+		ID                gid.GID   `db:"id"`
+		OrganizationID    gid.GID   `db:"organization_id"`
+		SourceFrameworkID gid.GID   `db:"source_framework_id"`
+		SourceStandard    string    `db:"source_standard"`
+		TargetFrameworkID gid.GID   `db:"target_framework_id"`
+		TargetStandard    string    `db:"target_standard"`
+		CreatedAt         time.Time `db:"created_at"`
+	}
+
+	StandardMappings []*StandardMapping
+
+	ErrStandardMappingNotFound struct {
+		message string
+	}
+
+	ErrStandardMappingAlreadyExists struct {
+		message string
+	}
+)
+
+func (e ErrStandardMappingNotFound) Error() string {
+	return e.message
+}
+
+func (e ErrStandardMappingAlreadyExists) Error() string {
+	return e.message
+}
+
+func (sm *StandardMapping) LoadByID(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+	standardMappingID gid.GID,
+) error {
+	q := `
+SELECT
+    id,
+    organization_id,
+    source_framework_id,
+    source_standard,
+    target_framework_id,
+    target_standard,
+    created_at
+FROM
+    standard_mappings
+WHERE
+    %s
+    AND id = @standard_mapping_id
+LIMIT 1;
+`
+
+	q = fmt.Sprintf(q, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{"standard_mapping_id": standardMappingID}
+	maps.Copy(args, scope.SQLArguments())
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query standard mapping: %w", err)
+	}
+
+	standardMapping, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[StandardMapping])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &ErrStandardMappingNotFound{message: fmt.Sprintf("standard mapping %q not found", standardMappingID)}
+		}
+
+		return fmt.Errorf("cannot collect standard mapping: %w", err)
+	}
+
+	*sm = standardMapping
+
+	return nil
+}
+
+func (sm StandardMapping) Insert(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+) error {
+	q := `
+INSERT INTO
+    standard_mappings (
+        tenant_id,
+        id,
+        organization_id,
+        source_framework_id,
+        source_standard,
+        target_framework_id,
+        target_standard,
+        created_at
+    )
+VALUES (
+    @tenant_id,
+    @id,
+    @organization_id,
+    @source_framework_id,
+    @source_standard,
+    @target_framework_id,
+    @target_standard,
+    @created_at
+)
+`
+
+	args := pgx.StrictNamedArgs{
+		"tenant_id":           scope.GetTenantID(),
+		"id":                  sm.ID,
+		"organization_id":     sm.OrganizationID,
+		"source_framework_id": sm.SourceFrameworkID,
+		"source_standard":     sm.SourceStandard,
+		"target_framework_id": sm.TargetFrameworkID,
+		"target_standard":     sm.TargetStandard,
+		"created_at":          sm.CreatedAt,
+	}
+
+	_, err := conn.Exec(ctx, q, args)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return &ErrStandardMappingAlreadyExists{
+				message: fmt.Sprintf("%q is already mapped to %q", sm.SourceStandard, sm.TargetStandard),
+			}
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (sm StandardMapping) Delete(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+) error {
+	q := `DELETE FROM standard_mappings WHERE %s AND id = @standard_mapping_id`
+	q = fmt.Sprintf(q, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{"standard_mapping_id": sm.ID}
+	maps.Copy(args, scope.SQLArguments())
+
+	_, err := conn.Exec(ctx, q, args)
+	return err
+}
+
+func (sm *StandardMappings) LoadByOrganizationID(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+	organizationID gid.GID,
+) error {
+	q := `
+SELECT
+    id,
+    organization_id,
+    source_framework_id,
+    source_standard,
+    target_framework_id,
+    target_standard,
+    created_at
+FROM
+    standard_mappings
+WHERE
+    %s
+    AND organization_id = @organization_id
+ORDER BY
+    source_framework_id, source_standard, target_framework_id, target_standard
+`
+
+	q = fmt.Sprintf(q, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{"organization_id": organizationID}
+	maps.Copy(args, scope.SQLArguments())
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query standard mappings: %w", err)
+	}
+
+	standardMappings, err := pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[StandardMapping])
+	if err != nil {
+		return fmt.Errorf("cannot collect standard mappings: %w", err)
+	}
+
+	*sm = standardMappings
+
+	return nil
+}
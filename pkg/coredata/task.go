@@ -29,17 +29,20 @@ import (
 
 type (
 	Task struct {
-		ID           gid.GID        `db:"id"`
-		ControlID    gid.GID        `db:"control_id"`
-		Name         string         `db:"name"`
-		Description  string         `db:"description"`
-		State        TaskState      `db:"state"`
-		ContentRef   string         `db:"content_ref"`
-		CreatedAt    time.Time      `db:"created_at"`
-		UpdatedAt    time.Time      `db:"updated_at"`
-		Version      int            `db:"version"`
-		AssignedTo   *gid.GID       `db:"assigned_to"`
-		TimeEstimate *time.Duration `db:"time_estimate"`
+		ID          gid.GID   `db:"id"`
+		ControlID   gid.GID   `db:"control_id"`
+		Name        string    `db:"name"`
+		Description string    `db:"description"`
+		State       TaskState `db:"state"`
+		ContentRef  string    `db:"content_ref"`
+		// ContentDigest is the digest of the template content the task
+		// was last imported or upgraded from.
+		ContentDigest *string        `db:"content_digest"`
+		CreatedAt     time.Time      `db:"created_at"`
+		UpdatedAt     time.Time      `db:"updated_at"`
+		Version       int            `db:"version"`
+		AssignedTo    *gid.GID       `db:"assigned_to"`
+		TimeEstimate  *time.Duration `db:"time_estimate"`
 	}
 
 	Tasks []*Task
@@ -78,6 +81,7 @@ SELECT
     state,
 	assigned_to,
     content_ref,
+    content_digest,
     created_at,
     updated_at,
     version
@@ -122,6 +126,7 @@ INSERT INTO tasks (
     control_id,
     description,
     content_ref,
+    content_digest,
     created_at,
     updated_at,
     version,
@@ -136,6 +141,7 @@ VALUES (
     @control_id,
     @description,
     @content_ref,
+    @content_digest,
     @created_at,
     @updated_at,
     @version,
@@ -146,18 +152,19 @@ VALUES (
 `
 
 	args := pgx.StrictNamedArgs{
-		"tenant_id":     scope.GetTenantID(),
-		"task_id":       t.ID,
-		"control_id":    t.ControlID,
-		"name":          t.Name,
-		"description":   t.Description,
-		"content_ref":   t.ContentRef,
-		"created_at":    t.CreatedAt,
-		"updated_at":    t.UpdatedAt,
-		"version":       t.Version,
-		"state":         t.State,
-		"time_estimate": t.TimeEstimate,
-		"assigned_to":   t.AssignedTo,
+		"tenant_id":      scope.GetTenantID(),
+		"task_id":        t.ID,
+		"control_id":     t.ControlID,
+		"name":           t.Name,
+		"description":    t.Description,
+		"content_ref":    t.ContentRef,
+		"content_digest": t.ContentDigest,
+		"created_at":     t.CreatedAt,
+		"updated_at":     t.UpdatedAt,
+		"version":        t.Version,
+		"state":          t.State,
+		"time_estimate":  t.TimeEstimate,
+		"assigned_to":    t.AssignedTo,
 	}
 	_, err := conn.Exec(ctx, q, args)
 	return err
@@ -179,6 +186,7 @@ SELECT
     state,
 	time_estimate,
     content_ref,
+    content_digest,
     created_at,
     updated_at,
     version,
@@ -212,6 +220,108 @@ WHERE
 	return nil
 }
 
+// LoadAllByFrameworkID loads every task of the controls of the
+// framework, without pagination, in creation order.
+func (t *Tasks) LoadAllByFrameworkID(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+	frameworkID gid.GID,
+) error {
+	q := `
+SELECT
+    id,
+    control_id,
+    name,
+    description,
+    state,
+    time_estimate,
+    content_ref,
+    content_digest,
+    created_at,
+    updated_at,
+    version,
+    assigned_to
+FROM
+    tasks
+WHERE
+    %s
+    AND control_id IN (
+        SELECT id FROM controls WHERE framework_id = @framework_id
+    )
+ORDER BY
+    created_at, id
+`
+
+	q = fmt.Sprintf(q, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{"framework_id": frameworkID}
+	maps.Copy(args, scope.SQLArguments())
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query tasks: %w", err)
+	}
+
+	tasks, err := pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[Task])
+	if err != nil {
+		return fmt.Errorf("cannot collect tasks: %w", err)
+	}
+
+	*t = tasks
+
+	return nil
+}
+
+func (t *Tasks) LoadByAssignedToID(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+	peopleID gid.GID,
+) error {
+	q := `
+SELECT
+    id,
+    control_id,
+    name,
+    description,
+    state,
+    time_estimate,
+    content_ref,
+    content_digest,
+    created_at,
+    updated_at,
+    version,
+    assigned_to
+FROM
+    tasks
+WHERE
+    %s
+    AND assigned_to = @assigned_to
+ORDER BY
+    created_at
+`
+
+	q = fmt.Sprintf(q, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{"assigned_to": peopleID}
+	maps.Copy(args, scope.SQLArguments())
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query tasks: %w", err)
+	}
+
+	tasks, err := pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[Task])
+	if err != nil {
+		return fmt.Errorf("cannot collect tasks: %w", err)
+	}
+
+	*t = tasks
+
+	return nil
+}
+
 func (t *Task) Update(
 	ctx context.Context,
 	conn pg.Conn,
@@ -252,6 +362,48 @@ RETURNING
 	return err
 }
 
+// UpdateTemplateContent overwrites the template provided fields of the
+// task with the ones currently set on t, leaving its state and assignment
+// untouched.
+func (t *Task) UpdateTemplateContent(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+) error {
+	q := `
+UPDATE tasks
+SET
+    name = @name,
+    description = @description,
+    time_estimate = @time_estimate,
+    content_ref = @content_ref,
+    content_digest = @content_digest,
+    updated_at = @updated_at,
+    version = version + 1
+WHERE
+    %s
+    AND id = @task_id
+    AND version = @expected_version
+RETURNING
+    version;
+`
+	q = fmt.Sprintf(q, scope.SQLFragment())
+
+	args := pgx.StrictNamedArgs{
+		"task_id":          t.ID,
+		"expected_version": t.Version,
+		"name":             t.Name,
+		"description":      t.Description,
+		"time_estimate":    t.TimeEstimate,
+		"content_ref":      t.ContentRef,
+		"content_digest":   t.ContentDigest,
+		"updated_at":       t.UpdatedAt,
+	}
+	maps.Copy(args, scope.SQLArguments())
+
+	return conn.QueryRow(ctx, q, args).Scan(&t.Version)
+}
+
 func (t *Task) AssignTo(
 	ctx context.Context,
 	conn pg.Conn,
@@ -0,0 +1,140 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package coredata
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/getprobo/probo/pkg/gid"
+	"github.com/getprobo/probo/pkg/page"
+	"github.com/jackc/pgx/v5"
+	"go.gearno.de/kit/pg"
+)
+
+type (
+	// TaskStateTransition records a change of the state of a task:
+	// who made it, when and why.
+	TaskStateTransition struct {
+		ID          gid.GID   `db:"id"`
+		TaskID      gid.GID   `db:"task_id"`
+		FromState   TaskState `db:"from_state"`
+		ToState     TaskState `db:"to_state"`
+		Reason      *string   `db:"reason"`
+		ChangedByID *gid.GID  `db:"changed_by_id"`
+		CreatedAt   time.Time `db:"created_at"`
+	}
+
+	TaskStateTransitions []*TaskStateTransition
+)
+
+func (t TaskStateTransition) CursorKey(orderBy StateTransitionOrderField) page.CursorKey {
+	switch orderBy {
+	case StateTransitionOrderFieldCreatedAt:
+		return page.NewCursorKey(t.ID, t.CreatedAt)
+	}
+
+	panic(fmt.Sprintf("unsupported order by: %s", orderBy))
+}
+
+func (t TaskStateTransition) Insert(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+) error {
+	q := `
+INSERT INTO
+    task_state_transitions (
+        tenant_id,
+        id,
+        task_id,
+        from_state,
+        to_state,
+        reason,
+        changed_by_id,
+        created_at
+    )
+VALUES (
+    @tenant_id,
+    @task_state_transition_id,
+    @task_id,
+    @from_state,
+    @to_state,
+    @reason,
+    @changed_by_id,
+    @created_at
+)
+`
+
+	args := pgx.StrictNamedArgs{
+		"tenant_id":                scope.GetTenantID(),
+		"task_state_transition_id": t.ID,
+		"task_id":                  t.TaskID,
+		"from_state":               t.FromState,
+		"to_state":                 t.ToState,
+		"reason":                   t.Reason,
+		"changed_by_id":            t.ChangedByID,
+		"created_at":               t.CreatedAt,
+	}
+	_, err := conn.Exec(ctx, q, args)
+	return err
+}
+
+func (t *TaskStateTransitions) LoadByTaskID(
+	ctx context.Context,
+	conn pg.Conn,
+	scope Scoper,
+	taskID gid.GID,
+	cursor *page.Cursor[StateTransitionOrderField],
+) error {
+	q := `
+SELECT
+    id,
+    task_id,
+    from_state,
+    to_state,
+    reason,
+    changed_by_id,
+    created_at
+FROM
+    task_state_transitions
+WHERE
+    %s
+    AND task_id = @task_id
+    AND %s
+`
+
+	q = fmt.Sprintf(q, scope.SQLFragment(), cursor.SQLFragment())
+
+	args := pgx.StrictNamedArgs{"task_id": taskID}
+	maps.Copy(args, scope.SQLArguments())
+	maps.Copy(args, cursor.SQLArguments())
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query task state transitions: %w", err)
+	}
+
+	transitions, err := pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[TaskStateTransition])
+	if err != nil {
+		return fmt.Errorf("cannot collect task state transitions: %w", err)
+	}
+
+	*t = transitions
+
+	return nil
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package coredata
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/getprobo/probo/pkg/gid"
+	"github.com/jackc/pgx/v5"
+	"go.gearno.de/kit/pg"
+)
+
+type (
+	// UsedSAMLAssertion records an assertion consumed by a sign in until
+	// it expires, so it cannot be replayed.
+	UsedSAMLAssertion struct {
+		OrganizationID gid.GID   `db:"organization_id"`
+		AssertionID    string    `db:"assertion_id"`
+		ExpiresAt      time.Time `db:"expires_at"`
+		CreatedAt      time.Time `db:"created_at"`
+	}
+
+	ErrSAMLAssertionAlreadyUsed struct {
+		message string
+	}
+)
+
+func (e ErrSAMLAssertionAlreadyUsed) Error() string {
+	return e.message
+}
+
+// Insert records the assertion, failing with ErrSAMLAssertionAlreadyUsed
+// when it was already consumed.
+func (a UsedSAMLAssertion) Insert(
+	ctx context.Context,
+	conn pg.Conn,
+) error {
+	q := `
+INSERT INTO used_saml_assertions (
+    organization_id,
+    assertion_id,
+    expires_at,
+    created_at
+)
+VALUES (
+    @organization_id,
+    @assertion_id,
+    @expires_at,
+    @created_at
+)
+ON CONFLICT (organization_id, assertion_id) DO NOTHING
+`
+
+	args := pgx.StrictNamedArgs{
+		"organization_id": a.OrganizationID,
+		"assertion_id":    a.AssertionID,
+		"expires_at":      a.ExpiresAt,
+		"created_at":      a.CreatedAt,
+	}
+
+	result, err := conn.Exec(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot insert used saml assertion: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return &ErrSAMLAssertionAlreadyUsed{message: fmt.Sprintf("saml assertion %q was already used", a.AssertionID)}
+	}
+
+	return nil
+}
+
+func DeleteExpiredUsedSAMLAssertions(
+	ctx context.Context,
+	conn pg.Conn,
+	now time.Time,
+) error {
+	q := `
+DELETE FROM used_saml_assertions
+WHERE expires_at < @now
+`
+
+	args := pgx.StrictNamedArgs{"now": now}
+
+	_, err := conn.Exec(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot delete expired used saml assertions: %w", err)
+	}
+
+	return nil
+}
@@ -31,13 +31,15 @@ import (
 
 type (
 	User struct {
-		ID                   gid.GID   `db:"id"`
-		EmailAddress         string    `db:"email_address"`
-		HashedPassword       []byte    `db:"hashed_password"`
-		FullName             string    `db:"fullname"`
-		EmailAddressVerified bool      `db:"email_address_verified"`
-		CreatedAt            time.Time `db:"created_at"`
-		UpdatedAt            time.Time `db:"updated_at"`
+		ID                   gid.GID    `db:"id"`
+		EmailAddress         string     `db:"email_address"`
+		HashedPassword       []byte     `db:"hashed_password"`
+		FullName             string     `db:"fullname"`
+		EmailAddressVerified bool       `db:"email_address_verified"`
+		TOTPSecret           *string    `db:"totp_secret"`
+		TOTPConfirmedAt      *time.Time `db:"totp_confirmed_at"`
+		CreatedAt            time.Time  `db:"created_at"`
+		UpdatedAt            time.Time  `db:"updated_at"`
 	}
 
 	Users []*User
@@ -49,6 +51,10 @@ type (
 	ErrUserAlreadyExists struct {
 		message string
 	}
+
+	ErrTOTPCounterAlreadyUsed struct {
+		message string
+	}
 )
 
 func (e ErrUserNotFound) Error() string {
@@ -59,6 +65,15 @@ func (e ErrUserAlreadyExists) Error() string {
 	return e.message
 }
 
+func (e ErrTOTPCounterAlreadyUsed) Error() string {
+	return e.message
+}
+
+// MFAEnabled reports whether the user completed a TOTP enrollment.
+func (u User) MFAEnabled() bool {
+	return u.TOTPSecret != nil && u.TOTPConfirmedAt != nil
+}
+
 func (u User) CursorKey(orderBy UserOrderField) page.CursorKey {
 	switch orderBy {
 	case UserOrderFieldCreatedAt:
@@ -81,6 +96,8 @@ SELECT
     hashed_password,
     email_address_verified,
     fullname,
+    totp_secret,
+    totp_confirmed_at,
 	created_at,
 	updated_at
 FROM
@@ -124,6 +141,8 @@ SELECT
     hashed_password,
     email_address_verified,
     fullname,
+    totp_secret,
+    totp_confirmed_at,
     created_at,
     updated_at
 FROM
@@ -166,6 +185,8 @@ SELECT
     hashed_password,
 	email_address_verified,
     fullname,
+    totp_secret,
+    totp_confirmed_at,
     created_at,
     updated_at
 FROM
@@ -242,6 +263,21 @@ VALUES (
 	return nil
 }
 
+// Delete removes the user. Credentials, recovery codes, API tokens and
+// SCIM links are removed along through foreign key cascades, sessions and
+// memberships must be deleted beforehand.
+func (u User) Delete(
+	ctx context.Context,
+	conn pg.Conn,
+) error {
+	q := `
+DELETE FROM users WHERE id = @user_id
+`
+
+	_, err := conn.Exec(ctx, q, pgx.StrictNamedArgs{"user_id": u.ID})
+	return err
+}
+
 func (u *User) UpdateEmailVerification(
 	ctx context.Context,
 	conn pg.Conn,
@@ -273,3 +309,192 @@ WHERE
 
 	return nil
 }
+
+// UpdateEmailAddress changes the email address of the user, the new
+// address is considered verified as it can only be set once confirmed.
+func (u *User) UpdateEmailAddress(
+	ctx context.Context,
+	conn pg.Conn,
+	emailAddress string,
+) error {
+	q := `
+UPDATE
+    users
+SET
+    email_address = @email_address,
+    email_address_verified = true,
+    updated_at = @updated_at
+WHERE
+    id = @user_id
+`
+
+	args := pgx.StrictNamedArgs{
+		"user_id":       u.ID,
+		"email_address": emailAddress,
+		"updated_at":    time.Now(),
+	}
+
+	_, err := conn.Exec(ctx, q, args)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			if pgErr.Code == "23505" && strings.Contains(pgErr.ConstraintName, "email_address") {
+				return &ErrUserAlreadyExists{
+					message: fmt.Sprintf("user with email %s already exists", emailAddress),
+				}
+			}
+		}
+
+		return fmt.Errorf("cannot update user email address: %w", err)
+	}
+
+	u.EmailAddress = emailAddress
+	u.EmailAddressVerified = true
+	u.UpdatedAt = args["updated_at"].(time.Time)
+
+	return nil
+}
+
+func (u *User) UpdatePassword(
+	ctx context.Context,
+	conn pg.Conn,
+	hashedPassword []byte,
+) error {
+	q := `
+UPDATE
+    users
+SET
+    hashed_password = @hashed_password,
+    updated_at = @updated_at
+WHERE
+    id = @user_id
+`
+
+	args := pgx.StrictNamedArgs{
+		"user_id":         u.ID,
+		"hashed_password": hashedPassword,
+		"updated_at":      time.Now(),
+	}
+
+	_, err := conn.Exec(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot update user password: %w", err)
+	}
+
+	u.HashedPassword = hashedPassword
+	u.UpdatedAt = args["updated_at"].(time.Time)
+
+	return nil
+}
+
+// ReplaceHashedPassword replaces the password hash only if it did not
+// change since the user was loaded, so upgrading a hash cannot overwrite a
+// concurrent password change.
+func (u *User) ReplaceHashedPassword(
+	ctx context.Context,
+	conn pg.Conn,
+	hashedPassword []byte,
+) error {
+	q := `
+UPDATE
+    users
+SET
+    hashed_password = @hashed_password,
+    updated_at = @updated_at
+WHERE
+    id = @user_id
+    AND hashed_password = @previous_hashed_password
+`
+
+	args := pgx.StrictNamedArgs{
+		"user_id":                  u.ID,
+		"hashed_password":          hashedPassword,
+		"previous_hashed_password": u.HashedPassword,
+		"updated_at":               time.Now(),
+	}
+
+	result, err := conn.Exec(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot replace user hashed password: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return nil
+	}
+
+	u.HashedPassword = hashedPassword
+	u.UpdatedAt = args["updated_at"].(time.Time)
+
+	return nil
+}
+
+func (u *User) UpdateTOTP(
+	ctx context.Context,
+	conn pg.Conn,
+	secret *string,
+	confirmedAt *time.Time,
+) error {
+	q := `
+UPDATE
+    users
+SET
+    totp_secret = @totp_secret,
+    totp_confirmed_at = @totp_confirmed_at,
+    updated_at = @updated_at
+WHERE
+    id = @user_id
+`
+
+	args := pgx.StrictNamedArgs{
+		"user_id":           u.ID,
+		"totp_secret":       secret,
+		"totp_confirmed_at": confirmedAt,
+		"updated_at":        time.Now(),
+	}
+
+	_, err := conn.Exec(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot update user totp: %w", err)
+	}
+
+	u.TOTPSecret = secret
+	u.TOTPConfirmedAt = confirmedAt
+	u.UpdatedAt = args["updated_at"].(time.Time)
+
+	return nil
+}
+
+// UseTOTPCounter records the time step of a TOTP code accepted for the
+// user. It fails with ErrTOTPCounterAlreadyUsed when a code of the same or
+// a later time step was already accepted, so each code works only once.
+func (u User) UseTOTPCounter(
+	ctx context.Context,
+	conn pg.Conn,
+	counter int64,
+) error {
+	q := `
+UPDATE
+    users
+SET
+    totp_last_used_counter = @counter
+WHERE
+    id = @user_id
+    AND (totp_last_used_counter IS NULL OR totp_last_used_counter < @counter)
+`
+
+	args := pgx.StrictNamedArgs{
+		"user_id": u.ID,
+		"counter": counter,
+	}
+
+	result, err := conn.Exec(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot update user totp counter: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return &ErrTOTPCounterAlreadyUsed{message: "totp code was already used"}
+	}
+
+	return nil
+}
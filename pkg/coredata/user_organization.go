@@ -16,6 +16,8 @@ package coredata
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/getprobo/probo/pkg/gid"
@@ -25,24 +27,92 @@ import (
 
 type (
 	UserOrganization struct {
-		UserID         gid.GID   `db:"user_id"`
-		OrganizationID gid.GID   `db:"organization_id"`
-		CreatedAt      time.Time `db:"created_at"`
+		UserID         gid.GID        `db:"user_id"`
+		OrganizationID gid.GID        `db:"organization_id"`
+		Role           MembershipRole `db:"role"`
+		CreatedAt      time.Time      `db:"created_at"`
 	}
 
 	UserOrganizations []*UserOrganization
+
+	ErrMembershipNotFound struct {
+		message string
+	}
 )
 
+func (e ErrMembershipNotFound) Error() string {
+	return e.message
+}
+
 func (uo UserOrganization) Insert(
 	ctx context.Context,
 	conn pg.Conn,
 ) error {
 	q := `
-INSERT INTO users_organizations (user_id, organization_id, created_at)
-VALUES (@user_id, @organization_id, @created_at)
+INSERT INTO users_organizations (user_id, organization_id, role, created_at)
+VALUES (@user_id, @organization_id, @role, @created_at)
+`
+
+	_, err := conn.Exec(ctx, q, pgx.StrictNamedArgs{"user_id": uo.UserID, "organization_id": uo.OrganizationID, "role": uo.Role, "created_at": uo.CreatedAt})
+	return err
+}
+
+// InsertIfNotExists enrolls the user in the organization, doing nothing
+// when the user is already a member.
+func (uo UserOrganization) InsertIfNotExists(
+	ctx context.Context,
+	conn pg.Conn,
+) error {
+	q := `
+INSERT INTO users_organizations (user_id, organization_id, role, created_at)
+VALUES (@user_id, @organization_id, @role, @created_at)
+ON CONFLICT (user_id, organization_id) DO NOTHING
+`
+
+	_, err := conn.Exec(ctx, q, pgx.StrictNamedArgs{"user_id": uo.UserID, "organization_id": uo.OrganizationID, "role": uo.Role, "created_at": uo.CreatedAt})
+	return err
+}
+
+// LoadByUserIDAndOrganizationID loads the membership and locks it until
+// the end of the transaction.
+func (uo *UserOrganization) LoadByUserIDAndOrganizationID(
+	ctx context.Context,
+	conn pg.Conn,
+	userID gid.GID,
+	organizationID gid.GID,
+) error {
+	q := `
+SELECT user_id, organization_id, role, created_at
+FROM users_organizations
+WHERE user_id = @user_id AND organization_id = @organization_id
+FOR UPDATE
+`
+
+	rows, err := conn.Query(ctx, q, pgx.StrictNamedArgs{"user_id": userID, "organization_id": organizationID})
+	if err != nil {
+		return fmt.Errorf("cannot query membership: %w", err)
+	}
+
+	userOrganization, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[UserOrganization])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &ErrMembershipNotFound{message: fmt.Sprintf("user %q is not a member of organization %q", userID, organizationID)}
+		}
+
+		return fmt.Errorf("cannot collect membership: %w", err)
+	}
+
+	*uo = userOrganization
+
+	return nil
+}
+
+func (uo UserOrganization) UpdateRole(ctx context.Context, conn pg.Conn) error {
+	q := `
+UPDATE users_organizations SET role = @role WHERE user_id = @user_id AND organization_id = @organization_id
 `
 
-	_, err := conn.Exec(ctx, q, pgx.StrictNamedArgs{"user_id": uo.UserID, "organization_id": uo.OrganizationID, "created_at": uo.CreatedAt})
+	_, err := conn.Exec(ctx, q, pgx.StrictNamedArgs{"user_id": uo.UserID, "organization_id": uo.OrganizationID, "role": uo.Role})
 	return err
 }
 
@@ -55,13 +125,26 @@ DELETE FROM users_organizations WHERE user_id = @user_id AND organization_id = @
 	return err
 }
 
+func DeleteUserOrganizations(
+	ctx context.Context,
+	conn pg.Conn,
+	userID gid.GID,
+) error {
+	q := `
+DELETE FROM users_organizations WHERE user_id = @user_id
+`
+
+	_, err := conn.Exec(ctx, q, pgx.StrictNamedArgs{"user_id": userID})
+	return err
+}
+
 func (uo *UserOrganizations) ForUserID(
 	ctx context.Context,
 	conn pg.Conn,
 	userID gid.GID,
 ) error {
 	q := `
-SELECT user_id, organization_id, created_at FROM users_organizations WHERE user_id = @user_id
+SELECT user_id, organization_id, role, created_at FROM users_organizations WHERE user_id = @user_id
 `
 
 	rows, err := conn.Query(ctx, q, pgx.StrictNamedArgs{"user_id": userID})
@@ -78,3 +161,58 @@ SELECT user_id, organization_id, created_at FROM users_organizations WHERE user_
 
 	return nil
 }
+
+func (uo *UserOrganizations) ForOrganizationID(
+	ctx context.Context,
+	conn pg.Conn,
+	organizationID gid.GID,
+) error {
+	q := `
+SELECT user_id, organization_id, role, created_at
+FROM users_organizations
+WHERE organization_id = @organization_id
+ORDER BY created_at, user_id
+`
+
+	rows, err := conn.Query(ctx, q, pgx.StrictNamedArgs{"organization_id": organizationID})
+	if err != nil {
+		return err
+	}
+
+	userOrganizations, err := pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[UserOrganization])
+	if err != nil {
+		return err
+	}
+
+	*uo = userOrganizations
+
+	return nil
+}
+
+// CountOwnersForUpdate counts the owners of the organization, locking
+// their memberships so concurrent demotions cannot leave the organization
+// without owner.
+func (uo UserOrganizations) CountOwnersForUpdate(
+	ctx context.Context,
+	conn pg.Conn,
+	organizationID gid.GID,
+) (int, error) {
+	q := `
+SELECT user_id
+FROM users_organizations
+WHERE organization_id = @organization_id AND role = @role
+FOR UPDATE
+`
+
+	rows, err := conn.Query(ctx, q, pgx.StrictNamedArgs{"organization_id": organizationID, "role": MembershipRoleOwner})
+	if err != nil {
+		return 0, fmt.Errorf("cannot query owners: %w", err)
+	}
+
+	owners, err := pgx.CollectRows(rows, pgx.RowTo[gid.GID])
+	if err != nil {
+		return 0, fmt.Errorf("cannot collect owners: %w", err)
+	}
+
+	return len(owners), nil
+}
@@ -0,0 +1,247 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package coredata
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/getprobo/probo/pkg/gid"
+	"github.com/jackc/pgx/v5"
+	"go.gearno.de/kit/pg"
+)
+
+type (
+	WebAuthnCredential struct {
+		ID              gid.GID    `db:"id"`
+		UserID          gid.GID    `db:"user_id"`
+		Name            string     `db:"name"`
+		CredentialID    []byte     `db:"credential_id"`
+		PublicKey       []byte     `db:"public_key"`
+		AttestationType string     `db:"attestation_type"`
+		Transports      []string   `db:"transports"`
+		AAGUID          []byte     `db:"aaguid"`
+		SignCount       int64      `db:"sign_count"`
+		BackupEligible  bool       `db:"backup_eligible"`
+		BackupState     bool       `db:"backup_state"`
+		LastUsedAt      *time.Time `db:"last_used_at"`
+		CreatedAt       time.Time  `db:"created_at"`
+		UpdatedAt       time.Time  `db:"updated_at"`
+	}
+
+	WebAuthnCredentials []*WebAuthnCredential
+)
+
+func (wcs *WebAuthnCredentials) LoadByUserID(
+	ctx context.Context,
+	conn pg.Conn,
+	userID gid.GID,
+) error {
+	q := `
+SELECT
+    id,
+    user_id,
+    name,
+    credential_id,
+    public_key,
+    attestation_type,
+    transports,
+    aaguid,
+    sign_count,
+    backup_eligible,
+    backup_state,
+    last_used_at,
+    created_at,
+    updated_at
+FROM
+    webauthn_credentials
+WHERE
+    user_id = @user_id
+ORDER BY
+    created_at ASC
+`
+
+	args := pgx.StrictNamedArgs{"user_id": userID}
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query webauthn credentials: %w", err)
+	}
+
+	credentials, err := pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[WebAuthnCredential])
+	if err != nil {
+		return fmt.Errorf("cannot collect webauthn credentials: %w", err)
+	}
+
+	*wcs = credentials
+
+	return nil
+}
+
+func (wc *WebAuthnCredential) LoadByUserIDAndID(
+	ctx context.Context,
+	conn pg.Conn,
+	userID gid.GID,
+	id gid.GID,
+) error {
+	q := `
+SELECT
+    id,
+    user_id,
+    name,
+    credential_id,
+    public_key,
+    attestation_type,
+    transports,
+    aaguid,
+    sign_count,
+    backup_eligible,
+    backup_state,
+    last_used_at,
+    created_at,
+    updated_at
+FROM
+    webauthn_credentials
+WHERE
+    user_id = @user_id
+    AND id = @id
+LIMIT 1;
+`
+
+	args := pgx.StrictNamedArgs{"user_id": userID, "id": id}
+
+	rows, err := conn.Query(ctx, q, args)
+	if err != nil {
+		return fmt.Errorf("cannot query webauthn credential: %w", err)
+	}
+
+	credential, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[WebAuthnCredential])
+	if err != nil {
+		return fmt.Errorf("cannot collect webauthn credential: %w", err)
+	}
+
+	*wc = credential
+
+	return nil
+}
+
+func (wc *WebAuthnCredential) Insert(
+	ctx context.Context,
+	conn pg.Conn,
+) error {
+	q := `
+INSERT INTO
+    webauthn_credentials (
+        id,
+        user_id,
+        name,
+        credential_id,
+        public_key,
+        attestation_type,
+        transports,
+        aaguid,
+        sign_count,
+        backup_eligible,
+        backup_state,
+        last_used_at,
+        created_at,
+        updated_at
+    )
+VALUES (
+    @id,
+    @user_id,
+    @name,
+    @credential_id,
+    @public_key,
+    @attestation_type,
+    @transports,
+    @aaguid,
+    @sign_count,
+    @backup_eligible,
+    @backup_state,
+    @last_used_at,
+    @created_at,
+    @updated_at
+)
+`
+
+	args := pgx.StrictNamedArgs{
+		"id":               wc.ID,
+		"user_id":          wc.UserID,
+		"name":             wc.Name,
+		"credential_id":    wc.CredentialID,
+		"public_key":       wc.PublicKey,
+		"attestation_type": wc.AttestationType,
+		"transports":       wc.Transports,
+		"aaguid":           wc.AAGUID,
+		"sign_count":       wc.SignCount,
+		"backup_eligible":  wc.BackupEligible,
+		"backup_state":     wc.BackupState,
+		"last_used_at":     wc.LastUsedAt,
+		"created_at":       wc.CreatedAt,
+		"updated_at":       wc.UpdatedAt,
+	}
+
+	_, err := conn.Exec(ctx, q, args)
+	return err
+}
+
+func (wc *WebAuthnCredential) Update(
+	ctx context.Context,
+	conn pg.Conn,
+) error {
+	q := `
+UPDATE
+    webauthn_credentials
+SET
+    name = @name,
+    sign_count = @sign_count,
+    backup_state = @backup_state,
+    last_used_at = @last_used_at,
+    updated_at = @updated_at
+WHERE
+    id = @id
+`
+
+	args := pgx.StrictNamedArgs{
+		"id":           wc.ID,
+		"name":         wc.Name,
+		"sign_count":   wc.SignCount,
+		"backup_state": wc.BackupState,
+		"last_used_at": wc.LastUsedAt,
+		"updated_at":   wc.UpdatedAt,
+	}
+
+	_, err := conn.Exec(ctx, q, args)
+	return err
+}
+
+func (wc *WebAuthnCredential) Delete(
+	ctx context.Context,
+	conn pg.Conn,
+) error {
+	q := `
+DELETE FROM
+    webauthn_credentials
+WHERE
+    id = @id
+`
+
+	args := pgx.StrictNamedArgs{"id": wc.ID}
+
+	_, err := conn.Exec(ctx, q, args)
+	return err
+}
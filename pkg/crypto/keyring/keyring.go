@@ -0,0 +1,91 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+// Package keyring holds the secrets used to sign values, the current one
+// used for new signatures and the retired ones which are only used to
+// verify signatures made before a rotation.
+//
+// Every key is identified by an ID derived from its secret, signers embed
+// that ID in what they produce so verifiers can pick the right key.
+package keyring
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+type (
+	Key struct {
+		ID     string
+		Secret []byte
+	}
+
+	Keyring struct {
+		current Key
+		keys    []Key
+	}
+)
+
+func New(current []byte, retired ...[]byte) (*Keyring, error) {
+	if len(current) == 0 {
+		return nil, fmt.Errorf("current secret cannot be empty")
+	}
+
+	kr := &Keyring{
+		current: newKey(current),
+	}
+	kr.keys = append(kr.keys, kr.current)
+
+	for _, secret := range retired {
+		if len(secret) == 0 {
+			return nil, fmt.Errorf("retired secret cannot be empty")
+		}
+
+		kr.keys = append(kr.keys, newKey(secret))
+	}
+
+	return kr, nil
+}
+
+func newKey(secret []byte) Key {
+	sum := sha256.Sum256(secret)
+
+	return Key{
+		ID:     hex.EncodeToString(sum[:4]),
+		Secret: secret,
+	}
+}
+
+// Current returns the key new signatures must be made with.
+func (kr *Keyring) Current() Key {
+	return kr.current
+}
+
+// Key returns the key with the given ID, either the current or a retired
+// one.
+func (kr *Keyring) Key(id string) (Key, bool) {
+	for _, k := range kr.keys {
+		if k.ID == id {
+			return k, true
+		}
+	}
+
+	return Key{}, false
+}
+
+// Keys returns all the keys, the current one first.
+func (kr *Keyring) Keys() []Key {
+	return kr.keys
+}
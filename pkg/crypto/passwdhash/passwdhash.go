@@ -12,36 +12,111 @@
 // OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
 // PERFORMANCE OF THIS SOFTWARE.
 
+// Package passwdhash hashes and verifies passwords.
+//
+// Hashes are self-describing: they carry the format version, the
+// algorithm, its parameters and an identifier of the pepper they were
+// computed with. This allows changing the profile at any time, hashes
+// produced by an older profile still verify and NeedsRehash reports them
+// so they can be upgraded the next time the password is known.
 package passwdhash
 
 import (
+	"bytes"
 	"crypto/hmac"
 	"crypto/rand"
 	"crypto/sha256"
 	"crypto/subtle"
 	"encoding/binary"
+	"errors"
 	"fmt"
 
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/pbkdf2"
 )
 
 type (
+	Algorithm uint8
+
 	Profile struct {
+		algorithm  Algorithm
 		iterations uint32
+		argon2id   Argon2idParams
 		saltLength uint
 		keyLength  uint
 		pepper     []byte
+		pepperID   []byte
+
+		// previousPeppers are only used to verify hashes computed before
+		// a pepper rotation.
+		previousPeppers [][]byte
 	}
+
+	Argon2idParams struct {
+		// Memory is expressed in KiB.
+		Memory  uint32
+		Time    uint32
+		Threads uint8
+	}
+
+	Option func(*Profile)
+
+	parsedHash struct {
+		version    byte
+		algorithm  Algorithm
+		pepperID   []byte
+		iterations uint32
+		argon2id   Argon2idParams
+		salt       []byte
+		key        []byte
+	}
+)
+
+var (
+	// ErrUnknownPepper is returned when a hash was computed with a pepper
+	// which is neither the current one nor a previous one, the password
+	// cannot be verified until the pepper is configured again.
+	ErrUnknownPepper = errors.New("unknown pepper")
 )
 
 const (
-	versionByte   = 0x01 // Version identifier
-	algorithmByte = 0x01 // Algorithm identifier (0x01 for PBKDF2-SHA256)
+	AlgorithmPBKDF2SHA256 Algorithm = 0x01
+	AlgorithmArgon2id     Algorithm = 0x02
+)
+
+const (
+	// versionLegacy hashes do not record the pepper they were computed
+	// with and only support PBKDF2-SHA256.
+	versionLegacy = 0x01
+	versionByte   = 0x02 // Version identifier
 
-	minIterations = 600000
+	pepperIDLength = 4
+
+	minIterations     = 600000
+	minArgon2idMemory = 19 * 1024
+	minArgon2idTime   = 2
+	minSaltLength     = 32
+	minKeyLength      = 32
 )
 
-func NewProfile(pepper []byte, iterations uint32) (*Profile, error) {
+// WithPreviousPeppers registers peppers which were used before the
+// current one, so hashes computed with them keep verifying.
+func WithPreviousPeppers(peppers ...[]byte) Option {
+	return func(hp *Profile) {
+		hp.previousPeppers = append(hp.previousPeppers, peppers...)
+	}
+}
+
+// WithArgon2id makes the profile hash new passwords with argon2id instead
+// of PBKDF2-SHA256.
+func WithArgon2id(params Argon2idParams) Option {
+	return func(hp *Profile) {
+		hp.algorithm = AlgorithmArgon2id
+		hp.argon2id = params
+	}
+}
+
+func NewProfile(pepper []byte, iterations uint32, opts ...Option) (*Profile, error) {
 	if len(pepper) < 32 {
 		return nil, fmt.Errorf("pepper must be at least 32 bytes")
 	}
@@ -50,16 +125,58 @@ func NewProfile(pepper []byte, iterations uint32) (*Profile, error) {
 		return nil, fmt.Errorf("iterations below minimum security threshold")
 	}
 
-	return &Profile{
+	hp := &Profile{
+		algorithm:  AlgorithmPBKDF2SHA256,
 		iterations: iterations,
 		saltLength: 32,
 		keyLength:  32,
 		pepper:     pepper,
-	}, nil
+		pepperID:   pepperID(pepper),
+	}
+
+	for _, opt := range opts {
+		opt(hp)
+	}
+
+	for _, previousPepper := range hp.previousPeppers {
+		if len(previousPepper) < 32 {
+			return nil, fmt.Errorf("previous pepper must be at least 32 bytes")
+		}
+	}
+
+	if hp.algorithm == AlgorithmArgon2id {
+		if err := hp.argon2id.validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return hp, nil
 }
 
-func (hp Profile) applyPepper(input []byte) []byte {
-	mac := hmac.New(sha256.New, hp.pepper)
+func (p Argon2idParams) validate() error {
+	if p.Memory < minArgon2idMemory {
+		return fmt.Errorf("argon2id memory below minimum security threshold")
+	}
+
+	if p.Time < minArgon2idTime {
+		return fmt.Errorf("argon2id time below minimum security threshold")
+	}
+
+	if p.Threads < 1 {
+		return fmt.Errorf("argon2id threads must be at least 1")
+	}
+
+	return nil
+}
+
+// pepperID identifies a pepper in hashes without revealing it.
+func pepperID(pepper []byte) []byte {
+	sum := sha256.Sum256(pepper)
+	return sum[:pepperIDLength]
+}
+
+func applyPepper(pepper, input []byte) []byte {
+	mac := hmac.New(sha256.New, pepper)
 	mac.Write(input)
 	return mac.Sum(nil)
 }
@@ -70,68 +187,219 @@ func (hp Profile) HashPassword(password []byte) ([]byte, error) {
 		return nil, fmt.Errorf("error generating salt: %v", err)
 	}
 
-	pepperedPassword := hp.applyPepper([]byte(password))
-	hash := pbkdf2.Key(pepperedPassword, salt, int(hp.iterations), int(hp.keyLength), sha256.New)
+	h := parsedHash{
+		version:    versionByte,
+		algorithm:  hp.algorithm,
+		pepperID:   hp.pepperID,
+		iterations: hp.iterations,
+		argon2id:   hp.argon2id,
+		salt:       salt,
+	}
 
-	// Binary format:
-	// [1B version][1B algorithm][4B iterations][1B salt length][salt bytes][hash bytes]
-	binaryHash := make([]byte, 0, 7+hp.saltLength+hp.keyLength)
+	h.key = h.derive(hp.pepper, password, uint32(hp.keyLength))
 
-	// Version and algorithm
-	binaryHash = append(binaryHash, versionByte)
-	binaryHash = append(binaryHash, algorithmByte)
+	return h.encode(), nil
+}
 
-	// Iterations (4 bytes, big endian)
-	iterBytes := make([]byte, 4)
-	binary.BigEndian.PutUint32(iterBytes, hp.iterations)
-	binaryHash = append(binaryHash, iterBytes...)
+// ComparePasswordAndHash reports whether the password matches the hash.
+// Hashes produced by previous profiles, including with a previous pepper,
+// are supported.
+func (hp Profile) ComparePasswordAndHash(password, passwordHash []byte) (bool, error) {
+	h, err := parseHash(passwordHash)
+	if err != nil {
+		return false, err
+	}
 
-	// Salt length and salt
-	binaryHash = append(binaryHash, byte(hp.saltLength))
-	binaryHash = append(binaryHash, salt...)
+	candidates := hp.candidatePeppers(h)
+	if len(candidates) == 0 {
+		return false, ErrUnknownPepper
+	}
 
-	// Hash
-	binaryHash = append(binaryHash, hash...)
+	for _, pepper := range candidates {
+		key := h.derive(pepper, password, uint32(len(h.key)))
+		if subtle.ConstantTimeCompare(h.key, key) == 1 {
+			return true, nil
+		}
+	}
 
-	return binaryHash, nil
+	return false, nil
 }
 
-func (hp Profile) ComparePasswordAndHash(password, passwordHash []byte) (bool, error) {
-	if len(passwordHash) < 7 {
-		return false, fmt.Errorf("hash too short")
+// NeedsRehash reports whether the hash was produced with different
+// settings than the profile ones, in which case the password should be
+// hashed again once it has been verified.
+func (hp Profile) NeedsRehash(passwordHash []byte) bool {
+	h, err := parseHash(passwordHash)
+	if err != nil {
+		return true
 	}
 
-	if passwordHash[0] != versionByte {
-		return false, fmt.Errorf("unsupported hash version: %d", passwordHash[0])
+	if h.version != versionByte || h.algorithm != hp.algorithm {
+		return true
 	}
 
-	if passwordHash[1] != algorithmByte {
-		return false, fmt.Errorf("unsupported algorithm: %d", passwordHash[1])
+	if !bytes.Equal(h.pepperID, hp.pepperID) {
+		return true
 	}
 
-	// Extract iterations
-	iterations := binary.BigEndian.Uint32(passwordHash[2:6])
+	if len(h.salt) != int(hp.saltLength) || len(h.key) != int(hp.keyLength) {
+		return true
+	}
 
-	if iterations < minIterations {
-		return false, fmt.Errorf("iterations below minimum security threshold")
+	switch h.algorithm {
+	case AlgorithmPBKDF2SHA256:
+		return h.iterations != hp.iterations
+	case AlgorithmArgon2id:
+		return h.argon2id != hp.argon2id
+	}
+
+	return true
+}
+
+// candidatePeppers returns the peppers the hash may have been computed
+// with. Legacy hashes do not record their pepper, so all of them are
+// candidates.
+func (hp Profile) candidatePeppers(h *parsedHash) [][]byte {
+	peppers := append([][]byte{hp.pepper}, hp.previousPeppers...)
+
+	if h.pepperID == nil {
+		return peppers
+	}
+
+	var candidates [][]byte
+	for _, pepper := range peppers {
+		if bytes.Equal(pepperID(pepper), h.pepperID) {
+			candidates = append(candidates, pepper)
+		}
+	}
+
+	return candidates
+}
+
+func (h parsedHash) derive(pepper, password []byte, keyLength uint32) []byte {
+	pepperedPassword := applyPepper(pepper, password)
+
+	switch h.algorithm {
+	case AlgorithmArgon2id:
+		return argon2.IDKey(
+			pepperedPassword,
+			h.salt,
+			h.argon2id.Time,
+			h.argon2id.Memory,
+			h.argon2id.Threads,
+			keyLength,
+		)
+	default:
+		return pbkdf2.Key(pepperedPassword, h.salt, int(h.iterations), int(keyLength), sha256.New)
+	}
+}
+
+// encode serializes the hash in the current binary format:
+//
+//	[1B version][1B algorithm][4B pepper id][parameters][1B salt length][salt bytes][hash bytes]
+//
+// PBKDF2-SHA256 parameters are [4B iterations], argon2id parameters are
+// [4B memory][4B time][1B threads], all integers are big endian.
+func (h parsedHash) encode() []byte {
+	b := make([]byte, 0, 16+len(h.salt)+len(h.key))
+
+	b = append(b, h.version, byte(h.algorithm))
+	b = append(b, h.pepperID...)
+
+	switch h.algorithm {
+	case AlgorithmPBKDF2SHA256:
+		b = binary.BigEndian.AppendUint32(b, h.iterations)
+	case AlgorithmArgon2id:
+		b = binary.BigEndian.AppendUint32(b, h.argon2id.Memory)
+		b = binary.BigEndian.AppendUint32(b, h.argon2id.Time)
+		b = append(b, h.argon2id.Threads)
 	}
 
-	// Extract salt length and validate
-	saltLen := int(passwordHash[6])
-	if saltLen < 32 {
-		return false, fmt.Errorf("salt length below security minimum")
+	b = append(b, byte(len(h.salt)))
+	b = append(b, h.salt...)
+	b = append(b, h.key...)
+
+	return b
+}
+
+// parseHash decodes a hash in the current format or in the legacy one:
+//
+//	[1B version][1B algorithm][4B iterations][1B salt length][salt bytes][hash bytes]
+func parseHash(passwordHash []byte) (*parsedHash, error) {
+	if len(passwordHash) < 2 {
+		return nil, fmt.Errorf("hash too short")
 	}
 
-	if len(passwordHash) < 7+saltLen+int(hp.keyLength) {
-		return false, fmt.Errorf("invalid hash length")
+	h := &parsedHash{
+		version:   passwordHash[0],
+		algorithm: Algorithm(passwordHash[1]),
 	}
+	rest := passwordHash[2:]
+
+	switch h.version {
+	case versionLegacy:
+		if h.algorithm != AlgorithmPBKDF2SHA256 {
+			return nil, fmt.Errorf("unsupported algorithm: %d", h.algorithm)
+		}
+	case versionByte:
+		if len(rest) < pepperIDLength {
+			return nil, fmt.Errorf("hash too short")
+		}
+
+		h.pepperID = rest[:pepperIDLength]
+		rest = rest[pepperIDLength:]
+	default:
+		return nil, fmt.Errorf("unsupported hash version: %d", h.version)
+	}
+
+	switch h.algorithm {
+	case AlgorithmPBKDF2SHA256:
+		if len(rest) < 4 {
+			return nil, fmt.Errorf("hash too short")
+		}
 
-	salt := passwordHash[7 : 7+saltLen]
-	storedHash := passwordHash[7+saltLen:]
+		h.iterations = binary.BigEndian.Uint32(rest[:4])
+		rest = rest[4:]
 
-	pepperedPassword := hp.applyPepper([]byte(password))
+		if h.iterations < minIterations {
+			return nil, fmt.Errorf("iterations below minimum security threshold")
+		}
+	case AlgorithmArgon2id:
+		if len(rest) < 9 {
+			return nil, fmt.Errorf("hash too short")
+		}
+
+		h.argon2id = Argon2idParams{
+			Memory:  binary.BigEndian.Uint32(rest[0:4]),
+			Time:    binary.BigEndian.Uint32(rest[4:8]),
+			Threads: rest[8],
+		}
+		rest = rest[9:]
+
+		if err := h.argon2id.validate(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported algorithm: %d", h.algorithm)
+	}
+
+	if len(rest) < 1 {
+		return nil, fmt.Errorf("hash too short")
+	}
+
+	saltLen := int(rest[0])
+	rest = rest[1:]
+
+	if saltLen < minSaltLength {
+		return nil, fmt.Errorf("salt length below security minimum")
+	}
+
+	if len(rest) < saltLen+minKeyLength {
+		return nil, fmt.Errorf("invalid hash length")
+	}
 
-	newHash := pbkdf2.Key(pepperedPassword, salt, int(iterations), len(storedHash), sha256.New)
+	h.salt = rest[:saltLen]
+	h.key = rest[saltLen:]
 
-	return subtle.ConstantTimeCompare(storedHash, newHash) == 1, nil
+	return h, nil
 }
@@ -0,0 +1,146 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	secretLength = 20 // 160 bits as recommended by RFC 4226
+	digits       = 6
+	period       = 30 * time.Second
+
+	// skew is the number of periods accepted before and after the current
+	// one to tolerate clock drift between the server and the authenticator.
+	skew = 1
+)
+
+var (
+	encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+)
+
+// GenerateSecret returns a new random base32 encoded secret.
+func GenerateSecret() (string, error) {
+	secret := make([]byte, secretLength)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("cannot generate secret: %w", err)
+	}
+
+	return encoding.EncodeToString(secret), nil
+}
+
+// GenerateCode returns the code for the given secret at the given time.
+func GenerateCode(secret string, t time.Time) (string, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+
+	return generateCode(key, uint64(t.Unix()/int64(period.Seconds()))), nil
+}
+
+// Validate checks that code is valid for the given secret at the given
+// time, tolerating a drift of one period in both directions.
+func Validate(secret string, code string, t time.Time) (bool, error) {
+	_, ok, err := ValidateCounter(secret, code, t)
+	return ok, err
+}
+
+// ValidateCounter is Validate also returning the counter, the time step,
+// the code was generated for. Callers remember the last accepted counter
+// to reject a code used twice.
+func ValidateCounter(secret string, code string, t time.Time) (int64, bool, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return 0, false, err
+	}
+
+	code = strings.TrimSpace(code)
+	if len(code) != digits {
+		return 0, false, nil
+	}
+
+	counter := t.Unix() / int64(period.Seconds())
+
+	var matched int64
+	valid := 0
+	for i := -skew; i <= skew; i++ {
+		expected := generateCode(key, uint64(counter+int64(i)))
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			matched = counter + int64(i)
+			valid = 1
+		}
+	}
+
+	return matched, valid == 1, nil
+}
+
+// URI returns the otpauth URI used by authenticator applications to
+// enroll the secret, usually rendered as a QR code.
+func URI(issuer string, accountName string, secret string) string {
+	u := url.URL{
+		Scheme: "otpauth",
+		Host:   "totp",
+		Path:   "/" + issuer + ":" + accountName,
+		RawQuery: url.Values{
+			"secret":    []string{secret},
+			"issuer":    []string{issuer},
+			"algorithm": []string{"SHA1"},
+			"digits":    []string{fmt.Sprintf("%d", digits)},
+			"period":    []string{fmt.Sprintf("%d", int(period.Seconds()))},
+		}.Encode(),
+	}
+
+	return u.String()
+}
+
+func decodeSecret(secret string) ([]byte, error) {
+	key, err := encoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode secret: %w", err)
+	}
+
+	return key, nil
+}
+
+func generateCode(key []byte, counter uint64) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, counter)
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation as described in RFC 4226 section 5.3
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for range digits {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", digits, value%mod)
+}
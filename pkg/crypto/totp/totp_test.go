@@ -0,0 +1,144 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package totp
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the SHA-1 seed of the RFC 6238 appendix B test vectors.
+var rfc6238Secret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+// rfc6238Vectors are the RFC 6238 SHA-1 test vectors, truncated to six
+// digits as the codes are the last digits of the eight digit ones.
+var rfc6238Vectors = []struct {
+	unix    int64
+	counter int64
+	code    string
+}{
+	{59, 1, "287082"},
+	{1111111109, 37037036, "081804"},
+	{1111111111, 37037037, "050471"},
+	{1234567890, 41152263, "005924"},
+	{2000000000, 66666666, "279037"},
+	{20000000000, 666666666, "353130"},
+}
+
+func TestGenerateCode(t *testing.T) {
+	for _, tt := range rfc6238Vectors {
+		code, err := GenerateCode(rfc6238Secret, time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatalf("cannot generate code at %d: %v", tt.unix, err)
+		}
+
+		if code != tt.code {
+			t.Errorf("code at %d: got %q, want %q", tt.unix, code, tt.code)
+		}
+	}
+}
+
+func TestValidateCounter(t *testing.T) {
+	for _, tt := range rfc6238Vectors {
+		counter, ok, err := ValidateCounter(rfc6238Secret, tt.code, time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatalf("cannot validate code at %d: %v", tt.unix, err)
+		}
+
+		if !ok || counter != tt.counter {
+			t.Errorf("code at %d: got (%d, %t), want (%d, true)", tt.unix, counter, ok, tt.counter)
+		}
+	}
+
+	// The code of the 1111111111 vector, generated for counter 37037037.
+	generatedAt := time.Unix(1111111111, 0)
+
+	tests := []struct {
+		name   string
+		code   string
+		at     time.Time
+		wantOK bool
+	}{
+		{"same period", "050471", generatedAt, true},
+		{"one period late", "050471", generatedAt.Add(period), true},
+		{"one period early", "050471", generatedAt.Add(-period), true},
+		{"two periods late", "050471", generatedAt.Add(2 * period), false},
+		{"two periods early", "050471", generatedAt.Add(-2 * period), false},
+		{"surrounding spaces", " 050471 ", generatedAt, true},
+		{"wrong code", "050472", generatedAt, false},
+		{"eight digits", "14050471", generatedAt, false},
+		{"empty", "", generatedAt, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name,
+			func(t *testing.T) {
+				counter, ok, err := ValidateCounter(rfc6238Secret, tt.code, tt.at)
+				if err != nil {
+					t.Fatalf("cannot validate code: %v", err)
+				}
+
+				if ok != tt.wantOK {
+					t.Fatalf("got %t, want %t", ok, tt.wantOK)
+				}
+
+				// The counter is the one the code was generated for, not
+				// the current one, so a code replayed in the next period
+				// is recognized as already used.
+				if ok && counter != 37037037 {
+					t.Errorf("got counter %d, want 37037037", counter)
+				}
+			},
+		)
+	}
+}
+
+func TestValidateCounterReplay(t *testing.T) {
+	at := time.Unix(1111111111, 0)
+
+	// The code of the previous period is still accepted within the skew,
+	// it must report its own counter so it cannot be accepted once a
+	// newer code was used.
+	previous, ok, err := ValidateCounter(rfc6238Secret, "081804", at)
+	if err != nil || !ok {
+		t.Fatalf("previous code rejected: %t, %v", ok, err)
+	}
+
+	current, ok, err := ValidateCounter(rfc6238Secret, "050471", at)
+	if err != nil || !ok {
+		t.Fatalf("current code rejected: %t, %v", ok, err)
+	}
+
+	if previous >= current {
+		t.Errorf("previous counter %d is not before current counter %d", previous, current)
+	}
+
+	replayed, ok, err := ValidateCounter(rfc6238Secret, "050471", at.Add(period))
+	if err != nil || !ok {
+		t.Fatalf("replayed code rejected: %t, %v", ok, err)
+	}
+
+	if replayed != current {
+		t.Errorf("replayed code got counter %d, want %d", replayed, current)
+	}
+}
+
+func TestValidateInvalidSecret(t *testing.T) {
+	if _, _, err := ValidateCounter("not base32!", "123456", time.Now()); err == nil {
+		t.Error("expected an error for an invalid secret")
+	}
+}
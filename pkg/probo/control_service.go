@@ -47,6 +47,29 @@ type (
 		Category        *string
 		State           *coredata.ControlState
 		Importance      *coredata.ControlImportance
+		// StateTransitionReason explains the change of state, if any.
+		StateTransitionReason *string
+		// ChangedByID is the user making the change, recorded in the
+		// state transition history.
+		ChangedByID gid.GID
+	}
+
+	DeleteControlRequest struct {
+		ID              gid.GID
+		ExpectedVersion int
+	}
+
+	ReorderControlRequest struct {
+		ID              gid.GID
+		ExpectedVersion int
+		// Position is clamped to the controls of the framework.
+		Position int
+	}
+
+	MoveControlRequest struct {
+		ID              gid.GID
+		ExpectedVersion int
+		FrameworkID     gid.GID
 	}
 )
 
@@ -88,7 +111,39 @@ func (s ControlService) Update(
 	err := s.svc.pg.WithTx(
 		ctx,
 		func(conn pg.Conn) error {
-			return control.Update(ctx, conn, s.svc.scope, params)
+			previous := &coredata.Control{}
+			if err := previous.LoadByID(ctx, conn, s.svc.scope, req.ID); err != nil {
+				return fmt.Errorf("cannot load control: %w", err)
+			}
+
+			if err := control.Update(ctx, conn, s.svc.scope, params); err != nil {
+				return err
+			}
+
+			if control.State == previous.State {
+				return nil
+			}
+
+			transitionID, err := gid.NewGID(s.svc.scope.GetTenantID(), coredata.ControlStateTransitionEntityType)
+			if err != nil {
+				return fmt.Errorf("cannot create global id: %w", err)
+			}
+
+			transition := &coredata.ControlStateTransition{
+				ID:          transitionID,
+				ControlID:   control.ID,
+				FromState:   previous.State,
+				ToState:     control.State,
+				Reason:      req.StateTransitionReason,
+				ChangedByID: &req.ChangedByID,
+				CreatedAt:   control.UpdatedAt,
+			}
+
+			if err := transition.Insert(ctx, conn, s.svc.scope); err != nil {
+				return fmt.Errorf("cannot insert control state transition: %w", err)
+			}
+
+			return nil
 		})
 	if err != nil {
 		return nil, err
@@ -97,6 +152,161 @@ func (s ControlService) Update(
 	return control, nil
 }
 
+func (s ControlService) ListStateTransitions(
+	ctx context.Context,
+	controlID gid.GID,
+	cursor *page.Cursor[coredata.StateTransitionOrderField],
+) (*page.Page[*coredata.ControlStateTransition, coredata.StateTransitionOrderField], error) {
+	var transitions coredata.ControlStateTransitions
+
+	err := s.svc.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			return transitions.LoadByControlID(
+				ctx,
+				conn,
+				s.svc.scope,
+				controlID,
+				cursor,
+			)
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return page.NewPage(transitions, cursor), nil
+}
+
+// Delete removes the control with its tasks and evidences, including the
+// evidence files stored in the bucket.
+func (s ControlService) Delete(
+	ctx context.Context,
+	req DeleteControlRequest,
+) error {
+	control := &coredata.Control{}
+	evidences := coredata.Evidences{}
+
+	err := s.svc.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+			if err := control.LoadByID(ctx, tx, s.svc.scope, req.ID); err != nil {
+				return fmt.Errorf("cannot load control: %w", err)
+			}
+
+			if err := evidences.LoadAllByControlID(ctx, tx, s.svc.scope, req.ID); err != nil {
+				return fmt.Errorf("cannot load evidences: %w", err)
+			}
+
+			if err := control.Delete(ctx, tx, s.svc.scope, req.ExpectedVersion); err != nil {
+				return fmt.Errorf("cannot delete control: %w", err)
+			}
+
+			framework := &coredata.Framework{ID: control.FrameworkID}
+			if err := framework.CompactControlPositions(ctx, tx, s.svc.scope); err != nil {
+				return fmt.Errorf("cannot compact control positions: %w", err)
+			}
+
+			return nil
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := s.svc.Evidences.deleteFiles(ctx, evidences); err != nil {
+		return fmt.Errorf("cannot delete evidence files: %w", err)
+	}
+
+	return nil
+}
+
+// Reorder moves the control to another position within its framework.
+func (s ControlService) Reorder(
+	ctx context.Context,
+	req ReorderControlRequest,
+) (*coredata.Control, error) {
+	control := &coredata.Control{}
+
+	err := s.svc.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+			if err := control.LoadByID(ctx, tx, s.svc.scope, req.ID); err != nil {
+				return fmt.Errorf("cannot load control: %w", err)
+			}
+
+			framework := &coredata.Framework{ID: control.FrameworkID}
+			nextPosition, err := framework.NextControlPosition(ctx, tx, s.svc.scope)
+			if err != nil {
+				return fmt.Errorf("cannot get control position: %w", err)
+			}
+
+			position := min(max(req.Position, 0), nextPosition-1)
+
+			if err := control.UpdatePosition(ctx, tx, s.svc.scope, req.ExpectedVersion, position); err != nil {
+				return fmt.Errorf("cannot update control position: %w", err)
+			}
+
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return control, nil
+}
+
+// Move re-parents the control, with its tasks and evidences, under
+// another framework of the organization, after its last control.
+func (s ControlService) Move(
+	ctx context.Context,
+	req MoveControlRequest,
+) (*coredata.Control, error) {
+	control := &coredata.Control{}
+	framework := &coredata.Framework{}
+
+	err := s.svc.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+			if err := control.LoadByID(ctx, tx, s.svc.scope, req.ID); err != nil {
+				return fmt.Errorf("cannot load control: %w", err)
+			}
+
+			if err := framework.LoadByID(ctx, tx, s.svc.scope, req.FrameworkID); err != nil {
+				return fmt.Errorf("cannot load framework %q: %w", req.FrameworkID, err)
+			}
+
+			if control.FrameworkID == framework.ID {
+				return fmt.Errorf("control %q already belongs to framework %q", control.ID, framework.ID)
+			}
+
+			source := &coredata.Framework{ID: control.FrameworkID}
+
+			position, err := framework.NextControlPosition(ctx, tx, s.svc.scope)
+			if err != nil {
+				return fmt.Errorf("cannot get control position: %w", err)
+			}
+
+			if err := control.UpdateFramework(ctx, tx, s.svc.scope, req.ExpectedVersion, framework.ID, position); err != nil {
+				return fmt.Errorf("cannot update control framework: %w", err)
+			}
+
+			if err := source.CompactControlPositions(ctx, tx, s.svc.scope); err != nil {
+				return fmt.Errorf("cannot compact control positions: %w", err)
+			}
+
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return control, nil
+}
+
 func (s ControlService) ListForFrameworkID(
 	ctx context.Context,
 	frameworkID gid.GID,
@@ -149,6 +359,10 @@ func (s ControlService) Create(
 		UpdatedAt:   now,
 	}
 
+	if control.ContentRef == "" {
+		control.ContentRef = controlID.String()
+	}
+
 	err = s.svc.pg.WithTx(
 		ctx,
 		func(conn pg.Conn) error {
@@ -156,6 +370,12 @@ func (s ControlService) Create(
 				return fmt.Errorf("cannot load framework %q: %w", req.FrameworkID, err)
 			}
 
+			position, err := framework.NextControlPosition(ctx, conn, s.svc.scope)
+			if err != nil {
+				return fmt.Errorf("cannot get control position: %w", err)
+			}
+			control.Position = position
+
 			if err := control.Insert(ctx, conn, s.svc.scope); err != nil {
 				return fmt.Errorf("cannot insert control: %w", err)
 			}
@@ -170,3 +390,43 @@ func (s ControlService) Create(
 
 	return control, nil
 }
+
+// ListMapped returns the controls of the other frameworks of the
+// organization that cover the same standard references as the control.
+func (s ControlService) ListMapped(
+	ctx context.Context,
+	controlID gid.GID,
+) (coredata.Controls, error) {
+	var controls coredata.Controls
+
+	err := s.svc.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			return controls.LoadMappedByControlID(ctx, conn, s.svc.scope, controlID)
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return controls, nil
+}
+
+func (s ControlService) IsCovered(
+	ctx context.Context,
+	controlID gid.GID,
+) (bool, error) {
+	var covered bool
+	control := &coredata.Control{ID: controlID}
+
+	err := s.svc.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) (err error) {
+			covered, err = control.IsCovered(ctx, conn, s.svc.scope)
+			return err
+		},
+	)
+
+	return covered, err
+}
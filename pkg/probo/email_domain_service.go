@@ -0,0 +1,288 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package probo
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"time"
+
+	"github.com/getprobo/probo/pkg/coredata"
+	"github.com/getprobo/probo/pkg/gid"
+	"go.gearno.de/kit/pg"
+)
+
+type (
+	EmailDomainService struct {
+		svc *TenantService
+	}
+
+	CreateEmailDomainRequest struct {
+		OrganizationID gid.GID
+		Domain         string
+	}
+
+	ErrPublicEmailDomain struct {
+		domain string
+	}
+
+	ErrEmailDomainNotVerified struct {
+		domain string
+	}
+)
+
+const (
+	emailDomainVerificationPrefix = "probo-domain-verification="
+)
+
+var (
+	// publicEmailDomains lists webmail providers anyone can get an
+	// address from. No organization can own them.
+	publicEmailDomains = []string{
+		"aol.com",
+		"fastmail.com",
+		"gmail.com",
+		"gmx.com",
+		"gmx.de",
+		"googlemail.com",
+		"hey.com",
+		"hotmail.com",
+		"icloud.com",
+		"live.com",
+		"mail.com",
+		"mail.ru",
+		"me.com",
+		"msn.com",
+		"outlook.com",
+		"proton.me",
+		"protonmail.com",
+		"qq.com",
+		"tutanota.com",
+		"yahoo.com",
+		"yandex.com",
+		"zoho.com",
+	}
+)
+
+func (e ErrPublicEmailDomain) Error() string {
+	return fmt.Sprintf("%q is a public email domain and cannot be claimed by an organization", e.domain)
+}
+
+func (e ErrEmailDomainNotVerified) Error() string {
+	return fmt.Sprintf("email domain %q is not verified", e.domain)
+}
+
+// EmailDomainVerificationRecord returns the value of the DNS TXT record
+// to publish on the domain to prove the organization owns it.
+func EmailDomainVerificationRecord(emailDomain *coredata.EmailDomain) string {
+	return emailDomainVerificationPrefix + emailDomain.VerificationToken
+}
+
+// Create claims an email domain for the organization. The domain must be
+// verified with Verify before it can be used for auto-join.
+func (s EmailDomainService) Create(
+	ctx context.Context,
+	req CreateEmailDomainRequest,
+) (*coredata.EmailDomain, error) {
+	domains, err := normalizeEmailDomains([]string{req.Domain})
+	if err != nil {
+		return nil, err
+	}
+
+	domain := domains[0]
+	if slices.Contains(publicEmailDomains, domain) {
+		return nil, &ErrPublicEmailDomain{domain: domain}
+	}
+
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return nil, fmt.Errorf("cannot generate verification token: %w", err)
+	}
+
+	emailDomainID, err := gid.NewGID(s.svc.scope.GetTenantID(), coredata.EmailDomainEntityType)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create global id: %w", err)
+	}
+
+	now := time.Now()
+	emailDomain := &coredata.EmailDomain{
+		ID:                emailDomainID,
+		OrganizationID:    req.OrganizationID,
+		Domain:            domain,
+		VerificationToken: hex.EncodeToString(token),
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+
+	err = s.svc.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			return emailDomain.Insert(ctx, conn, s.svc.scope)
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return emailDomain, nil
+}
+
+// Verify looks up the TXT records of the domain and marks it as verified
+// when one of them holds the verification record.
+func (s EmailDomainService) Verify(
+	ctx context.Context,
+	emailDomainID gid.GID,
+) (*coredata.EmailDomain, error) {
+	emailDomain := &coredata.EmailDomain{}
+
+	err := s.svc.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			return emailDomain.LoadByID(ctx, conn, s.svc.scope, emailDomainID)
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if emailDomain.Verified() {
+		return emailDomain, nil
+	}
+
+	records, err := net.DefaultResolver.LookupTXT(ctx, emailDomain.Domain)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if !errors.As(err, &dnsErr) || !dnsErr.IsNotFound {
+			return nil, fmt.Errorf("cannot lookup txt records: %w", err)
+		}
+	}
+
+	if !slices.Contains(records, EmailDomainVerificationRecord(emailDomain)) {
+		return nil, &ErrEmailDomainNotVerified{domain: emailDomain.Domain}
+	}
+
+	err = s.svc.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			return emailDomain.MarkAsVerified(ctx, conn, s.svc.scope, time.Now())
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return emailDomain, nil
+}
+
+// Delete releases the email domain and stops auto-joining the
+// organization with it.
+func (s EmailDomainService) Delete(
+	ctx context.Context,
+	emailDomainID gid.GID,
+) error {
+	return s.svc.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+			emailDomain := &coredata.EmailDomain{}
+			if err := emailDomain.LoadByID(ctx, tx, s.svc.scope, emailDomainID); err != nil {
+				return fmt.Errorf("cannot load email domain: %w", err)
+			}
+
+			organization := &coredata.Organization{}
+			if err := organization.LoadByID(ctx, tx, s.svc.scope, emailDomain.OrganizationID); err != nil {
+				return fmt.Errorf("cannot load organization: %w", err)
+			}
+
+			if slices.Contains(organization.AutoJoinEmailDomains, emailDomain.Domain) {
+				organization.AutoJoinEmailDomains = slices.DeleteFunc(
+					organization.AutoJoinEmailDomains,
+					func(domain string) bool { return domain == emailDomain.Domain },
+				)
+				organization.UpdatedAt = time.Now()
+
+				if err := organization.Update(ctx, s.svc.scope, tx); err != nil {
+					return fmt.Errorf("cannot update organization: %w", err)
+				}
+			}
+
+			if err := emailDomain.Delete(ctx, tx, s.svc.scope); err != nil {
+				return fmt.Errorf("cannot delete email domain: %w", err)
+			}
+
+			return nil
+		},
+	)
+}
+
+func (s EmailDomainService) ListForOrganizationID(
+	ctx context.Context,
+	organizationID gid.GID,
+) (coredata.EmailDomains, error) {
+	var emailDomains coredata.EmailDomains
+
+	err := s.svc.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			return emailDomains.LoadByOrganizationID(ctx, conn, s.svc.scope, organizationID)
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return emailDomains, nil
+}
+
+// ensureEmailDomainsVerified checks the organization proved it owns each
+// of the domains.
+func ensureEmailDomainsVerified(
+	ctx context.Context,
+	conn pg.Conn,
+	scope coredata.Scoper,
+	organizationID gid.GID,
+	domains []string,
+) error {
+	var emailDomains coredata.EmailDomains
+	if err := emailDomains.LoadByOrganizationID(ctx, conn, scope, organizationID); err != nil {
+		return fmt.Errorf("cannot load email domains: %w", err)
+	}
+
+	for _, domain := range domains {
+		if slices.Contains(publicEmailDomains, domain) {
+			return &ErrPublicEmailDomain{domain: domain}
+		}
+
+		verified := slices.ContainsFunc(
+			emailDomains,
+			func(emailDomain *coredata.EmailDomain) bool {
+				return emailDomain.Domain == domain && emailDomain.Verified()
+			},
+		)
+		if !verified {
+			return &ErrEmailDomainNotVerified{domain: domain}
+		}
+	}
+
+	return nil
+}
@@ -20,10 +20,12 @@ import (
 	"io"
 	"mime"
 	"path/filepath"
+	"slices"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/getprobo/probo/pkg/coredata"
 	"github.com/getprobo/probo/pkg/gid"
 	"github.com/getprobo/probo/pkg/page"
@@ -41,6 +43,15 @@ type (
 		Name   string
 		File   io.Reader
 	}
+
+	UpdateEvidenceStateRequest struct {
+		ID                    gid.GID
+		State                 coredata.EvidenceState
+		StateTransitionReason *string
+		// ChangedByID is the user making the change, recorded in the
+		// state transition history.
+		ChangedByID gid.GID
+	}
 )
 
 func (s EvidenceService) Get(
@@ -141,6 +152,85 @@ func (s EvidenceService) Create(
 	return evidence, nil
 }
 
+func (s EvidenceService) UpdateState(
+	ctx context.Context,
+	req UpdateEvidenceStateRequest,
+) (*coredata.Evidence, error) {
+	evidence := &coredata.Evidence{}
+
+	err := s.svc.pg.WithTx(
+		ctx,
+		func(conn pg.Conn) error {
+			if err := evidence.LoadByID(ctx, conn, s.svc.scope, req.ID); err != nil {
+				return fmt.Errorf("cannot load evidence: %w", err)
+			}
+
+			previousState := evidence.State
+			if previousState == req.State {
+				return nil
+			}
+
+			if err := evidence.UpdateState(ctx, conn, s.svc.scope, req.State); err != nil {
+				return fmt.Errorf("cannot update evidence state: %w", err)
+			}
+
+			transitionID, err := gid.NewGID(s.svc.scope.GetTenantID(), coredata.EvidenceStateTransitionEntityType)
+			if err != nil {
+				return fmt.Errorf("cannot create global id: %w", err)
+			}
+
+			transition := &coredata.EvidenceStateTransition{
+				ID:          transitionID,
+				EvidenceID:  evidence.ID,
+				FromState:   previousState,
+				ToState:     evidence.State,
+				Reason:      req.StateTransitionReason,
+				ChangedByID: &req.ChangedByID,
+				CreatedAt:   evidence.UpdatedAt,
+			}
+
+			if err := transition.Insert(ctx, conn, s.svc.scope); err != nil {
+				return fmt.Errorf("cannot insert evidence state transition: %w", err)
+			}
+
+			return nil
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return evidence, nil
+}
+
+func (s EvidenceService) ListStateTransitions(
+	ctx context.Context,
+	evidenceID gid.GID,
+	cursor *page.Cursor[coredata.StateTransitionOrderField],
+) (*page.Page[*coredata.EvidenceStateTransition, coredata.StateTransitionOrderField], error) {
+	var transitions coredata.EvidenceStateTransitions
+
+	err := s.svc.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			return transitions.LoadByEvidenceID(
+				ctx,
+				conn,
+				s.svc.scope,
+				evidenceID,
+				cursor,
+			)
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return page.NewPage(transitions, cursor), nil
+}
+
 func (s EvidenceService) GenerateFileURL(
 	ctx context.Context,
 	evidenceID gid.GID,
@@ -212,3 +302,36 @@ func (s *EvidenceService) Delete(
 		},
 	)
 }
+
+// deleteFiles removes the files of evidences whose rows are already
+// deleted. Files are only removed once nothing references them, so a
+// failure leaves orphaned objects rather than dangling rows.
+func (s EvidenceService) deleteFiles(
+	ctx context.Context,
+	evidences coredata.Evidences,
+) error {
+	// DeleteObjects accepts at most 1000 keys per request.
+	for batch := range slices.Chunk(evidences, 1000) {
+		objects := make([]types.ObjectIdentifier, len(batch))
+		for i, evidence := range batch {
+			objects[i] = types.ObjectIdentifier{Key: aws.String(evidence.ObjectKey)}
+		}
+
+		output, err := s.svc.s3.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(s.svc.bucket),
+			Delete: &types.Delete{
+				Objects: objects,
+				Quiet:   aws.Bool(true),
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("cannot delete objects: %w", err)
+		}
+
+		if len(output.Errors) > 0 {
+			return fmt.Errorf("cannot delete object %q: %s", aws.ToString(output.Errors[0].Key), aws.ToString(output.Errors[0].Message))
+		}
+	}
+
+	return nil
+}
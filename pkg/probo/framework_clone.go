@@ -0,0 +1,220 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package probo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/getprobo/probo/pkg/coredata"
+	"github.com/getprobo/probo/pkg/gid"
+	"go.gearno.de/kit/pg"
+)
+
+type (
+	CloneFrameworkRequest struct {
+		FrameworkID gid.GID
+		// OrganizationID is the organization receiving the copy, it may
+		// differ from the organization of the framework.
+		OrganizationID gid.GID
+		Name           *string
+		// CopyState keeps the state of the controls and tasks instead of
+		// starting over from scratch.
+		CopyState bool
+		// CopyAssignments keeps the task assignees. Across organizations
+		// assignees are matched by primary email address and tasks
+		// without a match are left unassigned.
+		CopyAssignments bool
+	}
+)
+
+// Clone copies the framework with its controls and tasks into the
+// organization of the target tenant. Evidences are never copied.
+func (s FrameworkService) Clone(
+	ctx context.Context,
+	target *TenantService,
+	req CloneFrameworkRequest,
+) (*coredata.Framework, error) {
+	source := &coredata.Framework{}
+	controls := coredata.Controls{}
+	tasks := coredata.Tasks{}
+	assigneeEmailAddresses := make(map[gid.GID]string)
+
+	err := s.svc.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			if err := source.LoadByID(ctx, conn, s.svc.scope, req.FrameworkID); err != nil {
+				return fmt.Errorf("cannot load framework: %w", err)
+			}
+
+			if err := controls.LoadAllByFrameworkID(ctx, conn, s.svc.scope, req.FrameworkID); err != nil {
+				return fmt.Errorf("cannot load controls: %w", err)
+			}
+
+			if err := tasks.LoadAllByFrameworkID(ctx, conn, s.svc.scope, req.FrameworkID); err != nil {
+				return fmt.Errorf("cannot load tasks: %w", err)
+			}
+
+			if !req.CopyAssignments || source.OrganizationID == req.OrganizationID {
+				return nil
+			}
+
+			for _, task := range tasks {
+				if task.AssignedTo == nil {
+					continue
+				}
+
+				if _, ok := assigneeEmailAddresses[*task.AssignedTo]; ok {
+					continue
+				}
+
+				assignee := &coredata.People{}
+				if err := assignee.LoadByID(ctx, conn, s.svc.scope, *task.AssignedTo); err != nil {
+					return fmt.Errorf("cannot load assignee: %w", err)
+				}
+
+				assigneeEmailAddresses[assignee.ID] = assignee.PrimaryEmailAddress
+			}
+
+			return nil
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	tenantID := target.scope.GetTenantID()
+
+	frameworkID, err := gid.NewGID(tenantID, coredata.FrameworkEntityType)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create global id: %w", err)
+	}
+
+	framework := &coredata.Framework{
+		ID:             frameworkID,
+		OrganizationID: req.OrganizationID,
+		Name:           source.Name,
+		Description:    source.Description,
+		ContentRef:     source.ContentRef,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	if req.Name != nil {
+		framework.Name = *req.Name
+	}
+
+	controlIDs := make(map[gid.GID]gid.GID, len(controls))
+	clonedControls := make(coredata.Controls, len(controls))
+	for i, control := range controls {
+		controlID, err := gid.NewGID(tenantID, coredata.ControlEntityType)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create global id: %w", err)
+		}
+
+		controlIDs[control.ID] = controlID
+
+		clonedControl := *control
+		clonedControl.ID = controlID
+		clonedControl.FrameworkID = frameworkID
+		clonedControl.CreatedAt = now
+		clonedControl.UpdatedAt = now
+		clonedControl.Version = 0
+
+		if !req.CopyState {
+			clonedControl.State = coredata.ControlStateNotStarted
+		}
+
+		clonedControls[i] = &clonedControl
+	}
+
+	clonedTasks := make(coredata.Tasks, len(tasks))
+	for i, task := range tasks {
+		taskID, err := gid.NewGID(tenantID, coredata.TaskEntityType)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create global id: %w", err)
+		}
+
+		clonedTask := *task
+		clonedTask.ID = taskID
+		clonedTask.ControlID = controlIDs[task.ControlID]
+		clonedTask.CreatedAt = now
+		clonedTask.UpdatedAt = now
+		clonedTask.Version = 0
+
+		if !req.CopyState {
+			clonedTask.State = coredata.TaskStateTodo
+		}
+
+		if !req.CopyAssignments {
+			clonedTask.AssignedTo = nil
+		}
+
+		clonedTasks[i] = &clonedTask
+	}
+
+	err = target.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+			if err := framework.Insert(ctx, tx, target.scope); err != nil {
+				return fmt.Errorf("cannot insert framework: %w", err)
+			}
+
+			for _, clonedControl := range clonedControls {
+				if err := clonedControl.Insert(ctx, tx, target.scope); err != nil {
+					return fmt.Errorf("cannot insert control: %w", err)
+				}
+			}
+
+			assignees := make(map[gid.GID]*gid.GID, len(assigneeEmailAddresses))
+			for sourceAssigneeID, emailAddress := range assigneeEmailAddresses {
+				assignee := &coredata.People{}
+				err := assignee.LoadByPrimaryEmailAddress(ctx, tx, target.scope, req.OrganizationID, emailAddress)
+				if err != nil {
+					var errPeopleNotFound *coredata.ErrPeopleNotFound
+					if errors.As(err, &errPeopleNotFound) {
+						continue
+					}
+
+					return fmt.Errorf("cannot load assignee: %w", err)
+				}
+
+				assignees[sourceAssigneeID] = &assignee.ID
+			}
+
+			for _, clonedTask := range clonedTasks {
+				if clonedTask.AssignedTo != nil && source.OrganizationID != req.OrganizationID {
+					clonedTask.AssignedTo = assignees[*clonedTask.AssignedTo]
+				}
+
+				if err := clonedTask.Insert(ctx, tx, target.scope); err != nil {
+					return fmt.Errorf("cannot insert task: %w", err)
+				}
+			}
+
+			return nil
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return framework, nil
+}
@@ -0,0 +1,362 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package probo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getprobo/probo/pkg/coredata"
+	"sigs.k8s.io/yaml"
+)
+
+type (
+	// FrameworkDocumentProblem is one reason a framework document is
+	// rejected. Path locates the offending value, e.g.
+	// framework.controls[12].tasks[3].name; Line and Column are only set
+	// for syntax errors, Column is unknown for YAML documents.
+	FrameworkDocumentProblem struct {
+		Path    string
+		Line    int
+		Column  int
+		Message string
+	}
+
+	ErrInvalidFrameworkDocument struct {
+		Problems []FrameworkDocumentProblem
+	}
+
+	frameworkDocumentValidator struct {
+		problems    []FrameworkDocumentProblem
+		contentRefs map[string]string
+	}
+)
+
+var (
+	yamlErrorLineRegexp = regexp.MustCompile(`yaml: line (\d+): (.*)`)
+)
+
+func (e ErrInvalidFrameworkDocument) Error() string {
+	messages := make([]string, len(e.Problems))
+	for i, problem := range e.Problems {
+		messages[i] = problem.String()
+	}
+
+	return "invalid framework document: " + strings.Join(messages, "; ")
+}
+
+func (p FrameworkDocumentProblem) String() string {
+	switch {
+	case p.Line > 0 && p.Column > 0:
+		return fmt.Sprintf("line %d, column %d: %s", p.Line, p.Column, p.Message)
+	case p.Line > 0:
+		return fmt.Sprintf("line %d: %s", p.Line, p.Message)
+	case p.Path != "":
+		return p.Path + ": " + p.Message
+	default:
+		return p.Message
+	}
+}
+
+// ParseFrameworkDocument decodes and validates a framework document
+// written in JSON or YAML. Every problem found is reported at once in an
+// ErrInvalidFrameworkDocument rather than stopping at the first one.
+func ParseFrameworkDocument(r io.Reader) (*FrameworkDocument, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read framework document: %w", err)
+	}
+
+	// JSON documents are objects, anything else is read as YAML and
+	// validated as the equivalent JSON document, so problems are reported
+	// with the same paths.
+	if trimmed := bytes.TrimSpace(data); len(trimmed) == 0 || trimmed[0] != '{' {
+		data, err = yaml.YAMLToJSON(data)
+		if err != nil {
+			return nil, &ErrInvalidFrameworkDocument{
+				Problems: []FrameworkDocumentProblem{yamlSyntaxProblem(err)},
+			}
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var raw any
+	if err := decoder.Decode(&raw); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			line, column := offsetPosition(data, syntaxErr.Offset)
+			return nil, &ErrInvalidFrameworkDocument{
+				Problems: []FrameworkDocumentProblem{
+					{Line: line, Column: column, Message: syntaxErr.Error()},
+				},
+			}
+		}
+
+		return nil, &ErrInvalidFrameworkDocument{
+			Problems: []FrameworkDocumentProblem{{Message: err.Error()}},
+		}
+	}
+
+	v := &frameworkDocumentValidator{contentRefs: make(map[string]string)}
+	v.validateDocument(raw)
+
+	if len(v.problems) > 0 {
+		return nil, &ErrInvalidFrameworkDocument{Problems: v.problems}
+	}
+
+	document := &FrameworkDocument{}
+	if err := json.Unmarshal(data, document); err != nil {
+		return nil, fmt.Errorf("cannot decode framework document: %w", err)
+	}
+
+	return document, nil
+}
+
+// yamlSyntaxProblem extracts the line of a YAML syntax error when the
+// parser reports it.
+func yamlSyntaxProblem(err error) FrameworkDocumentProblem {
+	matches := yamlErrorLineRegexp.FindStringSubmatch(err.Error())
+	if matches == nil {
+		return FrameworkDocumentProblem{Message: err.Error()}
+	}
+
+	line, _ := strconv.Atoi(matches[1])
+
+	return FrameworkDocumentProblem{Line: line, Message: matches[2]}
+}
+
+func offsetPosition(data []byte, offset int64) (int, int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := int(offset) - bytes.LastIndexByte(before, '\n')
+
+	return line, column
+}
+
+func (v *frameworkDocumentValidator) addProblem(path string, format string, args ...any) {
+	v.problems = append(
+		v.problems,
+		FrameworkDocumentProblem{
+			Path:    path,
+			Message: fmt.Sprintf(format, args...),
+		},
+	)
+}
+
+func (v *frameworkDocumentValidator) validateDocument(raw any) {
+	document, ok := v.object("", raw, "framework")
+	if !ok {
+		return
+	}
+
+	framework, ok := v.object("framework", document["framework"], "name", "description", "content-ref", "version", "controls")
+	if !ok {
+		return
+	}
+
+	v.requiredString("framework.name", framework["name"])
+	v.optionalString("framework.description", framework["description"])
+	v.requiredString("framework.content-ref", framework["content-ref"])
+	v.optionalString("framework.version", framework["version"])
+
+	controls, ok := v.array("framework.controls", framework["controls"], true)
+	if !ok {
+		return
+	}
+
+	for i, control := range controls {
+		v.validateControl(fmt.Sprintf("framework.controls[%d]", i), control)
+	}
+}
+
+func (v *frameworkDocumentValidator) validateControl(path string, raw any) {
+	control, ok := v.object(path, raw, "content-ref", "category", "importance", "standards", "name", "description", "tasks")
+	if !ok {
+		return
+	}
+
+	v.requiredString(path+".name", control["name"])
+	v.optionalString(path+".description", control["description"])
+	v.optionalString(path+".category", control["category"])
+
+	if contentRef, ok := v.requiredString(path+".content-ref", control["content-ref"]); ok {
+		v.uniqueContentRef(path+".content-ref", contentRef)
+	}
+
+	if importance, ok := v.requiredString(path+".importance", control["importance"]); ok {
+		var value coredata.ControlImportance
+		if err := value.Scan(importance); err != nil {
+			v.addProblem(path+".importance", "must be one of MANDATORY, PREFERRED or ADVANCED, got %q", importance)
+		}
+	}
+
+	if standards, ok := v.array(path+".standards", control["standards"], false); ok {
+		for i, standard := range standards {
+			v.requiredString(fmt.Sprintf("%s.standards[%d]", path, i), standard)
+		}
+	}
+
+	if tasks, ok := v.array(path+".tasks", control["tasks"], false); ok {
+		for i, task := range tasks {
+			v.validateTask(fmt.Sprintf("%s.tasks[%d]", path, i), task)
+		}
+	}
+}
+
+func (v *frameworkDocumentValidator) validateTask(path string, raw any) {
+	task, ok := v.object(path, raw, "content-ref", "name", "description", "time-estimate")
+	if !ok {
+		return
+	}
+
+	v.requiredString(path+".name", task["name"])
+	v.optionalString(path+".description", task["description"])
+
+	if contentRef, ok := v.optionalString(path+".content-ref", task["content-ref"]); ok && contentRef != "" {
+		v.uniqueContentRef(path+".content-ref", contentRef)
+	}
+
+	if raw, ok := task["time-estimate"]; ok && raw != nil {
+		number, ok := raw.(json.Number)
+		if !ok {
+			v.addProblem(path+".time-estimate", "must be a number of seconds")
+			return
+		}
+
+		if seconds, err := number.Int64(); err != nil || seconds < 0 {
+			v.addProblem(path+".time-estimate", "must be a positive whole number of seconds, got %s", number)
+		}
+	}
+}
+
+func (v *frameworkDocumentValidator) uniqueContentRef(path string, contentRef string) {
+	if previous, ok := v.contentRefs[contentRef]; ok {
+		v.addProblem(path, "duplicate content-ref %q, already used by %s", contentRef, previous)
+		return
+	}
+
+	v.contentRefs[contentRef] = path
+}
+
+func (v *frameworkDocumentValidator) object(path string, raw any, fields ...string) (map[string]any, bool) {
+	if raw == nil {
+		v.addProblem(orRoot(path), "is required")
+		return nil, false
+	}
+
+	object, ok := raw.(map[string]any)
+	if !ok {
+		v.addProblem(orRoot(path), "must be an object")
+		return nil, false
+	}
+
+	unknown := []string{}
+	for key := range object {
+		known := false
+		for _, field := range fields {
+			if key == field {
+				known = true
+				break
+			}
+		}
+
+		if !known {
+			unknown = append(unknown, key)
+		}
+	}
+
+	sort.Strings(unknown)
+	for _, key := range unknown {
+		v.addProblem(joinPath(path, key), "unknown field")
+	}
+
+	return object, true
+}
+
+func (v *frameworkDocumentValidator) array(path string, raw any, required bool) ([]any, bool) {
+	if raw == nil {
+		if required {
+			v.addProblem(path, "is required")
+		}
+
+		return nil, false
+	}
+
+	array, ok := raw.([]any)
+	if !ok {
+		v.addProblem(path, "must be an array")
+		return nil, false
+	}
+
+	return array, true
+}
+
+func (v *frameworkDocumentValidator) requiredString(path string, raw any) (string, bool) {
+	if raw == nil {
+		v.addProblem(path, "is required")
+		return "", false
+	}
+
+	value, ok := v.optionalString(path, raw)
+	if ok && strings.TrimSpace(value) == "" {
+		v.addProblem(path, "cannot be empty")
+		return "", false
+	}
+
+	return value, ok
+}
+
+func (v *frameworkDocumentValidator) optionalString(path string, raw any) (string, bool) {
+	if raw == nil {
+		return "", true
+	}
+
+	value, ok := raw.(string)
+	if !ok {
+		v.addProblem(path, "must be a string")
+		return "", false
+	}
+
+	return value, true
+}
+
+func joinPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
+
+func orRoot(path string) string {
+	if path == "" {
+		return "$"
+	}
+
+	return path
+}
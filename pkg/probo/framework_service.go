@@ -45,28 +45,50 @@ type (
 		Description     *string
 	}
 
+	DeleteFrameworkRequest struct {
+		ID gid.GID
+		// ConfirmationName must match the framework name, as the
+		// deletion cannot be undone.
+		ConfirmationName string
+	}
+
+	ErrFrameworkDeletionNotConfirmed struct {
+		message string
+	}
+
 	ImportFrameworkRequest struct {
-		Data struct {
-			Framework struct {
-				Name        string `json:"name"`
-				ContentRef  string `json:"content-ref"`
-				Description string `json:"description"`
-				Version     string `json:"version"`
-				Controls    []struct {
-					ContentRef  string                     `json:"content-ref"`
-					Category    string                     `json:"category"`
-					Importance  coredata.ControlImportance `json:"importance"`
-					Standards   []string                   `json:"standards"`
-					Name        string                     `json:"name"`
-					Description string                     `json:"description"`
-					Tasks       []struct {
-						Name         string `json:"name"`
-						Description  string `json:"description"`
-						TimeEstimate int    `json:"time-estimate"`
-					} `json:"tasks"`
-				} `json:"controls"`
-			} `json:"framework"`
-		}
+		Data FrameworkDocument
+	}
+
+	// FrameworkDocument is the document format frameworks are imported
+	// from and exported to (see data/frameworks).
+	FrameworkDocument struct {
+		Framework FrameworkDocumentFramework `json:"framework"`
+	}
+
+	FrameworkDocumentFramework struct {
+		Name        string                     `json:"name"`
+		ContentRef  string                     `json:"content-ref"`
+		Description string                     `json:"description"`
+		Version     string                     `json:"version,omitempty"`
+		Controls    []FrameworkDocumentControl `json:"controls"`
+	}
+
+	FrameworkDocumentControl struct {
+		ContentRef  string                     `json:"content-ref"`
+		Category    string                     `json:"category"`
+		Importance  coredata.ControlImportance `json:"importance"`
+		Standards   []string                   `json:"standards"`
+		Name        string                     `json:"name"`
+		Description string                     `json:"description"`
+		Tasks       []FrameworkDocumentTask    `json:"tasks"`
+	}
+
+	FrameworkDocumentTask struct {
+		ContentRef   string `json:"content-ref,omitempty"`
+		Name         string `json:"name"`
+		Description  string `json:"description"`
+		TimeEstimate int    `json:"time-estimate,omitempty"`
 	}
 )
 
@@ -90,6 +112,12 @@ func (s FrameworkService) Create(
 		UpdatedAt:      now,
 	}
 
+	// Frameworks created from the console have no upstream reference; use
+	// the ID so the framework can still be exported and re-imported.
+	if framework.ContentRef == "" {
+		framework.ContentRef = frameworkID.String()
+	}
+
 	err = s.svc.pg.WithConn(
 		ctx,
 		func(conn pg.Conn) error {
@@ -151,6 +179,46 @@ func (s FrameworkService) Get(
 	return framework, nil
 }
 
+func (s FrameworkService) ListStandardCoverages(
+	ctx context.Context,
+	frameworkID gid.GID,
+) (coredata.StandardCoverages, error) {
+	var coverages coredata.StandardCoverages
+
+	err := s.svc.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			return coverages.LoadByFrameworkID(ctx, conn, s.svc.scope, frameworkID)
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return coverages, nil
+}
+
+func (s FrameworkService) GetReadiness(
+	ctx context.Context,
+	frameworkID gid.GID,
+) (*coredata.Readiness, error) {
+	readiness := &coredata.Readiness{}
+
+	err := s.svc.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			return readiness.LoadByFrameworkID(ctx, conn, s.svc.scope, frameworkID)
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return readiness, nil
+}
+
 func (s FrameworkService) Update(
 	ctx context.Context,
 	req UpdateFrameworkRequest,
@@ -175,18 +243,50 @@ func (s FrameworkService) Update(
 	return framework, nil
 }
 
+func (e ErrFrameworkDeletionNotConfirmed) Error() string {
+	return e.message
+}
+
+// Delete removes the framework with its controls, tasks and evidences,
+// including the evidence files stored in the bucket.
 func (s FrameworkService) Delete(
 	ctx context.Context,
-	frameworkID gid.GID,
+	req DeleteFrameworkRequest,
 ) error {
-	framework := &coredata.Framework{ID: frameworkID}
+	framework := &coredata.Framework{}
+	evidences := coredata.Evidences{}
 
-	return s.svc.pg.WithConn(
+	err := s.svc.pg.WithTx(
 		ctx,
-		func(conn pg.Conn) error {
-			return framework.Delete(ctx, conn, s.svc.scope)
+		func(tx pg.Conn) error {
+			if err := framework.LoadByID(ctx, tx, s.svc.scope, req.ID); err != nil {
+				return fmt.Errorf("cannot load framework: %w", err)
+			}
+
+			if framework.Name != req.ConfirmationName {
+				return &ErrFrameworkDeletionNotConfirmed{message: "the confirmation name does not match the framework name"}
+			}
+
+			if err := evidences.LoadAllByFrameworkID(ctx, tx, s.svc.scope, req.ID); err != nil {
+				return fmt.Errorf("cannot load evidences: %w", err)
+			}
+
+			if err := framework.Delete(ctx, tx, s.svc.scope); err != nil {
+				return fmt.Errorf("cannot delete framework: %w", err)
+			}
+
+			return nil
 		},
 	)
+	if err != nil {
+		return err
+	}
+
+	if err := s.svc.Evidences.deleteFiles(ctx, evidences); err != nil {
+		return fmt.Errorf("cannot delete evidence files: %w", err)
+	}
+
+	return nil
 }
 
 func (s FrameworkService) Import(
@@ -194,101 +294,269 @@ func (s FrameworkService) Import(
 	organizationID gid.GID,
 	req ImportFrameworkRequest,
 ) (*coredata.Framework, error) {
+	framework, importedControls, importedTasks, err := req.Data.newFramework(organizationID, time.Now())
+	if err != nil {
+		return nil, err
+	}
 
-	now := time.Now()
+	err = s.svc.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+
+			err := framework.Insert(ctx, tx, s.svc.scope)
+			if err != nil {
+				return fmt.Errorf("cannot insert framework: %w", err)
+			}
+
+			for _, importedControl := range importedControls {
+				if err := importedControl.Insert(ctx, tx, s.svc.scope); err != nil {
+					return fmt.Errorf("cannot insert control: %w", err)
+				}
+			}
+
+			for _, importedTask := range importedTasks {
+				if err := importedTask.Insert(ctx, tx, s.svc.scope); err != nil {
+					return fmt.Errorf("cannot insert task: %w", err)
+				}
+			}
+
+			return nil
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return framework, nil
+}
+
+// Export serialises the framework with its controls and tasks into the
+// document format accepted by Import.
+func (s FrameworkService) Export(
+	ctx context.Context,
+	frameworkID gid.GID,
+) (*FrameworkDocument, error) {
+	framework := &coredata.Framework{}
+	controls := coredata.Controls{}
+	tasks := coredata.Tasks{}
+
+	err := s.svc.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			if err := framework.LoadByID(ctx, conn, s.svc.scope, frameworkID); err != nil {
+				return fmt.Errorf("cannot load framework: %w", err)
+			}
+
+			if err := controls.LoadAllByFrameworkID(ctx, conn, s.svc.scope, frameworkID); err != nil {
+				return fmt.Errorf("cannot load controls: %w", err)
+			}
+
+			if err := tasks.LoadAllByFrameworkID(ctx, conn, s.svc.scope, frameworkID); err != nil {
+				return fmt.Errorf("cannot load tasks: %w", err)
+			}
+
+			return nil
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
 
+	return newFrameworkDocument(framework, controls, tasks), nil
+}
+
+// newFramework builds the framework, controls and tasks described by the
+// document, ready to be inserted in the given organization.
+func (d FrameworkDocument) newFramework(
+	organizationID gid.GID,
+	now time.Time,
+) (*coredata.Framework, coredata.Controls, coredata.Tasks, error) {
 	frameworkID, err := gid.NewGID(organizationID.TenantID(), coredata.FrameworkEntityType)
 	if err != nil {
-		return nil, fmt.Errorf("cannot create global id: %w", err)
+		return nil, nil, nil, fmt.Errorf("cannot create global id: %w", err)
 	}
 
 	framework := &coredata.Framework{
 		ID:             frameworkID,
 		OrganizationID: organizationID,
-		Name:           req.Data.Framework.Name,
-		Description:    req.Data.Framework.Description,
-		ContentRef:     req.Data.Framework.ContentRef,
+		Name:           d.Framework.Name,
+		Description:    d.Framework.Description,
+		ContentRef:     d.Framework.ContentRef,
 		CreatedAt:      now,
 		UpdatedAt:      now,
 	}
 
-	importedControls := coredata.Controls{}
-	importedTasks := coredata.Tasks{}
-	for _, control := range req.Data.Framework.Controls {
+	controls := coredata.Controls{}
+	tasks := coredata.Tasks{}
+	for i, control := range d.Framework.Controls {
 		controlID, err := gid.NewGID(organizationID.TenantID(), coredata.ControlEntityType)
 		if err != nil {
-			return nil, fmt.Errorf("cannot create global id: %w", err)
-		}
-
-		importedControl := &coredata.Control{
-			ID:          controlID,
-			FrameworkID: frameworkID,
-			Category:    control.Category,
-			Importance:  coredata.ControlImportance(control.Importance),
-			Name:        control.Name,
-			Description: control.Description,
-			State:       coredata.ControlStateNotStarted,
-			ContentRef:  control.ContentRef,
-			CreatedAt:   now,
-			UpdatedAt:   now,
-			Standards:   control.Standards,
+			return nil, nil, nil, fmt.Errorf("cannot create global id: %w", err)
 		}
 
-		importedControls = append(importedControls, importedControl)
+		controls = append(controls, control.newControl(controlID, frameworkID, i, now))
 
 		for _, task := range control.Tasks {
 			taskID, err := gid.NewGID(organizationID.TenantID(), coredata.TaskEntityType)
 			if err != nil {
-				return nil, fmt.Errorf("cannot create global id: %w", err)
+				return nil, nil, nil, fmt.Errorf("cannot create global id: %w", err)
 			}
 
-			var timeEstimate *time.Duration
-			if task.TimeEstimate > 0 {
-				timeEstimate = ref.Ref(time.Duration(task.TimeEstimate) * time.Second)
-			}
+			tasks = append(tasks, task.newTask(taskID, controlID, now))
+		}
+	}
 
-			importedTasks = append(importedTasks, &coredata.Task{
-				ID:           taskID,
-				ControlID:    controlID,
-				Name:         task.Name,
-				State:        coredata.TaskStateTodo,
-				Description:  task.Description,
-				ContentRef:   "",
-				CreatedAt:    now,
-				UpdatedAt:    now,
-				TimeEstimate: timeEstimate,
-			})
+	return framework, controls, tasks, nil
+}
+
+// newFrameworkDocument serialises the framework, its controls and their
+// tasks. Content-refs must be present on controls and unique across the
+// document, so entities created before refs were assigned on creation, or
+// whose ref collides after being moved between frameworks, are exported
+// under their ID instead.
+func newFrameworkDocument(
+	framework *coredata.Framework,
+	controls coredata.Controls,
+	tasks coredata.Tasks,
+) *FrameworkDocument {
+	usedContentRefs := make(map[string]bool)
+	contentRef := func(id gid.GID, contentRef string, required bool) string {
+		if contentRef == "" && !required {
+			return ""
+		}
+
+		if contentRef == "" || usedContentRefs[contentRef] {
+			contentRef = id.String()
 		}
+
+		usedContentRefs[contentRef] = true
+		return contentRef
 	}
 
-	err = s.svc.pg.WithTx(
-		ctx,
-		func(tx pg.Conn) error {
+	controlContentRefs := make(map[gid.GID]string, len(controls))
+	controlTasks := make(map[gid.GID][]FrameworkDocumentTask, len(controls))
+	for _, control := range controls {
+		controlContentRefs[control.ID] = contentRef(control.ID, control.ContentRef, true)
+		controlTasks[control.ID] = []FrameworkDocumentTask{}
+	}
 
-			err := framework.Insert(ctx, tx, s.svc.scope)
-			if err != nil {
-				return fmt.Errorf("cannot insert framework: %w", err)
-			}
+	for _, task := range tasks {
+		exportedTask := FrameworkDocumentTask{
+			ContentRef:  contentRef(task.ID, task.ContentRef, false),
+			Name:        task.Name,
+			Description: task.Description,
+		}
 
-			for _, importedControl := range importedControls {
-				if err := importedControl.Insert(ctx, tx, s.svc.scope); err != nil {
-					return fmt.Errorf("cannot insert control: %w", err)
-				}
-			}
+		if task.TimeEstimate != nil {
+			exportedTask.TimeEstimate = int(task.TimeEstimate.Seconds())
+		}
 
-			for _, importedTask := range importedTasks {
-				if err := importedTask.Insert(ctx, tx, s.svc.scope); err != nil {
-					return fmt.Errorf("cannot insert task: %w", err)
-				}
-			}
+		controlTasks[task.ControlID] = append(controlTasks[task.ControlID], exportedTask)
+	}
 
-			return nil
+	frameworkContentRef := framework.ContentRef
+	if frameworkContentRef == "" {
+		frameworkContentRef = framework.ID.String()
+	}
+
+	document := &FrameworkDocument{
+		Framework: FrameworkDocumentFramework{
+			Name:        framework.Name,
+			ContentRef:  frameworkContentRef,
+			Description: framework.Description,
+			Controls:    make([]FrameworkDocumentControl, len(controls)),
 		},
-	)
+	}
+
+	for i, control := range controls {
+		standards := control.Standards
+		if standards == nil {
+			standards = []string{}
+		}
+
+		document.Framework.Controls[i] = FrameworkDocumentControl{
+			ContentRef:  controlContentRefs[control.ID],
+			Category:    control.Category,
+			Importance:  control.Importance,
+			Standards:   standards,
+			Name:        control.Name,
+			Description: control.Description,
+			Tasks:       controlTasks[control.ID],
+		}
+	}
+
+	return document
+}
+
+func (c FrameworkDocumentControl) newControl(
+	controlID gid.GID,
+	frameworkID gid.GID,
+	position int,
+	now time.Time,
+) *coredata.Control {
+	return &coredata.Control{
+		ID:            controlID,
+		FrameworkID:   frameworkID,
+		Category:      c.Category,
+		Importance:    c.Importance,
+		Name:          c.Name,
+		Description:   c.Description,
+		State:         coredata.ControlStateNotStarted,
+		ContentRef:    c.ContentRef,
+		ContentDigest: ref.Ref(c.digest()),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+		Standards:     c.Standards,
+		Position:      position,
+	}
+}
+
+func (c FrameworkDocumentControl) digest() string {
+	return controlContentDigest(c.Category, c.Name, c.Description, c.Importance, c.Standards)
+}
+
+func (t FrameworkDocumentTask) newTask(
+	taskID gid.GID,
+	controlID gid.GID,
+	now time.Time,
+) *coredata.Task {
+	return &coredata.Task{
+		ID:            taskID,
+		ControlID:     controlID,
+		Name:          t.Name,
+		State:         coredata.TaskStateTodo,
+		Description:   t.Description,
+		ContentRef:    t.ContentRef,
+		ContentDigest: ref.Ref(t.digest()),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+		TimeEstimate:  t.timeEstimate(),
+	}
+}
+
+func (t FrameworkDocumentTask) timeEstimate() *time.Duration {
+	if t.TimeEstimate <= 0 {
+		return nil
+	}
+
+	return ref.Ref(time.Duration(t.TimeEstimate) * time.Second)
+}
 
+func (t FrameworkDocumentTask) digest() string {
+	return taskContentDigest(t.Name, t.Description, t.timeEstimate())
+}
+
+func (s FrameworkService) ImportTemplate(
+	ctx context.Context,
+	organizationID gid.GID,
+	templateID string,
+) (*coredata.Framework, error) {
+	template, err := GetFrameworkTemplate(templateID)
 	if err != nil {
 		return nil, err
 	}
 
-	return framework, nil
+	return s.Import(ctx, organizationID, ImportFrameworkRequest{Data: template.Document})
 }
@@ -0,0 +1,111 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package probo
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"gearno.de/ref"
+	"github.com/getprobo/probo/pkg/coredata"
+	"github.com/getprobo/probo/pkg/gid"
+)
+
+func TestFrameworkDocumentRoundTrip(t *testing.T) {
+	tenantID := gid.NewTenantID()
+	organizationID := gid.New(tenantID, coredata.OrganizationEntityType)
+	frameworkID := gid.New(tenantID, coredata.FrameworkEntityType)
+	now := time.Now()
+
+	framework := &coredata.Framework{
+		ID:             frameworkID,
+		OrganizationID: organizationID,
+		Name:           "Internal policies",
+		Description:    "Framework created from the console",
+	}
+
+	controls := coredata.Controls{
+		{
+			ID:          gid.New(tenantID, coredata.ControlEntityType),
+			FrameworkID: frameworkID,
+			Name:        "Access reviews",
+			Category:    "Access",
+			Importance:  coredata.ControlImportanceMandatory,
+		},
+		{
+			ID:          gid.New(tenantID, coredata.ControlEntityType),
+			FrameworkID: frameworkID,
+			Name:        "Backups",
+			Importance:  coredata.ControlImportancePreferred,
+			Standards:   []string{"A.8.13"},
+			ContentRef:  "backups",
+		},
+		{
+			ID:          gid.New(tenantID, coredata.ControlEntityType),
+			FrameworkID: frameworkID,
+			Name:        "Moved backups",
+			Importance:  coredata.ControlImportanceAdvanced,
+			ContentRef:  "backups",
+		},
+	}
+
+	tasks := coredata.Tasks{
+		{
+			ID:           gid.New(tenantID, coredata.TaskEntityType),
+			ControlID:    controls[0].ID,
+			Name:         "Review access quarterly",
+			TimeEstimate: ref.Ref(2 * time.Hour),
+		},
+		{
+			ID:         gid.New(tenantID, coredata.TaskEntityType),
+			ControlID:  controls[1].ID,
+			Name:       "Test restores",
+			ContentRef: "backups",
+		},
+	}
+
+	exported := newFrameworkDocument(framework, controls, tasks)
+
+	data, err := json.Marshal(exported)
+	if err != nil {
+		t.Fatalf("cannot marshal exported document: %v", err)
+	}
+
+	parsed, err := ParseFrameworkDocument(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("exported document does not parse: %v", err)
+	}
+
+	importedFramework, importedControls, importedTasks, err := parsed.newFramework(organizationID, now)
+	if err != nil {
+		t.Fatalf("cannot import parsed document: %v", err)
+	}
+
+	if len(importedControls) != len(controls) {
+		t.Fatalf("expected %d imported controls, got %d", len(controls), len(importedControls))
+	}
+
+	if len(importedTasks) != len(tasks) {
+		t.Fatalf("expected %d imported tasks, got %d", len(tasks), len(importedTasks))
+	}
+
+	reexported := newFrameworkDocument(importedFramework, importedControls, importedTasks)
+	if !reflect.DeepEqual(exported, reexported) {
+		t.Fatalf("re-exported document differs:\nexported:   %+v\nreexported: %+v", exported, reexported)
+	}
+}
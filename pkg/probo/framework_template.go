@@ -0,0 +1,108 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package probo
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/getprobo/probo/data/frameworks"
+)
+
+type (
+	// FrameworkTemplate is a framework document embedded in the binary
+	// that organizations can import without uploading it. Its ID is the
+	// name of the template file.
+	FrameworkTemplate struct {
+		ID       string
+		Document FrameworkDocument
+	}
+)
+
+var (
+	loadFrameworkTemplates = sync.OnceValues(
+		func() ([]*FrameworkTemplate, error) {
+			return parseFrameworkTemplates(frameworks.Templates)
+		},
+	)
+)
+
+func (t FrameworkTemplate) ControlCount() int {
+	return len(t.Document.Framework.Controls)
+}
+
+func (t FrameworkTemplate) TaskCount() int {
+	count := 0
+	for _, control := range t.Document.Framework.Controls {
+		count += len(control.Tasks)
+	}
+
+	return count
+}
+
+// ListFrameworkTemplates returns the embedded framework templates sorted
+// by ID.
+func ListFrameworkTemplates() ([]*FrameworkTemplate, error) {
+	return loadFrameworkTemplates()
+}
+
+func GetFrameworkTemplate(templateID string) (*FrameworkTemplate, error) {
+	templates, err := loadFrameworkTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, template := range templates {
+		if template.ID == templateID {
+			return template, nil
+		}
+	}
+
+	return nil, fmt.Errorf("framework template %q not found", templateID)
+}
+
+func parseFrameworkTemplates(fsys fs.FS) ([]*FrameworkTemplate, error) {
+	filenames, err := fs.Glob(fsys, "*.yaml")
+	if err != nil {
+		return nil, fmt.Errorf("cannot list framework templates: %w", err)
+	}
+
+	sort.Strings(filenames)
+
+	templates := make([]*FrameworkTemplate, len(filenames))
+	for i, filename := range filenames {
+		data, err := fs.ReadFile(fsys, filename)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read framework template %q: %w", filename, err)
+		}
+
+		document, err := ParseFrameworkDocument(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse framework template %q: %w", filename, err)
+		}
+
+		templates[i] = &FrameworkTemplate{
+			ID:       strings.TrimSuffix(path.Base(filename), ".yaml"),
+			Document: *document,
+		}
+	}
+
+	return templates, nil
+}
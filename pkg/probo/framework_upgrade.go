@@ -0,0 +1,411 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package probo
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gearno.de/ref"
+	"github.com/getprobo/probo/pkg/coredata"
+	"github.com/getprobo/probo/pkg/gid"
+	"go.gearno.de/kit/pg"
+)
+
+type (
+	UpgradeFrameworkRequest struct {
+		FrameworkID gid.GID
+		Data        FrameworkDocument
+		DryRun      bool
+	}
+
+	FrameworkUpgradeAction uint8
+
+	FrameworkUpgradeItemKind uint8
+
+	// FrameworkUpgradeChange describes what an upgrade does, or would do
+	// on a dry-run, to one control or task.
+	FrameworkUpgradeChange struct {
+		Action     FrameworkUpgradeAction
+		Kind       FrameworkUpgradeItemKind
+		ID         *gid.GID
+		ContentRef string
+		Name       string
+	}
+
+	FrameworkUpgrade struct {
+		Framework *coredata.Framework
+		DryRun    bool
+		Changes   []FrameworkUpgradeChange
+	}
+
+	frameworkUpgradePlan struct {
+		FrameworkUpgrade
+
+		newControls     coredata.Controls
+		newTasks        coredata.Tasks
+		updatedControls coredata.Controls
+		updatedTasks    coredata.Tasks
+	}
+)
+
+const (
+	// FrameworkUpgradeActionAdd is for template items missing from the
+	// framework.
+	FrameworkUpgradeActionAdd FrameworkUpgradeAction = iota
+	// FrameworkUpgradeActionUpdate is for items whose content was not
+	// changed since the last import and is replaced by the template one.
+	FrameworkUpgradeActionUpdate
+	// FrameworkUpgradeActionKeep is for items customised locally whose
+	// template content changed; they are left as is for review.
+	FrameworkUpgradeActionKeep
+	// FrameworkUpgradeActionRemove is for items no longer part of the
+	// template. They are only flagged, never deleted, as they may carry
+	// state and evidence.
+	FrameworkUpgradeActionRemove
+)
+
+const (
+	FrameworkUpgradeItemKindControl FrameworkUpgradeItemKind = iota
+	FrameworkUpgradeItemKindTask
+)
+
+// Upgrade merges a newer version of the template the framework was
+// imported from. Controls are matched by content-ref and tasks by
+// content-ref, or by name when the template does not give one. State,
+// assignments and evidence are never touched.
+func (s FrameworkService) Upgrade(
+	ctx context.Context,
+	req UpgradeFrameworkRequest,
+) (*FrameworkUpgrade, error) {
+	var plan *frameworkUpgradePlan
+
+	err := s.svc.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+			framework := &coredata.Framework{}
+			if err := framework.LoadByID(ctx, tx, s.svc.scope, req.FrameworkID); err != nil {
+				return fmt.Errorf("cannot load framework: %w", err)
+			}
+
+			if framework.ContentRef == "" || framework.ContentRef != req.Data.Framework.ContentRef {
+				return fmt.Errorf("template content-ref %q does not match the framework one", req.Data.Framework.ContentRef)
+			}
+
+			controls := coredata.Controls{}
+			if err := controls.LoadAllByFrameworkID(ctx, tx, s.svc.scope, framework.ID); err != nil {
+				return fmt.Errorf("cannot load controls: %w", err)
+			}
+
+			tasks := coredata.Tasks{}
+			if err := tasks.LoadAllByFrameworkID(ctx, tx, s.svc.scope, framework.ID); err != nil {
+				return fmt.Errorf("cannot load tasks: %w", err)
+			}
+
+			var err error
+			plan, err = planFrameworkUpgrade(framework, controls, tasks, req.Data, time.Now())
+			if err != nil {
+				return err
+			}
+
+			plan.DryRun = req.DryRun
+			if req.DryRun {
+				return nil
+			}
+
+			for _, control := range plan.newControls {
+				if err := control.Insert(ctx, tx, s.svc.scope); err != nil {
+					return fmt.Errorf("cannot insert control: %w", err)
+				}
+			}
+
+			for _, control := range plan.updatedControls {
+				if err := control.UpdateTemplateContent(ctx, tx, s.svc.scope); err != nil {
+					return fmt.Errorf("cannot update control: %w", err)
+				}
+			}
+
+			for _, task := range plan.newTasks {
+				if err := task.Insert(ctx, tx, s.svc.scope); err != nil {
+					return fmt.Errorf("cannot insert task: %w", err)
+				}
+			}
+
+			for _, task := range plan.updatedTasks {
+				if err := task.UpdateTemplateContent(ctx, tx, s.svc.scope); err != nil {
+					return fmt.Errorf("cannot update task: %w", err)
+				}
+			}
+
+			return nil
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &plan.FrameworkUpgrade, nil
+}
+
+func planFrameworkUpgrade(
+	framework *coredata.Framework,
+	controls coredata.Controls,
+	tasks coredata.Tasks,
+	document FrameworkDocument,
+	now time.Time,
+) (*frameworkUpgradePlan, error) {
+	tenantID := framework.ID.TenantID()
+	plan := &frameworkUpgradePlan{
+		FrameworkUpgrade: FrameworkUpgrade{
+			Framework: framework,
+			Changes:   []FrameworkUpgradeChange{},
+		},
+	}
+
+	controlsByContentRef := make(map[string]*coredata.Control)
+	for _, control := range controls {
+		if control.ContentRef != "" {
+			controlsByContentRef[control.ContentRef] = control
+		}
+	}
+
+	tasksByControlID := make(map[gid.GID]coredata.Tasks)
+	for _, task := range tasks {
+		tasksByControlID[task.ControlID] = append(tasksByControlID[task.ControlID], task)
+	}
+
+	// Controls added by the template go after the existing ones, which
+	// may have been reordered since the import.
+	nextPosition := 0
+	for _, control := range controls {
+		nextPosition = max(nextPosition, control.Position+1)
+	}
+
+	seen := make(map[string]bool)
+	for _, templateControl := range document.Framework.Controls {
+		if templateControl.ContentRef == "" {
+			return nil, fmt.Errorf("control %q has no content-ref", templateControl.Name)
+		}
+
+		if seen[templateControl.ContentRef] {
+			return nil, fmt.Errorf("duplicate control content-ref %q", templateControl.ContentRef)
+		}
+		seen[templateControl.ContentRef] = true
+
+		control, ok := controlsByContentRef[templateControl.ContentRef]
+		if !ok {
+			controlID, err := gid.NewGID(tenantID, coredata.ControlEntityType)
+			if err != nil {
+				return nil, fmt.Errorf("cannot create global id: %w", err)
+			}
+
+			plan.newControls = append(plan.newControls, templateControl.newControl(controlID, framework.ID, nextPosition, now))
+			nextPosition++
+			plan.change(FrameworkUpgradeActionAdd, FrameworkUpgradeItemKindControl, nil, templateControl.ContentRef, templateControl.Name)
+
+			for _, templateTask := range templateControl.Tasks {
+				if err := plan.addTask(tenantID, controlID, templateTask, now); err != nil {
+					return nil, err
+				}
+			}
+
+			continue
+		}
+
+		digest := templateControl.digest()
+		current := controlContentDigest(control.Category, control.Name, control.Description, control.Importance, control.Standards)
+
+		switch {
+		case current == digest:
+			if control.ContentDigest == nil || *control.ContentDigest != digest {
+				control.ContentDigest = ref.Ref(digest)
+				control.UpdatedAt = now
+				plan.updatedControls = append(plan.updatedControls, control)
+			}
+		case control.ContentDigest != nil && *control.ContentDigest == current:
+			control.Category = templateControl.Category
+			control.Name = templateControl.Name
+			control.Description = templateControl.Description
+			control.Importance = templateControl.Importance
+			control.Standards = templateControl.Standards
+			control.ContentDigest = ref.Ref(digest)
+			control.UpdatedAt = now
+			plan.updatedControls = append(plan.updatedControls, control)
+			plan.change(FrameworkUpgradeActionUpdate, FrameworkUpgradeItemKindControl, &control.ID, control.ContentRef, control.Name)
+		default:
+			plan.change(FrameworkUpgradeActionKeep, FrameworkUpgradeItemKindControl, &control.ID, control.ContentRef, control.Name)
+		}
+
+		if err := plan.mergeTasks(tenantID, control.ID, tasksByControlID[control.ID], templateControl.Tasks, now); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, control := range controls {
+		if control.ContentRef != "" && !seen[control.ContentRef] {
+			plan.change(FrameworkUpgradeActionRemove, FrameworkUpgradeItemKindControl, &control.ID, control.ContentRef, control.Name)
+		}
+	}
+
+	return plan, nil
+}
+
+func (p *frameworkUpgradePlan) mergeTasks(
+	tenantID gid.TenantID,
+	controlID gid.GID,
+	tasks coredata.Tasks,
+	templateTasks []FrameworkDocumentTask,
+	now time.Time,
+) error {
+	matched := make(map[gid.GID]bool)
+
+	for _, templateTask := range templateTasks {
+		var task *coredata.Task
+		for _, candidate := range tasks {
+			if matched[candidate.ID] {
+				continue
+			}
+
+			if templateTask.ContentRef != "" && candidate.ContentRef == templateTask.ContentRef ||
+				templateTask.ContentRef == "" && candidate.ContentRef == "" && candidate.Name == templateTask.Name {
+				task = candidate
+				break
+			}
+		}
+
+		if task == nil {
+			if err := p.addTask(tenantID, controlID, templateTask, now); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		matched[task.ID] = true
+
+		digest := templateTask.digest()
+		current := taskContentDigest(task.Name, task.Description, task.TimeEstimate)
+
+		switch {
+		case current == digest:
+			if task.ContentDigest == nil || *task.ContentDigest != digest {
+				task.ContentDigest = ref.Ref(digest)
+				task.UpdatedAt = now
+				p.updatedTasks = append(p.updatedTasks, task)
+			}
+		case task.ContentDigest != nil && *task.ContentDigest == current:
+			task.Name = templateTask.Name
+			task.Description = templateTask.Description
+			task.TimeEstimate = templateTask.timeEstimate()
+			task.ContentDigest = ref.Ref(digest)
+			task.UpdatedAt = now
+			p.updatedTasks = append(p.updatedTasks, task)
+			p.change(FrameworkUpgradeActionUpdate, FrameworkUpgradeItemKindTask, &task.ID, task.ContentRef, task.Name)
+		default:
+			p.change(FrameworkUpgradeActionKeep, FrameworkUpgradeItemKindTask, &task.ID, task.ContentRef, task.Name)
+		}
+	}
+
+	// Only tasks that came from a template are flagged, tasks added by
+	// hand to a template control are none of the template business.
+	for _, task := range tasks {
+		if matched[task.ID] || (task.ContentRef == "" && task.ContentDigest == nil) {
+			continue
+		}
+
+		p.change(FrameworkUpgradeActionRemove, FrameworkUpgradeItemKindTask, &task.ID, task.ContentRef, task.Name)
+	}
+
+	return nil
+}
+
+func (p *frameworkUpgradePlan) addTask(
+	tenantID gid.TenantID,
+	controlID gid.GID,
+	templateTask FrameworkDocumentTask,
+	now time.Time,
+) error {
+	taskID, err := gid.NewGID(tenantID, coredata.TaskEntityType)
+	if err != nil {
+		return fmt.Errorf("cannot create global id: %w", err)
+	}
+
+	p.newTasks = append(p.newTasks, templateTask.newTask(taskID, controlID, now))
+	p.change(FrameworkUpgradeActionAdd, FrameworkUpgradeItemKindTask, nil, templateTask.ContentRef, templateTask.Name)
+
+	return nil
+}
+
+func (p *frameworkUpgradePlan) change(
+	action FrameworkUpgradeAction,
+	kind FrameworkUpgradeItemKind,
+	id *gid.GID,
+	contentRef string,
+	name string,
+) {
+	p.Changes = append(
+		p.Changes,
+		FrameworkUpgradeChange{
+			Action:     action,
+			Kind:       kind,
+			ID:         id,
+			ContentRef: contentRef,
+			Name:       name,
+		},
+	)
+}
+
+// contentDigest identifies template content so upgrades can tell whether
+// an item was edited since it was last imported.
+func contentDigest(fields ...any) string {
+	h := sha256.New()
+	if err := json.NewEncoder(h).Encode(fields); err != nil {
+		panic(fmt.Errorf("cannot encode content: %w", err))
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func controlContentDigest(
+	category string,
+	name string,
+	description string,
+	importance coredata.ControlImportance,
+	standards []string,
+) string {
+	if standards == nil {
+		standards = []string{}
+	}
+
+	return contentDigest(category, name, description, importance, standards)
+}
+
+func taskContentDigest(
+	name string,
+	description string,
+	timeEstimate *time.Duration,
+) string {
+	var seconds int64
+	if timeEstimate != nil {
+		seconds = int64(timeEstimate.Seconds())
+	}
+
+	return contentDigest(name, description, seconds)
+}
@@ -0,0 +1,193 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package probo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/getprobo/probo/pkg/coredata"
+	"github.com/getprobo/probo/pkg/gid"
+	"go.gearno.de/kit/pg"
+)
+
+type (
+	OIDCConfigurationService struct {
+		svc *TenantService
+	}
+
+	ConfigureOIDCRequest struct {
+		OrganizationID      gid.GID
+		IssuerURL           string
+		ClientID            string
+		ClientSecret        *string
+		AllowedEmailDomains []string
+		Enabled             bool
+	}
+)
+
+func (s OIDCConfigurationService) Get(
+	ctx context.Context,
+	organizationID gid.GID,
+) (*coredata.OIDCConfiguration, error) {
+	configuration := &coredata.OIDCConfiguration{}
+
+	err := s.svc.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			return configuration.LoadByOrganizationID(ctx, conn, s.svc.scope, organizationID)
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return configuration, nil
+}
+
+// Configure creates or replaces the OIDC configuration of the
+// organization. The client secret is kept unchanged when the request does
+// not provide one.
+func (s OIDCConfigurationService) Configure(
+	ctx context.Context,
+	req ConfigureOIDCRequest,
+) (*coredata.OIDCConfiguration, error) {
+	issuerURL, err := url.Parse(req.IssuerURL)
+	if err != nil || (issuerURL.Scheme != "https" && issuerURL.Scheme != "http") || issuerURL.Host == "" {
+		return nil, fmt.Errorf("invalid issuer url %q", req.IssuerURL)
+	}
+
+	if req.ClientID == "" {
+		return nil, fmt.Errorf("client id is required")
+	}
+
+	domains, err := normalizeEmailDomains(req.AllowedEmailDomains)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(domains) == 0 {
+		return nil, fmt.Errorf("at least one allowed email domain is required")
+	}
+
+	now := time.Now()
+	configuration := &coredata.OIDCConfiguration{}
+
+	err = s.svc.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+			organization := &coredata.Organization{}
+			if err := organization.LoadByID(ctx, tx, s.svc.scope, req.OrganizationID); err != nil {
+				return fmt.Errorf("cannot load organization: %w", err)
+			}
+
+			if err := ensureEmailDomainsVerified(ctx, tx, s.svc.scope, organization.ID, domains); err != nil {
+				return err
+			}
+
+			err := configuration.LoadByOrganizationID(ctx, tx, s.svc.scope, req.OrganizationID)
+			if err != nil {
+				var errNotFound *coredata.ErrOIDCConfigurationNotFound
+				if !errors.As(err, &errNotFound) {
+					return fmt.Errorf("cannot load oidc configuration: %w", err)
+				}
+
+				configuration = &coredata.OIDCConfiguration{
+					ID:             gid.New(s.svc.scope.GetTenantID(), coredata.OIDCConfigurationEntityType),
+					TenantID:       s.svc.scope.GetTenantID(),
+					OrganizationID: organization.ID,
+					CreatedAt:      now,
+				}
+			}
+
+			if req.ClientSecret != nil {
+				configuration.ClientSecret = *req.ClientSecret
+			}
+
+			if configuration.ClientSecret == "" {
+				return fmt.Errorf("client secret is required")
+			}
+
+			configuration.IssuerURL = strings.TrimSuffix(req.IssuerURL, "/")
+			configuration.ClientID = req.ClientID
+			configuration.AllowedEmailDomains = domains
+			configuration.Enabled = req.Enabled
+			configuration.UpdatedAt = now
+
+			if err := configuration.Upsert(ctx, tx); err != nil {
+				return fmt.Errorf("cannot upsert oidc configuration: %w", err)
+			}
+
+			return nil
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return configuration, nil
+}
+
+func (s OIDCConfigurationService) Delete(
+	ctx context.Context,
+	organizationID gid.GID,
+) (*coredata.OIDCConfiguration, error) {
+	configuration := &coredata.OIDCConfiguration{}
+
+	err := s.svc.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+			if err := configuration.LoadByOrganizationID(ctx, tx, s.svc.scope, organizationID); err != nil {
+				return fmt.Errorf("cannot load oidc configuration: %w", err)
+			}
+
+			if err := configuration.Delete(ctx, tx, s.svc.scope); err != nil {
+				return fmt.Errorf("cannot delete oidc configuration: %w", err)
+			}
+
+			return nil
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return configuration, nil
+}
+
+// normalizeEmailDomains validates the email domains an identity provider
+// is allowed to assert. At least one is required: without it the
+// identity provider of an organization could sign in as any user. The
+// organization must also have verified it owns each of them, see
+// ensureEmailDomainsVerified.
+func normalizeEmailDomains(allowedEmailDomains []string) ([]string, error) {
+	domains := make([]string, 0, len(allowedEmailDomains))
+	for _, domain := range allowedEmailDomains {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if domain == "" || strings.Contains(domain, "@") {
+			return nil, fmt.Errorf("invalid email domain %q", domain)
+		}
+		domains = append(domains, domain)
+	}
+
+	return domains, nil
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"slices"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -38,9 +39,14 @@ type (
 	}
 
 	UpdateOrganizationRequest struct {
-		ID   gid.GID
-		Name *string
-		File io.Reader
+		ID          gid.GID
+		Name        *string
+		File        io.Reader
+		MFARequired *bool
+		// AutoJoinEmailDomains replaces the auto-join domains when set,
+		// an empty list disables auto-join. Each domain must have been
+		// verified, see EmailDomainService.
+		AutoJoinEmailDomains *[]string
 	}
 )
 
@@ -55,11 +61,13 @@ func (s OrganizationService) Create(
 	}
 
 	organization := &coredata.Organization{
-		ID:        organizationID,
-		TenantID:  organizationID.TenantID(),
-		Name:      req.Name,
-		CreatedAt: now,
-		UpdatedAt: now,
+		ID:       organizationID,
+		TenantID: organizationID.TenantID(),
+		Name:     req.Name,
+		// Organizations are invitation-only until an owner opts in.
+		AutoJoinEmailDomains: []string{},
+		CreatedAt:            now,
+		UpdatedAt:            now,
 	}
 
 	err = s.svc.pg.WithConn(
@@ -105,6 +113,26 @@ func (s OrganizationService) Get(
 	return organization, nil
 }
 
+func (s OrganizationService) GetReadiness(
+	ctx context.Context,
+	organizationID gid.GID,
+) (*coredata.Readiness, error) {
+	readiness := &coredata.Readiness{}
+
+	err := s.svc.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			return readiness.LoadByOrganizationID(ctx, conn, s.svc.scope, organizationID)
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return readiness, nil
+}
+
 func (s OrganizationService) Update(
 	ctx context.Context,
 	req UpdateOrganizationRequest,
@@ -124,6 +152,23 @@ func (s OrganizationService) Update(
 				organization.Name = *req.Name
 			}
 
+			if req.MFARequired != nil {
+				organization.MFARequired = *req.MFARequired
+			}
+
+			if req.AutoJoinEmailDomains != nil {
+				domains, err := normalizeEmailDomains(*req.AutoJoinEmailDomains)
+				if err != nil {
+					return err
+				}
+
+				if err := ensureEmailDomainsVerified(ctx, conn, s.svc.scope, req.ID, domains); err != nil {
+					return err
+				}
+
+				organization.AutoJoinEmailDomains = slices.Compact(slices.Sorted(slices.Values(domains)))
+			}
+
 			if req.File != nil {
 				objectKey, err := uuid.NewV7()
 				if err != nil {
@@ -0,0 +1,184 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package probo
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/crewjam/saml"
+	"github.com/getprobo/probo/pkg/coredata"
+	"github.com/getprobo/probo/pkg/gid"
+	"go.gearno.de/kit/pg"
+)
+
+type (
+	SAMLConfigurationService struct {
+		svc *TenantService
+	}
+
+	ConfigureSAMLRequest struct {
+		OrganizationID      gid.GID
+		IdPMetadata         string
+		EmailAttribute      *string
+		FullNameAttribute   *string
+		AllowedEmailDomains []string
+		Enabled             bool
+	}
+)
+
+const (
+	defaultSAMLEmailAttribute    = "http://schemas.xmlsoap.org/ws/2005/05/identity/claims/emailaddress"
+	defaultSAMLFullNameAttribute = "http://schemas.microsoft.com/identity/claims/displayname"
+)
+
+func (s SAMLConfigurationService) Get(
+	ctx context.Context,
+	organizationID gid.GID,
+) (*coredata.SAMLConfiguration, error) {
+	configuration := &coredata.SAMLConfiguration{}
+
+	err := s.svc.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			return configuration.LoadByOrganizationID(ctx, conn, s.svc.scope, organizationID)
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return configuration, nil
+}
+
+// Configure creates or replaces the SAML configuration of the
+// organization from the metadata document published by its identity
+// provider.
+func (s SAMLConfigurationService) Configure(
+	ctx context.Context,
+	req ConfigureSAMLRequest,
+) (*coredata.SAMLConfiguration, error) {
+	var metadata saml.EntityDescriptor
+	if err := xml.Unmarshal([]byte(req.IdPMetadata), &metadata); err != nil {
+		return nil, fmt.Errorf("invalid identity provider metadata: %w", err)
+	}
+
+	if metadata.EntityID == "" || len(metadata.IDPSSODescriptors) == 0 {
+		return nil, fmt.Errorf("invalid identity provider metadata: missing entity id or sso descriptor")
+	}
+
+	domains, err := normalizeEmailDomains(req.AllowedEmailDomains)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(domains) == 0 {
+		return nil, fmt.Errorf("at least one allowed email domain is required")
+	}
+
+	emailAttribute := defaultSAMLEmailAttribute
+	if req.EmailAttribute != nil && *req.EmailAttribute != "" {
+		emailAttribute = *req.EmailAttribute
+	}
+
+	fullNameAttribute := defaultSAMLFullNameAttribute
+	if req.FullNameAttribute != nil && *req.FullNameAttribute != "" {
+		fullNameAttribute = *req.FullNameAttribute
+	}
+
+	now := time.Now()
+	configuration := &coredata.SAMLConfiguration{}
+
+	err = s.svc.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+			organization := &coredata.Organization{}
+			if err := organization.LoadByID(ctx, tx, s.svc.scope, req.OrganizationID); err != nil {
+				return fmt.Errorf("cannot load organization: %w", err)
+			}
+
+			if err := ensureEmailDomainsVerified(ctx, tx, s.svc.scope, organization.ID, domains); err != nil {
+				return err
+			}
+
+			err := configuration.LoadByOrganizationID(ctx, tx, s.svc.scope, req.OrganizationID)
+			if err != nil {
+				var errNotFound *coredata.ErrSAMLConfigurationNotFound
+				if !errors.As(err, &errNotFound) {
+					return fmt.Errorf("cannot load saml configuration: %w", err)
+				}
+
+				configuration = &coredata.SAMLConfiguration{
+					ID:             gid.New(s.svc.scope.GetTenantID(), coredata.SAMLConfigurationEntityType),
+					TenantID:       s.svc.scope.GetTenantID(),
+					OrganizationID: organization.ID,
+					CreatedAt:      now,
+				}
+			}
+
+			configuration.IdPEntityID = metadata.EntityID
+			configuration.IdPMetadata = req.IdPMetadata
+			configuration.EmailAttribute = emailAttribute
+			configuration.FullNameAttribute = fullNameAttribute
+			configuration.AllowedEmailDomains = domains
+			configuration.Enabled = req.Enabled
+			configuration.UpdatedAt = now
+
+			if err := configuration.Upsert(ctx, tx); err != nil {
+				return fmt.Errorf("cannot upsert saml configuration: %w", err)
+			}
+
+			return nil
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return configuration, nil
+}
+
+func (s SAMLConfigurationService) Delete(
+	ctx context.Context,
+	organizationID gid.GID,
+) (*coredata.SAMLConfiguration, error) {
+	configuration := &coredata.SAMLConfiguration{}
+
+	err := s.svc.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+			if err := configuration.LoadByOrganizationID(ctx, tx, s.svc.scope, organizationID); err != nil {
+				return fmt.Errorf("cannot load saml configuration: %w", err)
+			}
+
+			if err := configuration.Delete(ctx, tx, s.svc.scope); err != nil {
+				return fmt.Errorf("cannot delete saml configuration: %w", err)
+			}
+
+			return nil
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return configuration, nil
+}
@@ -46,6 +46,11 @@ type (
 		Peoples       *PeopleService
 		Organizations *OrganizationService
 		Vendors       *VendorService
+		OIDC          *OIDCConfigurationService
+		SAML          *SAMLConfigurationService
+
+		StandardMappings *StandardMappingService
+		EmailDomains     *EmailDomainService
 	}
 )
 
@@ -84,6 +89,10 @@ func (s *Service) WithTenant(tenantID gid.TenantID) *TenantService {
 	tenantService.Peoples = &PeopleService{svc: tenantService}
 	tenantService.Organizations = &OrganizationService{svc: tenantService}
 	tenantService.Vendors = &VendorService{svc: tenantService}
+	tenantService.OIDC = &OIDCConfigurationService{svc: tenantService}
+	tenantService.SAML = &SAMLConfigurationService{svc: tenantService}
+	tenantService.StandardMappings = &StandardMappingService{svc: tenantService}
+	tenantService.EmailDomains = &EmailDomainService{svc: tenantService}
 
 	return tenantService
 }
@@ -0,0 +1,159 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package probo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/getprobo/probo/pkg/coredata"
+	"github.com/getprobo/probo/pkg/gid"
+	"go.gearno.de/kit/pg"
+)
+
+type (
+	StandardMappingService struct {
+		svc *TenantService
+	}
+
+	CreateStandardMappingRequest struct {
+		OrganizationID    gid.GID
+		SourceFrameworkID gid.GID
+		SourceStandard    string
+		TargetFrameworkID gid.GID
+		TargetStandard    string
+	}
+)
+
+// Create maps the standard reference of a framework to the standard
+// reference of another framework, so the controls referencing one cover
+// the controls referencing the other. The mapping is symmetric. The
+// standards of the framework templates shipped with Probo are already
+// mapped by crosswalks, such as SOC 2 to ISO 27001.
+func (s StandardMappingService) Create(
+	ctx context.Context,
+	req CreateStandardMappingRequest,
+) (*coredata.StandardMapping, error) {
+	sourceFrameworkID, source := req.SourceFrameworkID, strings.TrimSpace(req.SourceStandard)
+	targetFrameworkID, target := req.TargetFrameworkID, strings.TrimSpace(req.TargetStandard)
+
+	if source == "" || target == "" {
+		return nil, fmt.Errorf("standard references cannot be empty")
+	}
+
+	if sourceFrameworkID == targetFrameworkID {
+		return nil, fmt.Errorf("cannot map standards of the same framework")
+	}
+
+	if targetFrameworkID.String() < sourceFrameworkID.String() {
+		sourceFrameworkID, targetFrameworkID = targetFrameworkID, sourceFrameworkID
+		source, target = target, source
+	}
+
+	standardMappingID, err := gid.NewGID(s.svc.scope.GetTenantID(), coredata.StandardMappingEntityType)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create global id: %w", err)
+	}
+
+	standardMapping := &coredata.StandardMapping{
+		ID:                standardMappingID,
+		OrganizationID:    req.OrganizationID,
+		SourceFrameworkID: sourceFrameworkID,
+		SourceStandard:    source,
+		TargetFrameworkID: targetFrameworkID,
+		TargetStandard:    target,
+		CreatedAt:         time.Now(),
+	}
+
+	err = s.svc.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			for _, frameworkID := range []gid.GID{sourceFrameworkID, targetFrameworkID} {
+				framework := &coredata.Framework{}
+				if err := framework.LoadByID(ctx, conn, s.svc.scope, frameworkID); err != nil {
+					return fmt.Errorf("cannot load framework %q: %w", frameworkID, err)
+				}
+
+				if framework.OrganizationID != req.OrganizationID {
+					return fmt.Errorf("framework %q does not belong to the organization", frameworkID)
+				}
+			}
+
+			return standardMapping.Insert(ctx, conn, s.svc.scope)
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return standardMapping, nil
+}
+
+func (s StandardMappingService) Get(
+	ctx context.Context,
+	standardMappingID gid.GID,
+) (*coredata.StandardMapping, error) {
+	standardMapping := &coredata.StandardMapping{}
+
+	err := s.svc.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			return standardMapping.LoadByID(ctx, conn, s.svc.scope, standardMappingID)
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return standardMapping, nil
+}
+
+func (s StandardMappingService) Delete(
+	ctx context.Context,
+	standardMappingID gid.GID,
+) error {
+	standardMapping := &coredata.StandardMapping{ID: standardMappingID}
+
+	return s.svc.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			return standardMapping.Delete(ctx, conn, s.svc.scope)
+		},
+	)
+}
+
+func (s StandardMappingService) ListForOrganizationID(
+	ctx context.Context,
+	organizationID gid.GID,
+) (coredata.StandardMappings, error) {
+	var standardMappings coredata.StandardMappings
+
+	err := s.svc.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			return standardMappings.LoadByOrganizationID(ctx, conn, s.svc.scope, organizationID)
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return standardMappings, nil
+}
@@ -46,6 +46,11 @@ type (
 		Description     *string
 		State           *coredata.TaskState
 		TimeEstimate    *time.Duration
+		// StateTransitionReason explains the change of state, if any.
+		StateTransitionReason *string
+		// ChangedByID is the user making the change, recorded in the
+		// state transition history.
+		ChangedByID gid.GID
 	}
 )
 
@@ -173,7 +178,39 @@ func (s TaskService) Update(
 	err := s.svc.pg.WithTx(
 		ctx,
 		func(conn pg.Conn) error {
-			return task.Update(ctx, conn, s.svc.scope, params)
+			previous := &coredata.Task{}
+			if err := previous.LoadByID(ctx, conn, s.svc.scope, req.ID); err != nil {
+				return fmt.Errorf("cannot load task: %w", err)
+			}
+
+			if err := task.Update(ctx, conn, s.svc.scope, params); err != nil {
+				return err
+			}
+
+			if task.State == previous.State {
+				return nil
+			}
+
+			transitionID, err := gid.NewGID(s.svc.scope.GetTenantID(), coredata.TaskStateTransitionEntityType)
+			if err != nil {
+				return fmt.Errorf("cannot create global id: %w", err)
+			}
+
+			transition := &coredata.TaskStateTransition{
+				ID:          transitionID,
+				TaskID:      task.ID,
+				FromState:   previous.State,
+				ToState:     task.State,
+				Reason:      req.StateTransitionReason,
+				ChangedByID: &req.ChangedByID,
+				CreatedAt:   time.Now(),
+			}
+
+			if err := transition.Insert(ctx, conn, s.svc.scope); err != nil {
+				return fmt.Errorf("cannot insert task state transition: %w", err)
+			}
+
+			return nil
 		})
 	if err != nil {
 		return nil, err
@@ -182,6 +219,33 @@ func (s TaskService) Update(
 	return task, nil
 }
 
+func (s TaskService) ListStateTransitions(
+	ctx context.Context,
+	taskID gid.GID,
+	cursor *page.Cursor[coredata.StateTransitionOrderField],
+) (*page.Page[*coredata.TaskStateTransition, coredata.StateTransitionOrderField], error) {
+	var transitions coredata.TaskStateTransitions
+
+	err := s.svc.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			return transitions.LoadByTaskID(
+				ctx,
+				conn,
+				s.svc.scope,
+				taskID,
+				cursor,
+			)
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return page.NewPage(transitions, cursor), nil
+}
+
 func (s TaskService) ListForControlID(
 	ctx context.Context,
 	controlID gid.GID,
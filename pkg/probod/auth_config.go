@@ -17,13 +17,29 @@ package probod
 import (
 	"encoding/base64"
 	"fmt"
+	"strings"
+
+	"github.com/getprobo/probo/pkg/crypto/keyring"
+	"github.com/getprobo/probo/pkg/crypto/passwdhash"
 )
 
 type (
 	authConfig struct {
 		Cookie        cookieConfig   `json:"cookie"`
+		Token         tokenConfig    `json:"token"`
 		Password      passwordConfig `json:"password"`
 		DisableSignup bool           `json:"disable-signup"`
+		// SignupAllowedEmailDomains restricts self-service signup to
+		// users of these email domains, signup is open to any domain when
+		// empty. Invited and SSO users are not affected.
+		SignupAllowedEmailDomains []string `json:"signup-allowed-email-domains"`
+		// ClientIPHeader is the header in which the reverse proxy in
+		// front of probod forwards the client address (e.g.
+		// X-Forwarded-For), used to throttle sign-in attempts. It must
+		// only be set when the proxy overwrites or appends to the header,
+		// otherwise clients can forge it. The connection address is used
+		// when empty.
+		ClientIPHeader string `json:"client-ip-header"`
 	}
 
 	cookieConfig struct {
@@ -31,48 +47,154 @@ type (
 		Secret   string `json:"secret"`
 		Duration int    `json:"duration"`
 		Name     string `json:"name"`
+		// RetiredSecrets lists the secrets used before the current one,
+		// cookies signed with them stay valid until they expire.
+		RetiredSecrets []string `json:"retired-secrets"`
+	}
+
+	// tokenConfig configures the secret used to sign the stateless tokens
+	// sent in emails or kept during login flows (email confirmation,
+	// invitations, password reset, MFA challenge...). The secret is
+	// required and must differ from the cookie secret.
+	tokenConfig struct {
+		Secret string `json:"secret"`
+		// RetiredSecrets lists the secrets used before the current one,
+		// tokens signed with them stay valid until they expire. When
+		// upgrading from a release signing tokens with the cookie secret,
+		// list the cookie secret here so in-flight invitations and reset
+		// links keep working, and remove it once they expired.
+		RetiredSecrets []string `json:"retired-secrets"`
 	}
 
 	passwordConfig struct {
 		Iterations uint32 `json:"iterations"`
 		Pepper     string `json:"pepper"`
+		// PreviousPeppers lists the peppers used before the current one,
+		// they are only used to verify existing password hashes which get
+		// upgraded to the current pepper on sign-in.
+		PreviousPeppers []string       `json:"previous-peppers"`
+		Algorithm       string         `json:"algorithm"`
+		Argon2id        argon2idConfig `json:"argon2id"`
+	}
+
+	argon2idConfig struct {
+		Memory  uint32 `json:"memory"`
+		Time    uint32 `json:"time"`
+		Threads uint8  `json:"threads"`
 	}
 )
 
 func (c authConfig) GetPepperBytes() ([]byte, error) {
-	if c.Password.Pepper == "" {
-		return nil, fmt.Errorf("pepper cannot be empty")
+	return decodeSecret("pepper", c.Password.Pepper)
+}
+
+func (c authConfig) GetPreviousPeppersBytes() ([][]byte, error) {
+	peppers := make([][]byte, len(c.Password.PreviousPeppers))
+
+	for i, pepper := range c.Password.PreviousPeppers {
+		decoded, err := decodeSecret("pepper", pepper)
+		if err != nil {
+			return nil, fmt.Errorf("invalid previous pepper %d: %w", i, err)
+		}
+
+		peppers[i] = decoded
 	}
 
-	if decoded, err := base64.StdEncoding.DecodeString(c.Password.Pepper); err == nil {
-		if len(decoded) < 32 {
-			return nil, fmt.Errorf("decoded pepper must be at least 32 bytes long")
+	return peppers, nil
+}
+
+func (c authConfig) GetPasswordHashOptions() ([]passwdhash.Option, error) {
+	previousPeppers, err := c.GetPreviousPeppersBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []passwdhash.Option{
+		passwdhash.WithPreviousPeppers(previousPeppers...),
+	}
+
+	switch c.Password.Algorithm {
+	case "", "pbkdf2":
+	case "argon2id":
+		opts = append(
+			opts,
+			passwdhash.WithArgon2id(
+				passwdhash.Argon2idParams{
+					Memory:  c.Password.Argon2id.Memory,
+					Time:    c.Password.Argon2id.Time,
+					Threads: c.Password.Argon2id.Threads,
+				},
+			),
+		)
+	default:
+		return nil, fmt.Errorf("unsupported password algorithm %q", c.Password.Algorithm)
+	}
+
+	return opts, nil
+}
+
+func (c authConfig) GetSignupAllowedEmailDomains() ([]string, error) {
+	domains := make([]string, len(c.SignupAllowedEmailDomains))
+
+	for i, domain := range c.SignupAllowedEmailDomains {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if domain == "" || strings.Contains(domain, "@") {
+			return nil, fmt.Errorf("invalid signup allowed email domain %q", domain)
 		}
-		return decoded, nil
+
+		domains[i] = domain
+	}
+
+	return domains, nil
+}
+
+func (c authConfig) GetCookieKeyring() (*keyring.Keyring, error) {
+	return newSecretKeyring("cookie secret", c.Cookie.Secret, c.Cookie.RetiredSecrets)
+}
+
+func (c authConfig) GetTokenKeyring() (*keyring.Keyring, error) {
+	if c.Token.Secret != "" && c.Token.Secret == c.Cookie.Secret {
+		return nil, fmt.Errorf("token secret must differ from the cookie secret")
 	}
 
-	if len(c.Password.Pepper) < 32 {
-		return nil, fmt.Errorf("pepper must be at least 32 bytes long")
+	return newSecretKeyring("token secret", c.Token.Secret, c.Token.RetiredSecrets)
+}
+
+// newSecretKeyring validates the secrets and builds a keyring from them.
+// Secrets are used as configured to sign, so signatures made before the
+// keyring was introduced stay valid.
+func newSecretKeyring(name, secret string, retiredSecrets []string) (*keyring.Keyring, error) {
+	if _, err := decodeSecret(name, secret); err != nil {
+		return nil, err
+	}
+
+	retired := make([][]byte, len(retiredSecrets))
+	for i, retiredSecret := range retiredSecrets {
+		if _, err := decodeSecret("retired "+name, retiredSecret); err != nil {
+			return nil, err
+		}
+
+		retired[i] = []byte(retiredSecret)
 	}
 
-	return []byte(c.Password.Pepper), nil
+	return keyring.New([]byte(secret), retired...)
 }
 
-func (c authConfig) GetCookieSecretBytes() ([]byte, error) {
-	if c.Cookie.Secret == "" {
-		return nil, fmt.Errorf("cookie secret cannot be empty")
+func decodeSecret(name, secret string) ([]byte, error) {
+	if secret == "" {
+		return nil, fmt.Errorf("%s cannot be empty", name)
 	}
 
-	if decoded, err := base64.StdEncoding.DecodeString(c.Cookie.Secret); err == nil {
+	if decoded, err := base64.StdEncoding.DecodeString(secret); err == nil {
 		if len(decoded) < 32 {
-			return nil, fmt.Errorf("decoded cookie secret must be at least 32 bytes long")
+			return nil, fmt.Errorf("decoded %s must be at least 32 bytes long", name)
 		}
 		return decoded, nil
 	}
 
-	if len(c.Cookie.Secret) < 32 {
-		return nil, fmt.Errorf("cookie secret must be at least 32 bytes long")
+	if len(secret) < 32 {
+		return nil, fmt.Errorf("%s must be at least 32 bytes long", name)
 	}
 
-	return []byte(c.Cookie.Secret), nil
+	return []byte(secret), nil
 }
@@ -80,6 +80,11 @@ func New() *Implm {
 				Password: passwordConfig{
 					Pepper:     "this-is-a-secure-pepper-for-password-hashing-at-least-32-bytes",
 					Iterations: 1000000,
+					Argon2id: argon2idConfig{
+						Memory:  64 * 1024,
+						Time:    3,
+						Threads: 4,
+					},
 				},
 				Cookie: cookieConfig{
 					Name:     "SSID",
@@ -134,10 +139,14 @@ func (impl *Implm) Run(
 		return fmt.Errorf("cannot get pepper bytes: %w", err)
 	}
 
-	// Validate cookie secret
-	_, err = impl.cfg.Auth.GetCookieSecretBytes()
+	cookieKeyring, err := impl.cfg.Auth.GetCookieKeyring()
 	if err != nil {
-		return fmt.Errorf("cannot get cookie secret bytes: %w", err)
+		return fmt.Errorf("cannot get cookie keyring: %w", err)
+	}
+
+	tokenKeyring, err := impl.cfg.Auth.GetTokenKeyring()
+	if err != nil {
+		return fmt.Errorf("cannot get token keyring: %w", err)
 	}
 
 	awsConfig := awsconfig.NewConfig(
@@ -162,18 +171,30 @@ func (impl *Implm) Run(
 		return fmt.Errorf("cannot migrate database schema: %w", err)
 	}
 
-	hp, err := passwdhash.NewProfile(pepper, uint32(impl.cfg.Auth.Password.Iterations))
+	hpOpts, err := impl.cfg.Auth.GetPasswordHashOptions()
+	if err != nil {
+		return fmt.Errorf("cannot get password hash options: %w", err)
+	}
+
+	hp, err := passwdhash.NewProfile(pepper, uint32(impl.cfg.Auth.Password.Iterations), hpOpts...)
 	if err != nil {
 		return fmt.Errorf("cannot create hashing profile: %w", err)
 	}
 
+	signupAllowedEmailDomains, err := impl.cfg.Auth.GetSignupAllowedEmailDomains()
+	if err != nil {
+		return fmt.Errorf("cannot get signup allowed email domains: %w", err)
+	}
+
 	usrmgrService, err := usrmgr.NewService(
 		ctx,
 		pgClient,
+		l,
 		hp,
-		impl.cfg.Auth.Cookie.Secret,
+		tokenKeyring,
 		impl.cfg.Hostname,
 		impl.cfg.Auth.DisableSignup,
+		signupAllowedEmailDomains,
 	)
 	if err != nil {
 		return fmt.Errorf("cannot create usrmgr service: %w", err)
@@ -193,7 +214,8 @@ func (impl *Implm) Run(
 				CookieName:      impl.cfg.Auth.Cookie.Name,
 				CookieDomain:    impl.cfg.Auth.Cookie.Domain,
 				SessionDuration: time.Duration(impl.cfg.Auth.Cookie.Duration) * time.Hour,
-				CookieSecret:    impl.cfg.Auth.Cookie.Secret,
+				CookieKeyring:   cookieKeyring,
+				ClientIPHeader:  impl.cfg.Auth.ClientIPHeader,
 			},
 		},
 	)
@@ -23,6 +23,8 @@ import (
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/getprobo/probo/pkg/crypto/keyring"
 )
 
 var (
@@ -36,8 +38,9 @@ type Config struct {
 	// Name is the name of the cookie
 	Name string
 
-	// Secret is the secret key used for signing cookies
-	Secret string
+	// Keyring holds the keys used for signing cookies, cookies signed
+	// with a retired key stay valid
+	Keyring *keyring.Keyring
 
 	// Domain is the cookie domain
 	Domain string
@@ -59,10 +62,10 @@ type Config struct {
 }
 
 // DefaultConfig returns a default secure cookie configuration
-func DefaultConfig(name, secret string) Config {
+func DefaultConfig(name string, kr *keyring.Keyring) Config {
 	return Config{
 		Name:     name,
-		Secret:   secret,
+		Keyring:  kr,
 		Path:     "/",
 		MaxAge:   86400 * 30, // 30 days
 		Secure:   true,
@@ -73,7 +76,7 @@ func DefaultConfig(name, secret string) Config {
 
 // Set creates and sets a secure cookie with the given value
 func Set(w http.ResponseWriter, config Config, value string) error {
-	signedValue, err := Sign(value, config.Secret)
+	signedValue, err := Sign(value, config.Keyring)
 	if err != nil {
 		return fmt.Errorf("failed to sign cookie value: %w", err)
 	}
@@ -100,7 +103,7 @@ func Get(r *http.Request, config Config) (string, error) {
 		return "", ErrCookieNotFound
 	}
 
-	value, err := Verify(cookie.Value, config.Secret)
+	value, err := Verify(cookie.Value, config.Keyring)
 	if err != nil {
 		return "", ErrInvalidCookie
 	}
@@ -124,41 +127,59 @@ func Clear(w http.ResponseWriter, config Config) {
 	http.SetCookie(w, cookie)
 }
 
-// Sign creates a signed value using HMAC-SHA256
-func Sign(value, secret string) (string, error) {
-	if secret == "" {
-		return "", fmt.Errorf("secret cannot be empty")
+// Sign creates a signed value using HMAC-SHA256 with the current key of
+// the keyring. The signed value has the following format:
+//
+//	<value>.<key id>.<signature>
+//
+// The signature covers both the value and the key ID.
+func Sign(value string, kr *keyring.Keyring) (string, error) {
+	if kr == nil {
+		return "", fmt.Errorf("keyring cannot be nil")
 	}
 
-	h := hmac.New(sha256.New, []byte(secret))
-	h.Write([]byte(value))
-
-	signature := base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+	key := kr.Current()
+	signedPart := value + "." + key.ID
 
-	return value + "." + signature, nil
+	return signedPart + "." + signature(key.Secret, signedPart), nil
 }
 
-// Verify checks if a signed value is valid
-func Verify(signedValue, secret string) (string, error) {
-	if secret == "" {
-		return "", fmt.Errorf("secret cannot be empty")
+// Verify checks if a signed value is valid and returns the value. Values
+// signed before key IDs were introduced, with the "<value>.<signature>"
+// format, are checked against every key of the keyring.
+func Verify(signedValue string, kr *keyring.Keyring) (string, error) {
+	if kr == nil {
+		return "", fmt.Errorf("keyring cannot be nil")
 	}
 
-	parts := strings.Split(signedValue, ".")
-	if len(parts) != 2 {
+	i := strings.LastIndex(signedValue, ".")
+	if i < 0 {
 		return "", fmt.Errorf("invalid signed value format")
 	}
 
-	value := parts[0]
+	signedPart := signedValue[:i]
+	providedSignature := signedValue[i+1:]
 
-	expectedSignedValue, err := Sign(value, secret)
-	if err != nil {
-		return "", fmt.Errorf("failed to sign value: %w", err)
+	if j := strings.LastIndex(signedPart, "."); j >= 0 {
+		if key, ok := kr.Key(signedPart[j+1:]); ok {
+			if hmac.Equal([]byte(providedSignature), []byte(signature(key.Secret, signedPart))) {
+				return signedPart[:j], nil
+			}
+		}
 	}
 
-	if signedValue != expectedSignedValue {
-		return "", ErrInvalidSignature
+	for _, key := range kr.Keys() {
+		if hmac.Equal([]byte(providedSignature), []byte(signature(key.Secret, signedPart))) {
+			return signedPart, nil
+		}
 	}
 
-	return value, nil
+	return "", ErrInvalidSignature
+}
+
+func signature(secret []byte, value string) string {
+	h := hmac.New(sha256.New, secret)
+	h.Write([]byte(value))
+
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
 }
@@ -20,6 +20,7 @@ import (
 
 	"github.com/getprobo/probo/pkg/probo"
 	console_v1 "github.com/getprobo/probo/pkg/server/api/console/v1"
+	scim_v2 "github.com/getprobo/probo/pkg/server/api/scim/v2"
 	"github.com/getprobo/probo/pkg/usrmgr"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/cors"
@@ -86,7 +87,7 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	corsOpts := cors.Options{
 		AllowedOrigins:     s.cfg.AllowedOrigins,
 		AllowedMethods:     []string{"GET", "POST", "PUT", "DELETE", "HEAD"},
-		AllowedHeaders:     []string{"content-type", "traceparent"},
+		AllowedHeaders:     []string{"authorization", "content-type", "traceparent"},
 		ExposedHeaders:     []string{"x-Request-id"},
 		AllowCredentials:   true,
 		MaxAge:             600, // 10 minutes (chrome >= 76 maximum value c.f. https://source.chromium.org/chromium/chromium/src/+/main:services/network/public/cpp/cors/preflight_result.cc;drc=52002151773d8cd9ffc5f557cd7cc880fddcae3e;l=36)
@@ -103,5 +104,8 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// Mount the console API with authentication
 	router.Mount("/console/v1", console_v1.NewMux(s.cfg.Probo, s.cfg.Usrmgr, s.cfg.Auth))
 
+	// Mount the SCIM provisioning API, authenticated with organization tokens
+	router.Mount("/scim/v2", scim_v2.NewMux(s.cfg.Usrmgr))
+
 	router.ServeHTTP(w, r)
 }
@@ -0,0 +1,89 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package console_v1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/getprobo/probo/pkg/securecookie"
+	"github.com/getprobo/probo/pkg/usrmgr"
+	"go.gearno.de/kit/httpserver"
+)
+
+type (
+	DeleteAccountRequest struct {
+		EmailConfirmation string `json:"emailConfirmation"`
+	}
+)
+
+func PersonalDataExportHandler(usrmgrSvc *usrmgr.Service, authCfg AuthConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authenticatedUser(r, usrmgrSvc, authCfg)
+		if err != nil {
+			httpserver.RenderError(w, http.StatusUnauthorized, err)
+			return
+		}
+
+		data, err := usrmgrSvc.ExportPersonalData(r.Context(), user.ID)
+		if err != nil {
+			panic(fmt.Errorf("cannot export personal data: %w", err))
+		}
+
+		w.Header().Set("Content-Disposition", `attachment; filename="personal-data.json"`)
+		httpserver.RenderJSON(w, http.StatusOK, data)
+	}
+}
+
+func DeleteAccountHandler(usrmgrSvc *usrmgr.Service, authCfg AuthConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authenticatedUser(r, usrmgrSvc, authCfg)
+		if err != nil {
+			httpserver.RenderError(w, http.StatusUnauthorized, err)
+			return
+		}
+
+		var req DeleteAccountRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httpserver.RenderError(w, http.StatusBadRequest, fmt.Errorf("cannot decode body: %w", err))
+			return
+		}
+
+		if err := usrmgrSvc.DeleteAccount(r.Context(), user.ID, req.EmailConfirmation); err != nil {
+			var errAccountDeletionNotConfirmed *usrmgr.ErrAccountDeletionNotConfirmed
+			if errors.As(err, &errAccountDeletionNotConfirmed) {
+				httpserver.RenderError(w, http.StatusBadRequest, err)
+				return
+			}
+
+			var errLastOwner *usrmgr.ErrLastOwner
+			if errors.As(err, &errLastOwner) {
+				httpserver.RenderError(w, http.StatusConflict, err)
+				return
+			}
+
+			panic(fmt.Errorf("cannot delete account: %w", err))
+		}
+
+		securecookie.Clear(w, securecookie.DefaultConfig(
+			authCfg.CookieName,
+			authCfg.CookieKeyring,
+		))
+
+		httpserver.RenderJSON(w, http.StatusOK, map[string]bool{"success": true})
+	}
+}
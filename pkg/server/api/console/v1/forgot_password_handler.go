@@ -0,0 +1,57 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package console_v1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/getprobo/probo/pkg/usrmgr"
+	"go.gearno.de/kit/httpserver"
+)
+
+type (
+	ForgotPasswordRequest struct {
+		Email string `json:"email"`
+	}
+
+	ForgotPasswordResponse struct {
+	}
+)
+
+func ForgotPasswordHandler(usrmgrSvc *usrmgr.Service, authCfg AuthConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req ForgotPasswordRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httpserver.RenderError(w, http.StatusBadRequest, fmt.Errorf("cannot decode body: %w", err))
+			return
+		}
+
+		err := usrmgrSvc.RequestPasswordReset(r.Context(), req.Email)
+		if err != nil {
+			var errInvalidEmail *usrmgr.ErrInvalidEmail
+			if errors.As(err, &errInvalidEmail) {
+				httpserver.RenderError(w, http.StatusBadRequest, err)
+				return
+			}
+
+			panic(fmt.Errorf("cannot request password reset: %w", err))
+		}
+
+		httpserver.RenderJSON(w, http.StatusOK, ForgotPasswordResponse{})
+	}
+}
@@ -16,6 +16,7 @@ package console_v1
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -43,6 +44,12 @@ func InvitationConfirmationHandler(usrmgrSvc *usrmgr.Service, authCfg AuthConfig
 
 		err := usrmgrSvc.ConfirmInvitation(r.Context(), req.Token, req.Password)
 		if err != nil {
+			var errInvitationNotPending *usrmgr.ErrInvitationNotPending
+			if errors.As(err, &errInvitationNotPending) {
+				httpserver.RenderError(w, http.StatusGone, err)
+				return
+			}
+
 			httpserver.RenderError(w, http.StatusInternalServerError, err)
 			return
 		}
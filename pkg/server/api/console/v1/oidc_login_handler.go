@@ -0,0 +1,130 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package console_v1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/getprobo/probo/pkg/gid"
+	"github.com/getprobo/probo/pkg/securecookie"
+	"github.com/getprobo/probo/pkg/usrmgr"
+	"go.gearno.de/kit/httpserver"
+)
+
+func OIDCLoginHandler(usrmgrSvc *usrmgr.Service, authCfg AuthConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		organizationID, err := gid.ParseGID(r.URL.Query().Get("organizationId"))
+		if err != nil {
+			httpserver.RenderError(w, http.StatusBadRequest, fmt.Errorf("invalid organization id: %w", err))
+			return
+		}
+
+		authURL, loginToken, err := usrmgrSvc.BeginOIDCLogin(r.Context(), organizationID)
+		if err != nil {
+			var errOIDCNotConfigured *usrmgr.ErrOIDCNotConfigured
+			if errors.As(err, &errOIDCNotConfigured) {
+				httpserver.RenderError(w, http.StatusNotFound, err)
+				return
+			}
+
+			panic(fmt.Errorf("cannot begin oidc login: %w", err))
+		}
+
+		securecookie.Set(w, oidcCookieConfig(authCfg), loginToken)
+
+		http.Redirect(w, r, authURL, http.StatusFound)
+	}
+}
+
+func OIDCCallbackHandler(usrmgrSvc *usrmgr.Service, authCfg AuthConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		loginToken, err := securecookie.Get(r, oidcCookieConfig(authCfg))
+		if err != nil {
+			httpserver.RenderError(w, http.StatusBadRequest, fmt.Errorf("missing login flow: %w", err))
+			return
+		}
+
+		securecookie.Clear(w, oidcCookieConfig(authCfg))
+
+		if idpError := query.Get("error"); idpError != "" {
+			httpserver.RenderError(
+				w,
+				http.StatusUnauthorized,
+				fmt.Errorf("identity provider error: %s: %s", idpError, query.Get("error_description")),
+			)
+			return
+		}
+
+		_, session, err := usrmgrSvc.FinishOIDCLogin(
+			r.Context(),
+			loginToken,
+			query.Get("state"),
+			query.Get("code"),
+		)
+		if err != nil {
+			var errInvalidOIDCResponse *usrmgr.ErrInvalidOIDCResponse
+			if errors.As(err, &errInvalidOIDCResponse) {
+				httpserver.RenderError(w, http.StatusUnauthorized, err)
+				return
+			}
+
+			var errEmailDomainNotAllowed *usrmgr.ErrEmailDomainNotAllowed
+			if errors.As(err, &errEmailDomainNotAllowed) {
+				httpserver.RenderError(w, http.StatusForbidden, err)
+				return
+			}
+
+			var errFederatedAccountNotLinkable *usrmgr.ErrFederatedAccountNotLinkable
+			if errors.As(err, &errFederatedAccountNotLinkable) {
+				httpserver.RenderError(w, http.StatusForbidden, err)
+				return
+			}
+
+			var errOIDCNotConfigured *usrmgr.ErrOIDCNotConfigured
+			if errors.As(err, &errOIDCNotConfigured) {
+				httpserver.RenderError(w, http.StatusNotFound, err)
+				return
+			}
+
+			panic(fmt.Errorf("cannot finish oidc login: %w", err))
+		}
+
+		securecookie.Set(
+			w,
+			securecookie.DefaultConfig(
+				authCfg.CookieName,
+				authCfg.CookieKeyring,
+			),
+			session.ID.String(),
+		)
+
+		http.Redirect(w, r, "/", http.StatusFound)
+	}
+}
+
+// oidcCookieConfig returns the configuration of the cookie holding the
+// login flow state. It must be sent back on the top-level cross-site
+// redirection from the identity provider, hence the lax SameSite mode.
+func oidcCookieConfig(authCfg AuthConfig) securecookie.Config {
+	config := securecookie.DefaultConfig(authCfg.CookieName+"_oidc", authCfg.CookieKeyring)
+	config.MaxAge = 600
+	config.SameSite = http.SameSiteLaxMode
+
+	return config
+}
@@ -20,7 +20,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
+	"net"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/99designs/gqlgen/graphql"
@@ -29,6 +32,7 @@ import (
 	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/getprobo/probo/pkg/coredata"
+	"github.com/getprobo/probo/pkg/crypto/keyring"
 	"github.com/getprobo/probo/pkg/gid"
 	"github.com/getprobo/probo/pkg/probo"
 	"github.com/getprobo/probo/pkg/securecookie"
@@ -36,6 +40,7 @@ import (
 	"github.com/getprobo/probo/pkg/usrmgr"
 	"github.com/go-chi/chi/v5"
 	"github.com/vektah/gqlparser/v2/gqlerror"
+	"go.gearno.de/kit/httpserver"
 )
 
 type (
@@ -43,7 +48,10 @@ type (
 		CookieName      string
 		CookieDomain    string
 		SessionDuration time.Duration
-		CookieSecret    string
+		CookieKeyring   *keyring.Keyring
+		// ClientIPHeader is the header in which the trusted reverse
+		// proxy forwards the client address, see clientIPAddress.
+		ClientIPHeader string
 	}
 
 	Resolver struct {
@@ -56,9 +64,12 @@ type (
 )
 
 var (
-	sessionContextKey    = &ctxKey{name: "session"}
-	userContextKey       = &ctxKey{name: "user"}
-	userTenantContextKey = &ctxKey{name: "user_tenants"}
+	sessionContextKey             = &ctxKey{name: "session"}
+	apiTokenContextKey            = &ctxKey{name: "api_token"}
+	userContextKey                = &ctxKey{name: "user"}
+	userTenantContextKey          = &ctxKey{name: "user_tenants"}
+	userTenantRoleContextKey      = &ctxKey{name: "user_tenant_roles"}
+	mfaRestrictedTenantContextKey = &ctxKey{name: "mfa_restricted_tenants"}
 )
 
 func SessionFromContext(ctx context.Context) *coredata.Session {
@@ -66,6 +77,13 @@ func SessionFromContext(ctx context.Context) *coredata.Session {
 	return session
 }
 
+// APITokenFromContext returns the token the request is authenticated
+// with, it is nil for requests authenticated with a session.
+func APITokenFromContext(ctx context.Context) *coredata.APIToken {
+	apiToken, _ := ctx.Value(apiTokenContextKey).(*coredata.APIToken)
+	return apiToken
+}
+
 func UserFromContext(ctx context.Context) *coredata.User {
 	user, _ := ctx.Value(userContextKey).(*coredata.User)
 	return user
@@ -76,8 +94,24 @@ func NewMux(proboSvc *probo.Service, usrmgrSvc *usrmgr.Service, authCfg AuthConf
 
 	r.Post("/auth/register", SignUpHandler(usrmgrSvc, authCfg))
 	r.Post("/auth/login", SignInHandler(usrmgrSvc, authCfg))
+	r.Post("/auth/login/mfa", SignInMFAHandler(usrmgrSvc, authCfg))
+	r.Get("/auth/oidc/login", OIDCLoginHandler(usrmgrSvc, authCfg))
+	r.Get("/auth/oidc/callback", OIDCCallbackHandler(usrmgrSvc, authCfg))
+	r.Get("/auth/saml/{organizationId}/metadata", SAMLMetadataHandler(usrmgrSvc, authCfg))
+	r.Get("/auth/saml/{organizationId}/login", SAMLLoginHandler(usrmgrSvc, authCfg))
+	r.Post("/auth/saml/{organizationId}/acs", SAMLConsumerHandler(usrmgrSvc, authCfg))
+	r.Post("/auth/webauthn/login/begin", WebAuthnLoginBeginHandler(usrmgrSvc, authCfg))
+	r.Post("/auth/webauthn/login/finish", WebAuthnLoginFinishHandler(usrmgrSvc, authCfg))
+	r.Post("/auth/webauthn/register/begin", WebAuthnRegistrationBeginHandler(usrmgrSvc, authCfg))
+	r.Post("/auth/webauthn/register/finish", WebAuthnRegistrationFinishHandler(usrmgrSvc, authCfg))
 	r.Delete("/auth/logout", SignOutHandler(usrmgrSvc, authCfg))
 	r.Post("/auth/invitation", InvitationConfirmationHandler(usrmgrSvc, authCfg))
+	r.Post("/auth/forgot-password", ForgotPasswordHandler(usrmgrSvc, authCfg))
+	r.Post("/auth/reset-password", ResetPasswordHandler(usrmgrSvc, authCfg))
+	r.Get("/auth/account/personal-data", PersonalDataExportHandler(usrmgrSvc, authCfg))
+	r.Delete("/auth/account", DeleteAccountHandler(usrmgrSvc, authCfg))
+
+	r.Get("/frameworks/schema.json", FrameworkDocumentSchemaHandler())
 
 	r.Get("/", playground.Handler("GraphQL", "/api/console/v1/query"))
 	r.Post("/query", graphqlHandler(proboSvc, usrmgrSvc, authCfg))
@@ -129,9 +163,57 @@ func graphqlHandler(proboSvc *probo.Service, usrmgrSvc *usrmgr.Service, authCfg
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
+		if authorization := r.Header.Get("Authorization"); authorization != "" {
+			token, found := strings.CutPrefix(authorization, "Bearer ")
+			if !found {
+				httpserver.RenderJSON(w, http.StatusUnauthorized, map[string]string{"error": "unsupported authorization scheme"})
+				return
+			}
+
+			apiToken, user, err := usrmgrSvc.AuthenticateAPIToken(ctx, strings.TrimSpace(token))
+			if err != nil {
+				var errInvalidAPIToken *usrmgr.ErrInvalidAPIToken
+				if errors.As(err, &errInvalidAPIToken) {
+					httpserver.RenderJSON(w, http.StatusUnauthorized, map[string]string{"error": errInvalidAPIToken.Error()})
+					return
+				}
+
+				panic(fmt.Errorf("failed to authenticate api token: %w", err))
+			}
+
+			// The token only grants access to its organization, whatever
+			// the other organizations of the user are.
+			tenantIDs := []gid.TenantID{apiToken.OrganizationID.TenantID()}
+
+			tenantRoles, err := usrmgrSvc.ListTenantRolesForUserID(ctx, user.ID)
+			if err != nil {
+				panic(fmt.Errorf("failed to list tenant roles for user: %w", err))
+			}
+			maps.DeleteFunc(tenantRoles, func(tenantID gid.TenantID, _ coredata.MembershipRole) bool {
+				return tenantID != apiToken.OrganizationID.TenantID()
+			})
+
+			var mfaRestrictedTenantIDs []gid.TenantID
+			if !user.MFAEnabled() {
+				mfaRestrictedTenantIDs, err = usrmgrSvc.ListMFARequiredTenantsForUserID(ctx, user.ID)
+				if err != nil {
+					panic(fmt.Errorf("failed to list mfa required tenants for user: %w", err))
+				}
+			}
+
+			ctx = context.WithValue(ctx, apiTokenContextKey, apiToken)
+			ctx = context.WithValue(ctx, userContextKey, user)
+			ctx = context.WithValue(ctx, userTenantContextKey, &tenantIDs)
+			ctx = context.WithValue(ctx, userTenantRoleContextKey, tenantRoles)
+			ctx = context.WithValue(ctx, mfaRestrictedTenantContextKey, mfaRestrictedTenantIDs)
+
+			srv.ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
+
 		cookieValue, err := securecookie.Get(r, securecookie.DefaultConfig(
 			authCfg.CookieName,
-			authCfg.CookieSecret,
+			authCfg.CookieKeyring,
 		))
 		if err != nil {
 			if !errors.Is(err, securecookie.ErrCookieNotFound) {
@@ -146,7 +228,7 @@ func graphqlHandler(proboSvc *probo.Service, usrmgrSvc *usrmgr.Service, authCfg
 		if err != nil {
 			securecookie.Clear(w, securecookie.DefaultConfig(
 				authCfg.CookieName,
-				authCfg.CookieSecret,
+				authCfg.CookieKeyring,
 			))
 
 			srv.ServeHTTP(w, r)
@@ -157,7 +239,7 @@ func graphqlHandler(proboSvc *probo.Service, usrmgrSvc *usrmgr.Service, authCfg
 		if err != nil {
 			securecookie.Clear(w, securecookie.DefaultConfig(
 				authCfg.CookieName,
-				authCfg.CookieSecret,
+				authCfg.CookieKeyring,
 			))
 
 			srv.ServeHTTP(w, r)
@@ -168,7 +250,7 @@ func graphqlHandler(proboSvc *probo.Service, usrmgrSvc *usrmgr.Service, authCfg
 		if err != nil {
 			securecookie.Clear(w, securecookie.DefaultConfig(
 				authCfg.CookieName,
-				authCfg.CookieSecret,
+				authCfg.CookieKeyring,
 			))
 
 			srv.ServeHTTP(w, r)
@@ -180,12 +262,30 @@ func graphqlHandler(proboSvc *probo.Service, usrmgrSvc *usrmgr.Service, authCfg
 			panic(fmt.Errorf("failed to list tenants for user: %w", err))
 		}
 
+		tenantRoles, err := usrmgrSvc.ListTenantRolesForUserID(ctx, user.ID)
+		if err != nil {
+			panic(fmt.Errorf("failed to list tenant roles for user: %w", err))
+		}
+
+		var mfaRestrictedTenantIDs []gid.TenantID
+		if !user.MFAEnabled() {
+			mfaRestrictedTenantIDs, err = usrmgrSvc.ListMFARequiredTenantsForUserID(ctx, user.ID)
+			if err != nil {
+				panic(fmt.Errorf("failed to list mfa required tenants for user: %w", err))
+			}
+		}
+
 		ctx = context.WithValue(ctx, sessionContextKey, session)
 		ctx = context.WithValue(ctx, userContextKey, user)
 		ctx = context.WithValue(ctx, userTenantContextKey, &tenantIDs)
+		ctx = context.WithValue(ctx, userTenantRoleContextKey, tenantRoles)
+		ctx = context.WithValue(ctx, mfaRestrictedTenantContextKey, mfaRestrictedTenantIDs)
 
 		srv.ServeHTTP(w, r.WithContext(ctx))
 
+		session.UserAgent = r.UserAgent()
+		session.IPAddress = clientIPAddress(r, authCfg.ClientIPHeader)
+
 		if err := usrmgrSvc.UpdateSession(r.Context(), session); err != nil {
 			panic(fmt.Errorf("failed to update session: %w", err))
 		}
@@ -193,8 +293,38 @@ func graphqlHandler(proboSvc *probo.Service, usrmgrSvc *usrmgr.Service, authCfg
 	}
 }
 
+// clientIPAddress returns the address of the client the request comes
+// from, without port. Behind a reverse proxy, the address is read from
+// the header the proxy sets, taking the last address as the previous ones
+// may come from the client itself.
+func clientIPAddress(r *http.Request, header string) string {
+	if header != "" {
+		values := r.Header.Values(header)
+		if len(values) > 0 {
+			addresses := strings.Split(values[len(values)-1], ",")
+			if address := strings.TrimSpace(addresses[len(addresses)-1]); address != "" {
+				return address
+			}
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
 func (r *Resolver) GetTenantServiceIfAuthorized(ctx context.Context, tenantID gid.TenantID) *probo.TenantService {
 	tenantIDs, _ := ctx.Value(userTenantContextKey).(*[]gid.TenantID)
+	mfaRestrictedTenantIDs, _ := ctx.Value(mfaRestrictedTenantContextKey).([]gid.TenantID)
+
+	for _, id := range mfaRestrictedTenantIDs {
+		if id == tenantID {
+			panic(fmt.Errorf("organization requires multi-factor authentication"))
+		}
+	}
 
 	for _, id := range *tenantIDs {
 		if id == tenantID {
@@ -204,3 +334,90 @@ func (r *Resolver) GetTenantServiceIfAuthorized(ctx context.Context, tenantID gi
 
 	panic(fmt.Errorf("tenant not found"))
 }
+
+// HasPermission reports whether the role of the user in the organization
+// of the tenant grants the permission.
+func (r *Resolver) HasPermission(ctx context.Context, tenantID gid.TenantID, permission usrmgr.Permission) bool {
+	tenantRoles, _ := ctx.Value(userTenantRoleContextKey).(map[gid.TenantID]coredata.MembershipRole)
+
+	role, ok := tenantRoles[tenantID]
+	if !ok {
+		return false
+	}
+
+	return usrmgr.RoleHasPermission(role, permission)
+}
+
+// GetTenantServiceIfPermitted is GetTenantServiceIfAuthorized for
+// operations restricted to the roles granting the permission.
+func (r *Resolver) GetTenantServiceIfPermitted(ctx context.Context, tenantID gid.TenantID, permission usrmgr.Permission) *probo.TenantService {
+	svc := r.GetTenantServiceIfAuthorized(ctx, tenantID)
+
+	if !r.HasPermission(ctx, tenantID, permission) {
+		panic(fmt.Errorf("permission denied"))
+	}
+
+	return svc
+}
+
+// loadUsers loads the distinct users referenced by userIDs, skipping the
+// nil ones.
+func (r *Resolver) loadUsers(ctx context.Context, userIDs []*gid.GID) (map[gid.GID]*coredata.User, error) {
+	users := make(map[gid.GID]*coredata.User)
+
+	for _, userID := range userIDs {
+		if userID == nil {
+			continue
+		}
+
+		if _, ok := users[*userID]; ok {
+			continue
+		}
+
+		user, err := r.usrmgrSvc.GetUserByID(ctx, *userID)
+		if err != nil {
+			return nil, err
+		}
+
+		users[*userID] = user
+	}
+
+	return users, nil
+}
+
+// frameworkDocumentError reports each problem of an invalid framework
+// document as its own GraphQL error, carrying the path of the offending
+// value, so clients can point at all of them at once.
+func frameworkDocumentError(ctx context.Context, err error) error {
+	var errInvalidFrameworkDocument *probo.ErrInvalidFrameworkDocument
+	if !errors.As(err, &errInvalidFrameworkDocument) || len(errInvalidFrameworkDocument.Problems) == 0 {
+		return fmt.Errorf("cannot parse framework document: %w", err)
+	}
+
+	problems := errInvalidFrameworkDocument.Problems
+	for _, problem := range problems[:len(problems)-1] {
+		graphql.AddError(ctx, newFrameworkDocumentProblemError(problem))
+	}
+
+	return newFrameworkDocumentProblemError(problems[len(problems)-1])
+}
+
+func newFrameworkDocumentProblemError(problem probo.FrameworkDocumentProblem) *gqlerror.Error {
+	extensions := map[string]any{
+		"code": "INVALID_FRAMEWORK_DOCUMENT",
+	}
+
+	if problem.Path != "" {
+		extensions["documentPath"] = problem.Path
+	}
+
+	if problem.Line > 0 {
+		extensions["line"] = problem.Line
+		extensions["column"] = problem.Column
+	}
+
+	return &gqlerror.Error{
+		Message:    problem.String(),
+		Extensions: extensions,
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package console_v1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/getprobo/probo/pkg/gid"
+	"github.com/getprobo/probo/pkg/securecookie"
+	"github.com/getprobo/probo/pkg/usrmgr"
+	"github.com/go-chi/chi/v5"
+	"go.gearno.de/kit/httpserver"
+)
+
+func SAMLMetadataHandler(usrmgrSvc *usrmgr.Service, authCfg AuthConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		organizationID, err := gid.ParseGID(chi.URLParam(r, "organizationId"))
+		if err != nil {
+			httpserver.RenderError(w, http.StatusBadRequest, fmt.Errorf("invalid organization id: %w", err))
+			return
+		}
+
+		metadata, err := usrmgrSvc.GetSAMLServiceProviderMetadata(r.Context(), organizationID)
+		if err != nil {
+			var errSAMLNotConfigured *usrmgr.ErrSAMLNotConfigured
+			if errors.As(err, &errSAMLNotConfigured) {
+				httpserver.RenderError(w, http.StatusNotFound, err)
+				return
+			}
+
+			panic(fmt.Errorf("cannot get saml metadata: %w", err))
+		}
+
+		w.Header().Set("Content-Type", "application/samlmetadata+xml")
+		w.WriteHeader(http.StatusOK)
+		w.Write(metadata)
+	}
+}
+
+func SAMLLoginHandler(usrmgrSvc *usrmgr.Service, authCfg AuthConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		organizationID, err := gid.ParseGID(chi.URLParam(r, "organizationId"))
+		if err != nil {
+			httpserver.RenderError(w, http.StatusBadRequest, fmt.Errorf("invalid organization id: %w", err))
+			return
+		}
+
+		redirectURL, loginToken, err := usrmgrSvc.BeginSAMLLogin(r.Context(), organizationID)
+		if err != nil {
+			var errSAMLNotConfigured *usrmgr.ErrSAMLNotConfigured
+			if errors.As(err, &errSAMLNotConfigured) {
+				httpserver.RenderError(w, http.StatusNotFound, err)
+				return
+			}
+
+			panic(fmt.Errorf("cannot begin saml login: %w", err))
+		}
+
+		securecookie.Set(w, samlCookieConfig(authCfg), loginToken)
+
+		http.Redirect(w, r, redirectURL, http.StatusFound)
+	}
+}
+
+func SAMLConsumerHandler(usrmgrSvc *usrmgr.Service, authCfg AuthConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		organizationID, err := gid.ParseGID(chi.URLParam(r, "organizationId"))
+		if err != nil {
+			httpserver.RenderError(w, http.StatusBadRequest, fmt.Errorf("invalid organization id: %w", err))
+			return
+		}
+
+		if err := r.ParseForm(); err != nil {
+			httpserver.RenderError(w, http.StatusBadRequest, fmt.Errorf("cannot parse form: %w", err))
+			return
+		}
+
+		loginToken, err := securecookie.Get(r, samlCookieConfig(authCfg))
+		if err != nil {
+			httpserver.RenderError(w, http.StatusBadRequest, fmt.Errorf("missing login flow: %w", err))
+			return
+		}
+
+		securecookie.Clear(w, samlCookieConfig(authCfg))
+
+		_, session, err := usrmgrSvc.FinishSAMLLogin(
+			r.Context(),
+			organizationID,
+			loginToken,
+			r.PostForm.Get("SAMLResponse"),
+		)
+		if err != nil {
+			var errInvalidSAMLResponse *usrmgr.ErrInvalidSAMLResponse
+			if errors.As(err, &errInvalidSAMLResponse) {
+				httpserver.RenderError(w, http.StatusUnauthorized, err)
+				return
+			}
+
+			var errEmailDomainNotAllowed *usrmgr.ErrEmailDomainNotAllowed
+			if errors.As(err, &errEmailDomainNotAllowed) {
+				httpserver.RenderError(w, http.StatusForbidden, err)
+				return
+			}
+
+			var errFederatedAccountNotLinkable *usrmgr.ErrFederatedAccountNotLinkable
+			if errors.As(err, &errFederatedAccountNotLinkable) {
+				httpserver.RenderError(w, http.StatusForbidden, err)
+				return
+			}
+
+			var errSAMLNotConfigured *usrmgr.ErrSAMLNotConfigured
+			if errors.As(err, &errSAMLNotConfigured) {
+				httpserver.RenderError(w, http.StatusNotFound, err)
+				return
+			}
+
+			panic(fmt.Errorf("cannot finish saml login: %w", err))
+		}
+
+		securecookie.Set(
+			w,
+			securecookie.DefaultConfig(
+				authCfg.CookieName,
+				authCfg.CookieKeyring,
+			),
+			session.ID.String(),
+		)
+
+		http.Redirect(w, r, "/", http.StatusFound)
+	}
+}
+
+// samlCookieConfig returns the configuration of the cookie holding the
+// login flow state. The identity provider posts the response from its own
+// origin, so the cookie must not be restricted to same-site requests.
+func samlCookieConfig(authCfg AuthConfig) securecookie.Config {
+	config := securecookie.DefaultConfig(authCfg.CookieName+"_saml", authCfg.CookieKeyring)
+	config.MaxAge = 600
+	config.SameSite = http.SameSiteNoneMode
+
+	return config
+}
@@ -18,7 +18,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/getprobo/probo/pkg/gid"
@@ -37,6 +39,11 @@ type (
 		User UserResponse `json:"user"`
 	}
 
+	SignInMFAChallengeResponse struct {
+		MFARequired bool   `json:"mfaRequired"`
+		MFAToken    string `json:"mfaToken"`
+	}
+
 	UserResponse struct {
 		ID        gid.GID   `json:"id"`
 		Email     string    `json:"email"`
@@ -55,7 +62,7 @@ func SignInHandler(usrmgrSvc *usrmgr.Service, authCfg AuthConfig) http.HandlerFu
 			return
 		}
 
-		user, session, err := usrmgrSvc.SignIn(r.Context(), req.Email, req.Password)
+		user, session, err := usrmgrSvc.SignIn(r.Context(), req.Email, req.Password, clientIPAddress(r, authCfg.ClientIPHeader))
 		if err != nil {
 			var ErrInvalidCredentials *usrmgr.ErrInvalidCredentials
 			if errors.As(err, &ErrInvalidCredentials) {
@@ -63,6 +70,31 @@ func SignInHandler(usrmgrSvc *usrmgr.Service, authCfg AuthConfig) http.HandlerFu
 				return
 			}
 
+			var errTooManySignInAttempts *usrmgr.ErrTooManySignInAttempts
+			if errors.As(err, &errTooManySignInAttempts) {
+				renderTooManyRequests(w, errTooManySignInAttempts.RetryAfter, err)
+				return
+			}
+
+			var errAccountLocked *usrmgr.ErrAccountLocked
+			if errors.As(err, &errAccountLocked) {
+				renderTooManyRequests(w, errAccountLocked.RetryAfter, err)
+				return
+			}
+
+			var errMFARequired *usrmgr.ErrMFARequired
+			if errors.As(err, &errMFARequired) {
+				httpserver.RenderJSON(
+					w,
+					http.StatusOK,
+					SignInMFAChallengeResponse{
+						MFARequired: true,
+						MFAToken:    errMFARequired.ChallengeToken,
+					},
+				)
+				return
+			}
+
 			panic(fmt.Errorf("cannot sign in: %w", err))
 		}
 
@@ -70,7 +102,7 @@ func SignInHandler(usrmgrSvc *usrmgr.Service, authCfg AuthConfig) http.HandlerFu
 			w,
 			securecookie.DefaultConfig(
 				authCfg.CookieName,
-				authCfg.CookieSecret,
+				authCfg.CookieKeyring,
 			),
 			session.ID.String(),
 		)
@@ -90,3 +122,9 @@ func SignInHandler(usrmgrSvc *usrmgr.Service, authCfg AuthConfig) http.HandlerFu
 		)
 	}
 }
+
+func renderTooManyRequests(w http.ResponseWriter, retryAfter time.Duration, err error) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	httpserver.RenderError(w, http.StatusTooManyRequests, err)
+}
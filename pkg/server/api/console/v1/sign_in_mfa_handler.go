@@ -0,0 +1,101 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package console_v1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/getprobo/probo/pkg/securecookie"
+	"github.com/getprobo/probo/pkg/usrmgr"
+	"go.gearno.de/kit/httpserver"
+)
+
+type (
+	SignInMFARequest struct {
+		Token string `json:"token"`
+		Code  string `json:"code"`
+	}
+)
+
+func SignInMFAHandler(usrmgrSvc *usrmgr.Service, authCfg AuthConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req SignInMFARequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httpserver.RenderError(w, http.StatusBadRequest, fmt.Errorf("cannot decode body: %w", err))
+			return
+		}
+
+		user, session, err := usrmgrSvc.VerifyMFAChallenge(r.Context(), req.Token, req.Code, clientIPAddress(r, authCfg.ClientIPHeader))
+		if err != nil {
+			var errInvalidCredentials *usrmgr.ErrInvalidCredentials
+			if errors.As(err, &errInvalidCredentials) {
+				httpserver.RenderError(w, http.StatusUnauthorized, err)
+				return
+			}
+
+			var errInvalidMFACode *usrmgr.ErrInvalidMFACode
+			if errors.As(err, &errInvalidMFACode) {
+				httpserver.RenderError(w, http.StatusUnauthorized, err)
+				return
+			}
+
+			var errTooManySignInAttempts *usrmgr.ErrTooManySignInAttempts
+			if errors.As(err, &errTooManySignInAttempts) {
+				renderTooManyRequests(w, errTooManySignInAttempts.RetryAfter, err)
+				return
+			}
+
+			var errAccountLocked *usrmgr.ErrAccountLocked
+			if errors.As(err, &errAccountLocked) {
+				renderTooManyRequests(w, errAccountLocked.RetryAfter, err)
+				return
+			}
+
+			var errMFANotEnabled *usrmgr.ErrMFANotEnabled
+			if errors.As(err, &errMFANotEnabled) {
+				httpserver.RenderError(w, http.StatusBadRequest, err)
+				return
+			}
+
+			panic(fmt.Errorf("cannot verify mfa challenge: %w", err))
+		}
+
+		securecookie.Set(
+			w,
+			securecookie.DefaultConfig(
+				authCfg.CookieName,
+				authCfg.CookieKeyring,
+			),
+			session.ID.String(),
+		)
+
+		httpserver.RenderJSON(
+			w,
+			http.StatusOK,
+			SignInResponse{
+				User: UserResponse{
+					ID:        user.ID,
+					Email:     user.EmailAddress,
+					FullName:  user.FullName,
+					CreatedAt: user.CreatedAt,
+					UpdatedAt: user.UpdatedAt,
+				},
+			},
+		)
+	}
+}
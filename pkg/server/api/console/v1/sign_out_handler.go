@@ -29,7 +29,7 @@ func SignOutHandler(usrmgrSvc *usrmgr.Service, authCfg AuthConfig) http.HandlerF
 
 		sessionID, err := securecookie.Get(r, securecookie.DefaultConfig(
 			authCfg.CookieName,
-			authCfg.CookieSecret,
+			authCfg.CookieKeyring,
 		))
 		if err != nil {
 			httpserver.RenderError(w, http.StatusBadRequest, err)
@@ -49,7 +49,7 @@ func SignOutHandler(usrmgrSvc *usrmgr.Service, authCfg AuthConfig) http.HandlerF
 
 		securecookie.Clear(w, securecookie.DefaultConfig(
 			authCfg.CookieName,
-			authCfg.CookieSecret,
+			authCfg.CookieKeyring,
 		))
 
 		httpserver.RenderJSON(w, http.StatusOK, map[string]bool{"success": true})
@@ -65,6 +65,12 @@ func SignUpHandler(usrmgrSvc *usrmgr.Service, authCfg AuthConfig) http.HandlerFu
 				return
 			}
 
+			var errSignupEmailDomainNotAllowed *usrmgr.ErrSignupEmailDomainNotAllowed
+			if errors.As(err, &errSignupEmailDomainNotAllowed) {
+				httpserver.RenderError(w, http.StatusForbidden, fmt.Errorf("cannot register user: %w", err))
+				return
+			}
+
 			panic(fmt.Errorf("cannot register user: %w", err))
 		}
 
@@ -72,7 +78,7 @@ func SignUpHandler(usrmgrSvc *usrmgr.Service, authCfg AuthConfig) http.HandlerFu
 			w,
 			securecookie.DefaultConfig(
 				authCfg.CookieName,
-				authCfg.CookieSecret,
+				authCfg.CookieKeyring,
 			),
 			session.ID.String(),
 		)
@@ -52,6 +52,8 @@ func NewControl(c *coredata.Control) *Control {
 		Description: c.Description,
 		State:       c.State,
 		Importance:  c.Importance,
+		Standards:   c.Standards,
+		Position:    c.Position,
 		CreatedAt:   c.CreatedAt,
 		UpdatedAt:   c.UpdatedAt,
 	}
@@ -0,0 +1,40 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package types
+
+import (
+	"github.com/getprobo/probo/pkg/probo"
+)
+
+func NewFrameworkTemplates(templates []*probo.FrameworkTemplate) []*FrameworkTemplate {
+	result := make([]*FrameworkTemplate, len(templates))
+
+	for i := range result {
+		result[i] = NewFrameworkTemplate(templates[i])
+	}
+
+	return result
+}
+
+func NewFrameworkTemplate(t *probo.FrameworkTemplate) *FrameworkTemplate {
+	return &FrameworkTemplate{
+		ID:           t.ID,
+		Name:         t.Document.Framework.Name,
+		Description:  t.Document.Framework.Description,
+		Version:      t.Document.Framework.Version,
+		ControlCount: t.ControlCount(),
+		TaskCount:    t.TaskCount(),
+	}
+}
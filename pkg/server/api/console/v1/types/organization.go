@@ -45,9 +45,11 @@ func NewOrganizationEdge(o *coredata.Organization, orderBy coredata.Organization
 
 func NewOrganization(o *coredata.Organization) *Organization {
 	return &Organization{
-		ID:        o.ID,
-		Name:      o.Name,
-		CreatedAt: o.CreatedAt,
-		UpdatedAt: o.UpdatedAt,
+		ID:                   o.ID,
+		Name:                 o.Name,
+		MfaRequired:          o.MFARequired,
+		AutoJoinEmailDomains: o.AutoJoinEmailDomains,
+		CreatedAt:            o.CreatedAt,
+		UpdatedAt:            o.UpdatedAt,
 	}
 }
@@ -0,0 +1,36 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package types
+
+import (
+	"github.com/getprobo/probo/pkg/coredata"
+)
+
+func NewSamlConfiguration(c *coredata.SAMLConfiguration, spMetadataURL string, acsURL string) *SamlConfiguration {
+	return &SamlConfiguration{
+		ID:                  c.ID,
+		IdpEntityID:         c.IdPEntityID,
+		IdpMetadata:         c.IdPMetadata,
+		EmailAttribute:      c.EmailAttribute,
+		FullNameAttribute:   c.FullNameAttribute,
+		AllowedEmailDomains: c.AllowedEmailDomains,
+		Enabled:             c.Enabled,
+		SpEntityID:          spMetadataURL,
+		SpMetadataURL:       spMetadataURL,
+		AcsURL:              acsURL,
+		CreatedAt:           c.CreatedAt,
+		UpdatedAt:           c.UpdatedAt,
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package types
+
+import (
+	"github.com/getprobo/probo/pkg/coredata"
+	"github.com/getprobo/probo/pkg/gid"
+)
+
+func NewSessions(sessions coredata.Sessions, currentSessionID gid.GID) []*Session {
+	result := make([]*Session, len(sessions))
+
+	for i := range result {
+		result[i] = NewSession(sessions[i], sessions[i].ID == currentSessionID)
+	}
+
+	return result
+}
+
+func NewSession(s *coredata.Session, current bool) *Session {
+	return &Session{
+		ID:             s.ID,
+		UserAgent:      s.UserAgent,
+		IPAddress:      s.IPAddress,
+		Current:        current,
+		LastActivityAt: s.LastActivityAt,
+		ExpiresAt:      s.ExpiredAt,
+		CreatedAt:      s.CreatedAt,
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package types
+
+import (
+	"github.com/getprobo/probo/pkg/coredata"
+)
+
+func NewStandardMappings(standardMappings coredata.StandardMappings) []*StandardMapping {
+	result := make([]*StandardMapping, len(standardMappings))
+
+	for i := range result {
+		result[i] = NewStandardMapping(standardMappings[i])
+	}
+
+	return result
+}
+
+func NewStandardMapping(sm *coredata.StandardMapping) *StandardMapping {
+	return &StandardMapping{
+		ID:             sm.ID,
+		SourceStandard: sm.SourceStandard,
+		TargetStandard: sm.TargetStandard,
+		CreatedAt:      sm.CreatedAt,
+	}
+}
+
+func NewStandardCoverages(coverages coredata.StandardCoverages) []*StandardCoverage {
+	result := make([]*StandardCoverage, len(coverages))
+
+	for i, coverage := range coverages {
+		result[i] = &StandardCoverage{
+			Standard:            coverage.Standard,
+			ControlCount:        coverage.ControlCount,
+			CoveredControlCount: coverage.CoveredControlCount,
+		}
+	}
+
+	return result
+}
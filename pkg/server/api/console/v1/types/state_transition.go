@@ -0,0 +1,154 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package types
+
+import (
+	"github.com/getprobo/probo/pkg/coredata"
+	"github.com/getprobo/probo/pkg/gid"
+	"github.com/getprobo/probo/pkg/page"
+)
+
+type (
+	StateTransitionOrderBy OrderBy[coredata.StateTransitionOrderField]
+)
+
+func NewControlStateTransitionConnection(
+	p *page.Page[*coredata.ControlStateTransition, coredata.StateTransitionOrderField],
+	users map[gid.GID]*coredata.User,
+) *ControlStateTransitionConnection {
+	var edges = make([]*ControlStateTransitionEdge, len(p.Data))
+
+	for i := range edges {
+		edges[i] = NewControlStateTransitionEdge(p.Data[i], p.Cursor.OrderBy.Field, users)
+	}
+
+	return &ControlStateTransitionConnection{
+		Edges:    edges,
+		PageInfo: NewPageInfo(p),
+	}
+}
+
+func NewControlStateTransitionEdge(
+	t *coredata.ControlStateTransition,
+	orderBy coredata.StateTransitionOrderField,
+	users map[gid.GID]*coredata.User,
+) *ControlStateTransitionEdge {
+	return &ControlStateTransitionEdge{
+		Cursor: t.CursorKey(orderBy),
+		Node:   NewControlStateTransition(t, users),
+	}
+}
+
+func NewControlStateTransition(t *coredata.ControlStateTransition, users map[gid.GID]*coredata.User) *ControlStateTransition {
+	return &ControlStateTransition{
+		ID:        t.ID,
+		FromState: t.FromState,
+		ToState:   t.ToState,
+		Reason:    t.Reason,
+		ChangedBy: newStateTransitionChangedBy(t.ChangedByID, users),
+		CreatedAt: t.CreatedAt,
+	}
+}
+
+func NewTaskStateTransitionConnection(
+	p *page.Page[*coredata.TaskStateTransition, coredata.StateTransitionOrderField],
+	users map[gid.GID]*coredata.User,
+) *TaskStateTransitionConnection {
+	var edges = make([]*TaskStateTransitionEdge, len(p.Data))
+
+	for i := range edges {
+		edges[i] = NewTaskStateTransitionEdge(p.Data[i], p.Cursor.OrderBy.Field, users)
+	}
+
+	return &TaskStateTransitionConnection{
+		Edges:    edges,
+		PageInfo: NewPageInfo(p),
+	}
+}
+
+func NewTaskStateTransitionEdge(
+	t *coredata.TaskStateTransition,
+	orderBy coredata.StateTransitionOrderField,
+	users map[gid.GID]*coredata.User,
+) *TaskStateTransitionEdge {
+	return &TaskStateTransitionEdge{
+		Cursor: t.CursorKey(orderBy),
+		Node:   NewTaskStateTransition(t, users),
+	}
+}
+
+func NewTaskStateTransition(t *coredata.TaskStateTransition, users map[gid.GID]*coredata.User) *TaskStateTransition {
+	return &TaskStateTransition{
+		ID:        t.ID,
+		FromState: t.FromState,
+		ToState:   t.ToState,
+		Reason:    t.Reason,
+		ChangedBy: newStateTransitionChangedBy(t.ChangedByID, users),
+		CreatedAt: t.CreatedAt,
+	}
+}
+
+func NewEvidenceStateTransitionConnection(
+	p *page.Page[*coredata.EvidenceStateTransition, coredata.StateTransitionOrderField],
+	users map[gid.GID]*coredata.User,
+) *EvidenceStateTransitionConnection {
+	var edges = make([]*EvidenceStateTransitionEdge, len(p.Data))
+
+	for i := range edges {
+		edges[i] = NewEvidenceStateTransitionEdge(p.Data[i], p.Cursor.OrderBy.Field, users)
+	}
+
+	return &EvidenceStateTransitionConnection{
+		Edges:    edges,
+		PageInfo: NewPageInfo(p),
+	}
+}
+
+func NewEvidenceStateTransitionEdge(
+	t *coredata.EvidenceStateTransition,
+	orderBy coredata.StateTransitionOrderField,
+	users map[gid.GID]*coredata.User,
+) *EvidenceStateTransitionEdge {
+	return &EvidenceStateTransitionEdge{
+		Cursor: t.CursorKey(orderBy),
+		Node:   NewEvidenceStateTransition(t, users),
+	}
+}
+
+func NewEvidenceStateTransition(t *coredata.EvidenceStateTransition, users map[gid.GID]*coredata.User) *EvidenceStateTransition {
+	return &EvidenceStateTransition{
+		ID:        t.ID,
+		FromState: t.FromState,
+		ToState:   t.ToState,
+		Reason:    t.Reason,
+		ChangedBy: newStateTransitionChangedBy(t.ChangedByID, users),
+		CreatedAt: t.CreatedAt,
+	}
+}
+
+// newStateTransitionChangedBy returns the user who made the transition,
+// nil when the account has since been deleted.
+func newStateTransitionChangedBy(userID *gid.GID, users map[gid.GID]*coredata.User) *User {
+	if userID == nil {
+		return nil
+	}
+
+	user, ok := users[*userID]
+	if !ok {
+		return nil
+	}
+
+	return NewUser(user)
+}
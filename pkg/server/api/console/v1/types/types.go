@@ -12,6 +12,7 @@ import (
 	"github.com/getprobo/probo/pkg/coredata"
 	"github.com/getprobo/probo/pkg/gid"
 	"github.com/getprobo/probo/pkg/page"
+	"github.com/getprobo/probo/pkg/probo"
 )
 
 type Node interface {
@@ -19,6 +20,16 @@ type Node interface {
 	GetID() gid.GID
 }
 
+type APIToken struct {
+	ID         gid.GID    `json:"id"`
+	Name       string     `json:"name"`
+	CreatedBy  *User      `json:"createdBy"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
+}
+
 type AssignTaskInput struct {
 	TaskID       gid.GID `json:"taskId"`
 	AssignedToID gid.GID `json:"assignedToId"`
@@ -28,6 +39,62 @@ type AssignTaskPayload struct {
 	Task *Task `json:"task"`
 }
 
+type ChangeMemberRoleInput struct {
+	OrganizationID gid.GID                 `json:"organizationId"`
+	UserID         gid.GID                 `json:"userId"`
+	Role           coredata.MembershipRole `json:"role"`
+}
+
+type ChangeMemberRolePayload struct {
+	Membership *Membership `json:"membership"`
+}
+
+type CloneFrameworkInput struct {
+	FrameworkID     gid.GID `json:"frameworkId"`
+	OrganizationID  gid.GID `json:"organizationId"`
+	Name            *string `json:"name,omitempty"`
+	CopyState       *bool   `json:"copyState,omitempty"`
+	CopyAssignments *bool   `json:"copyAssignments,omitempty"`
+}
+
+type CloneFrameworkPayload struct {
+	FrameworkEdge *FrameworkEdge `json:"frameworkEdge"`
+}
+
+type ConfigureOidcInput struct {
+	OrganizationID      gid.GID  `json:"organizationId"`
+	IssuerURL           string   `json:"issuerUrl"`
+	ClientID            string   `json:"clientId"`
+	ClientSecret        *string  `json:"clientSecret,omitempty"`
+	AllowedEmailDomains []string `json:"allowedEmailDomains"`
+	Enabled             bool     `json:"enabled"`
+}
+
+type ConfigureOidcPayload struct {
+	OidcConfiguration *OidcConfiguration `json:"oidcConfiguration"`
+}
+
+type ConfigureSamlInput struct {
+	OrganizationID      gid.GID  `json:"organizationId"`
+	IdpMetadata         string   `json:"idpMetadata"`
+	EmailAttribute      *string  `json:"emailAttribute,omitempty"`
+	FullNameAttribute   *string  `json:"fullNameAttribute,omitempty"`
+	AllowedEmailDomains []string `json:"allowedEmailDomains"`
+	Enabled             bool     `json:"enabled"`
+}
+
+type ConfigureSamlPayload struct {
+	SamlConfiguration *SamlConfiguration `json:"samlConfiguration"`
+}
+
+type ConfirmEmailChangeInput struct {
+	Token string `json:"token"`
+}
+
+type ConfirmEmailChangePayload struct {
+	Success bool `json:"success"`
+}
+
 type ConfirmEmailInput struct {
 	Token string `json:"token"`
 }
@@ -36,17 +103,30 @@ type ConfirmEmailPayload struct {
 	Success bool `json:"success"`
 }
 
+type ConfirmTotpInput struct {
+	Code string `json:"code"`
+}
+
+type ConfirmTotpPayload struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
 type Control struct {
-	ID          gid.GID                    `json:"id"`
-	Version     int                        `json:"version"`
-	Category    string                     `json:"category"`
-	Name        string                     `json:"name"`
-	Description string                     `json:"description"`
-	State       coredata.ControlState      `json:"state"`
-	Importance  coredata.ControlImportance `json:"importance"`
-	Tasks       *TaskConnection            `json:"tasks"`
-	CreatedAt   time.Time                  `json:"createdAt"`
-	UpdatedAt   time.Time                  `json:"updatedAt"`
+	ID               gid.GID                           `json:"id"`
+	Version          int                               `json:"version"`
+	Category         string                            `json:"category"`
+	Name             string                            `json:"name"`
+	Description      string                            `json:"description"`
+	State            coredata.ControlState             `json:"state"`
+	Importance       coredata.ControlImportance        `json:"importance"`
+	Standards        []string                          `json:"standards"`
+	Position         int                               `json:"position"`
+	Covered          bool                              `json:"covered"`
+	MappedControls   []*MappedControl                  `json:"mappedControls"`
+	Tasks            *TaskConnection                   `json:"tasks"`
+	StateTransitions *ControlStateTransitionConnection `json:"stateTransitions"`
+	CreatedAt        time.Time                         `json:"createdAt"`
+	UpdatedAt        time.Time                         `json:"updatedAt"`
 }
 
 func (Control) IsNode()             {}
@@ -62,6 +142,36 @@ type ControlEdge struct {
 	Node   *Control       `json:"node"`
 }
 
+type ControlStateTransition struct {
+	ID        gid.GID               `json:"id"`
+	FromState coredata.ControlState `json:"fromState"`
+	ToState   coredata.ControlState `json:"toState"`
+	Reason    *string               `json:"reason,omitempty"`
+	ChangedBy *User                 `json:"changedBy,omitempty"`
+	CreatedAt time.Time             `json:"createdAt"`
+}
+
+type ControlStateTransitionConnection struct {
+	Edges    []*ControlStateTransitionEdge `json:"edges"`
+	PageInfo *PageInfo                     `json:"pageInfo"`
+}
+
+type ControlStateTransitionEdge struct {
+	Cursor page.CursorKey          `json:"cursor"`
+	Node   *ControlStateTransition `json:"node"`
+}
+
+type CreateAPITokenInput struct {
+	OrganizationID gid.GID    `json:"organizationId"`
+	Name           string     `json:"name"`
+	ExpiresAt      *time.Time `json:"expiresAt,omitempty"`
+}
+
+type CreateAPITokenPayload struct {
+	APIToken *APIToken `json:"apiToken"`
+	Token    string    `json:"token"`
+}
+
 type CreateControlInput struct {
 	FrameworkID gid.GID                    `json:"frameworkId"`
 	Name        string                     `json:"name"`
@@ -74,6 +184,15 @@ type CreateControlPayload struct {
 	ControlEdge *ControlEdge `json:"controlEdge"`
 }
 
+type CreateEmailDomainInput struct {
+	OrganizationID gid.GID `json:"organizationId"`
+	Domain         string  `json:"domain"`
+}
+
+type CreateEmailDomainPayload struct {
+	EmailDomain *EmailDomain `json:"emailDomain"`
+}
+
 type CreateFrameworkInput struct {
 	OrganizationID gid.GID `json:"organizationId"`
 	Name           string  `json:"name"`
@@ -117,6 +236,18 @@ type CreatePolicyPayload struct {
 	PolicyEdge *PolicyEdge `json:"policyEdge"`
 }
 
+type CreateStandardMappingInput struct {
+	OrganizationID    gid.GID `json:"organizationId"`
+	SourceFrameworkID gid.GID `json:"sourceFrameworkId"`
+	SourceStandard    string  `json:"sourceStandard"`
+	TargetFrameworkID gid.GID `json:"targetFrameworkId"`
+	TargetStandard    string  `json:"targetStandard"`
+}
+
+type CreateStandardMappingPayload struct {
+	StandardMapping *StandardMapping `json:"standardMapping"`
+}
+
 type CreateTaskInput struct {
 	ControlID    gid.GID        `json:"controlId"`
 	Name         string         `json:"name"`
@@ -146,6 +277,23 @@ type CreateVendorPayload struct {
 	VendorEdge *VendorEdge `json:"vendorEdge"`
 }
 
+type DeleteControlInput struct {
+	ControlID       gid.GID `json:"controlId"`
+	ExpectedVersion int     `json:"expectedVersion"`
+}
+
+type DeleteControlPayload struct {
+	DeletedControlID gid.GID `json:"deletedControlId"`
+}
+
+type DeleteEmailDomainInput struct {
+	EmailDomainID gid.GID `json:"emailDomainId"`
+}
+
+type DeleteEmailDomainPayload struct {
+	DeletedEmailDomainID gid.GID `json:"deletedEmailDomainId"`
+}
+
 type DeleteEvidenceInput struct {
 	EvidenceID gid.GID `json:"evidenceId"`
 }
@@ -154,6 +302,23 @@ type DeleteEvidencePayload struct {
 	DeletedEvidenceID gid.GID `json:"deletedEvidenceId"`
 }
 
+type DeleteFrameworkInput struct {
+	FrameworkID      gid.GID `json:"frameworkId"`
+	ConfirmationName string  `json:"confirmationName"`
+}
+
+type DeleteFrameworkPayload struct {
+	DeletedFrameworkID gid.GID `json:"deletedFrameworkId"`
+}
+
+type DeleteOidcConfigurationInput struct {
+	OrganizationID gid.GID `json:"organizationId"`
+}
+
+type DeleteOidcConfigurationPayload struct {
+	DeletedOidcConfigurationID gid.GID `json:"deletedOidcConfigurationId"`
+}
+
 type DeleteOrganizationInput struct {
 	OrganizationID gid.GID `json:"organizationId"`
 }
@@ -178,6 +343,30 @@ type DeletePolicyPayload struct {
 	DeletedPolicyID gid.GID `json:"deletedPolicyId"`
 }
 
+type DeleteSamlConfigurationInput struct {
+	OrganizationID gid.GID `json:"organizationId"`
+}
+
+type DeleteSamlConfigurationPayload struct {
+	DeletedSamlConfigurationID gid.GID `json:"deletedSamlConfigurationId"`
+}
+
+type DeleteScimConfigurationInput struct {
+	OrganizationID gid.GID `json:"organizationId"`
+}
+
+type DeleteScimConfigurationPayload struct {
+	DeletedScimConfigurationID gid.GID `json:"deletedScimConfigurationId"`
+}
+
+type DeleteStandardMappingInput struct {
+	StandardMappingID gid.GID `json:"standardMappingId"`
+}
+
+type DeleteStandardMappingPayload struct {
+	DeletedStandardMappingID gid.GID `json:"deletedStandardMappingId"`
+}
+
 type DeleteTaskInput struct {
 	TaskID gid.GID `json:"taskId"`
 }
@@ -194,15 +383,46 @@ type DeleteVendorPayload struct {
 	DeletedVendorID gid.GID `json:"deletedVendorId"`
 }
 
+type DeleteWebAuthnCredentialInput struct {
+	WebAuthnCredentialID gid.GID `json:"webAuthnCredentialId"`
+}
+
+type DeleteWebAuthnCredentialPayload struct {
+	DeletedWebAuthnCredentialID gid.GID `json:"deletedWebAuthnCredentialId"`
+}
+
+type DisableTotpInput struct {
+	Code string `json:"code"`
+}
+
+type DisableTotpPayload struct {
+	Success bool `json:"success"`
+}
+
+type EmailDomain struct {
+	ID                 gid.GID    `json:"id"`
+	Domain             string     `json:"domain"`
+	VerificationRecord string     `json:"verificationRecord"`
+	VerifiedAt         *time.Time `json:"verifiedAt,omitempty"`
+	CreatedAt          time.Time  `json:"createdAt"`
+	UpdatedAt          time.Time  `json:"updatedAt"`
+}
+
+type EnrollTotpPayload struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
 type Evidence struct {
-	ID        gid.GID                `json:"id"`
-	FileURL   string                 `json:"fileUrl"`
-	MimeType  string                 `json:"mimeType"`
-	Size      int                    `json:"size"`
-	State     coredata.EvidenceState `json:"state"`
-	Filename  string                 `json:"filename"`
-	CreatedAt time.Time              `json:"createdAt"`
-	UpdatedAt time.Time              `json:"updatedAt"`
+	ID               gid.GID                            `json:"id"`
+	FileURL          string                             `json:"fileUrl"`
+	MimeType         string                             `json:"mimeType"`
+	Size             int                                `json:"size"`
+	State            coredata.EvidenceState             `json:"state"`
+	Filename         string                             `json:"filename"`
+	StateTransitions *EvidenceStateTransitionConnection `json:"stateTransitions"`
+	CreatedAt        time.Time                          `json:"createdAt"`
+	UpdatedAt        time.Time                          `json:"updatedAt"`
 }
 
 func (Evidence) IsNode()             {}
@@ -218,14 +438,36 @@ type EvidenceEdge struct {
 	Node   *Evidence      `json:"node"`
 }
 
+type EvidenceStateTransition struct {
+	ID        gid.GID                `json:"id"`
+	FromState coredata.EvidenceState `json:"fromState"`
+	ToState   coredata.EvidenceState `json:"toState"`
+	Reason    *string                `json:"reason,omitempty"`
+	ChangedBy *User                  `json:"changedBy,omitempty"`
+	CreatedAt time.Time              `json:"createdAt"`
+}
+
+type EvidenceStateTransitionConnection struct {
+	Edges    []*EvidenceStateTransitionEdge `json:"edges"`
+	PageInfo *PageInfo                      `json:"pageInfo"`
+}
+
+type EvidenceStateTransitionEdge struct {
+	Cursor page.CursorKey           `json:"cursor"`
+	Node   *EvidenceStateTransition `json:"node"`
+}
+
 type Framework struct {
-	ID          gid.GID            `json:"id"`
-	Version     int                `json:"version"`
-	Name        string             `json:"name"`
-	Description string             `json:"description"`
-	Controls    *ControlConnection `json:"controls"`
-	CreatedAt   time.Time          `json:"createdAt"`
-	UpdatedAt   time.Time          `json:"updatedAt"`
+	ID                gid.GID             `json:"id"`
+	Version           int                 `json:"version"`
+	Name              string              `json:"name"`
+	Description       string              `json:"description"`
+	Controls          *ControlConnection  `json:"controls"`
+	ExportDocument    string              `json:"exportDocument"`
+	StandardCoverages []*StandardCoverage `json:"standardCoverages"`
+	Readiness         *Readiness          `json:"readiness"`
+	CreatedAt         time.Time           `json:"createdAt"`
+	UpdatedAt         time.Time           `json:"updatedAt"`
 }
 
 func (Framework) IsNode()             {}
@@ -241,6 +483,32 @@ type FrameworkEdge struct {
 	Node   *Framework     `json:"node"`
 }
 
+type FrameworkTemplate struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	Version      string `json:"version"`
+	ControlCount int    `json:"controlCount"`
+	TaskCount    int    `json:"taskCount"`
+}
+
+type FrameworkUpgradeChange struct {
+	Action     probo.FrameworkUpgradeAction   `json:"action"`
+	Kind       probo.FrameworkUpgradeItemKind `json:"kind"`
+	ID         *gid.GID                       `json:"id,omitempty"`
+	ContentRef string                         `json:"contentRef"`
+	Name       string                         `json:"name"`
+}
+
+type GenerateScimTokenInput struct {
+	OrganizationID gid.GID `json:"organizationId"`
+}
+
+type GenerateScimTokenPayload struct {
+	ScimConfiguration *ScimConfiguration `json:"scimConfiguration"`
+	Token             string             `json:"token"`
+}
+
 type ImportFrameworkInput struct {
 	OrganizationID gid.GID        `json:"organizationId"`
 	File           graphql.Upload `json:"file"`
@@ -250,6 +518,26 @@ type ImportFrameworkPayload struct {
 	FrameworkEdge *FrameworkEdge `json:"frameworkEdge"`
 }
 
+type ImportFrameworkTemplateInput struct {
+	OrganizationID gid.GID `json:"organizationId"`
+	TemplateID     string  `json:"templateId"`
+}
+
+type ImportFrameworkTemplatePayload struct {
+	FrameworkEdge *FrameworkEdge `json:"frameworkEdge"`
+}
+
+type Invitation struct {
+	ID         gid.GID                   `json:"id"`
+	Email      string                    `json:"email"`
+	FullName   string                    `json:"fullName"`
+	Status     coredata.InvitationStatus `json:"status"`
+	ExpiresAt  time.Time                 `json:"expiresAt"`
+	AcceptedAt *time.Time                `json:"acceptedAt,omitempty"`
+	CreatedAt  time.Time                 `json:"createdAt"`
+	UpdatedAt  time.Time                 `json:"updatedAt"`
+}
+
 type InviteUserInput struct {
 	OrganizationID gid.GID `json:"organizationId"`
 	Email          string  `json:"email"`
@@ -260,20 +548,64 @@ type InviteUserPayload struct {
 	Success bool `json:"success"`
 }
 
+type MappedControl struct {
+	Framework *Framework `json:"framework"`
+	Control   *Control   `json:"control"`
+}
+
+type Membership struct {
+	User      *User                   `json:"user"`
+	Role      coredata.MembershipRole `json:"role"`
+	CreatedAt time.Time               `json:"createdAt"`
+}
+
+type MoveControlInput struct {
+	ID              gid.GID `json:"id"`
+	ExpectedVersion int     `json:"expectedVersion"`
+	FrameworkID     gid.GID `json:"frameworkId"`
+}
+
+type MoveControlPayload struct {
+	Control *Control `json:"control"`
+}
+
 type Mutation struct {
 }
 
+type OidcConfiguration struct {
+	ID                  gid.GID   `json:"id"`
+	IssuerURL           string    `json:"issuerUrl"`
+	ClientID            string    `json:"clientId"`
+	AllowedEmailDomains []string  `json:"allowedEmailDomains"`
+	Enabled             bool      `json:"enabled"`
+	RedirectURI         string    `json:"redirectUri"`
+	CreatedAt           time.Time `json:"createdAt"`
+	UpdatedAt           time.Time `json:"updatedAt"`
+}
+
 type Organization struct {
-	ID         gid.GID              `json:"id"`
-	Name       string               `json:"name"`
-	LogoURL    *string              `json:"logoUrl,omitempty"`
-	Users      *UserConnection      `json:"users"`
-	Frameworks *FrameworkConnection `json:"frameworks"`
-	Vendors    *VendorConnection    `json:"vendors"`
-	Peoples    *PeopleConnection    `json:"peoples"`
-	Policies   *PolicyConnection    `json:"policies"`
-	CreatedAt  time.Time            `json:"createdAt"`
-	UpdatedAt  time.Time            `json:"updatedAt"`
+	ID                   gid.GID                 `json:"id"`
+	Name                 string                  `json:"name"`
+	LogoURL              *string                 `json:"logoUrl,omitempty"`
+	MfaRequired          bool                    `json:"mfaRequired"`
+	AutoJoinEmailDomains []string                `json:"autoJoinEmailDomains"`
+	Users                *UserConnection         `json:"users"`
+	Frameworks           *FrameworkConnection    `json:"frameworks"`
+	Vendors              *VendorConnection       `json:"vendors"`
+	Peoples              *PeopleConnection       `json:"peoples"`
+	Policies             *PolicyConnection       `json:"policies"`
+	OidcConfiguration    *OidcConfiguration      `json:"oidcConfiguration,omitempty"`
+	SamlConfiguration    *SamlConfiguration      `json:"samlConfiguration,omitempty"`
+	ScimConfiguration    *ScimConfiguration      `json:"scimConfiguration,omitempty"`
+	APITokens            []*APIToken             `json:"apiTokens"`
+	Memberships          []*Membership           `json:"memberships"`
+	Invitations          []*Invitation           `json:"invitations"`
+	StandardMappings     []*StandardMapping      `json:"standardMappings"`
+	EmailDomains         []*EmailDomain          `json:"emailDomains"`
+	Readiness            *Readiness              `json:"readiness"`
+	ViewerRole           coredata.MembershipRole `json:"viewerRole"`
+	CreatedAt            time.Time               `json:"createdAt"`
+	UpdatedAt            time.Time               `json:"updatedAt"`
 }
 
 func (Organization) IsNode()             {}
@@ -353,31 +685,160 @@ type PolicyEdge struct {
 type Query struct {
 }
 
+type Readiness struct {
+	ControlCount                     int           `json:"controlCount"`
+	NotStartedControlCount           int           `json:"notStartedControlCount"`
+	InProgressControlCount           int           `json:"inProgressControlCount"`
+	NotApplicableControlCount        int           `json:"notApplicableControlCount"`
+	ImplementedControlCount          int           `json:"implementedControlCount"`
+	MandatoryControlCount            int           `json:"mandatoryControlCount"`
+	PreferredControlCount            int           `json:"preferredControlCount"`
+	AdvancedControlCount             int           `json:"advancedControlCount"`
+	ImplementedMandatoryControlCount int           `json:"implementedMandatoryControlCount"`
+	MandatoryImplementedPercentage   float64       `json:"mandatoryImplementedPercentage"`
+	TaskCount                        int           `json:"taskCount"`
+	TodoTaskCount                    int           `json:"todoTaskCount"`
+	DoneTaskCount                    int           `json:"doneTaskCount"`
+	RemainingTimeEstimate            time.Duration `json:"remainingTimeEstimate"`
+	EvidenceCount                    int           `json:"evidenceCount"`
+	ValidEvidenceCount               int           `json:"validEvidenceCount"`
+	InvalidEvidenceCount             int           `json:"invalidEvidenceCount"`
+	ExpiredEvidenceCount             int           `json:"expiredEvidenceCount"`
+}
+
+type RegenerateRecoveryCodesInput struct {
+	Code string `json:"code"`
+}
+
+type RegenerateRecoveryCodesPayload struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
 type RemoveUserInput struct {
 	OrganizationID gid.GID `json:"organizationId"`
 	UserID         gid.GID `json:"userId"`
+	RevokeSessions *bool   `json:"revokeSessions,omitempty"`
 }
 
 type RemoveUserPayload struct {
 	Success bool `json:"success"`
 }
 
+type RenameWebAuthnCredentialInput struct {
+	WebAuthnCredentialID gid.GID `json:"webAuthnCredentialId"`
+	Name                 string  `json:"name"`
+}
+
+type RenameWebAuthnCredentialPayload struct {
+	WebAuthnCredential *WebAuthnCredential `json:"webAuthnCredential"`
+}
+
+type ReorderControlInput struct {
+	ID              gid.GID `json:"id"`
+	ExpectedVersion int     `json:"expectedVersion"`
+	Position        int     `json:"position"`
+}
+
+type ReorderControlPayload struct {
+	Control *Control `json:"control"`
+}
+
+type ResendInvitationInput struct {
+	InvitationID gid.GID `json:"invitationId"`
+}
+
+type ResendInvitationPayload struct {
+	Invitation *Invitation `json:"invitation"`
+}
+
+type RevokeAllOtherSessionsPayload struct {
+	RevokedSessionCount int `json:"revokedSessionCount"`
+}
+
+type RevokeAPITokenInput struct {
+	APITokenID gid.GID `json:"apiTokenId"`
+}
+
+type RevokeAPITokenPayload struct {
+	RevokedAPITokenID gid.GID `json:"revokedApiTokenId"`
+}
+
+type RevokeInvitationInput struct {
+	InvitationID gid.GID `json:"invitationId"`
+}
+
+type RevokeInvitationPayload struct {
+	Invitation *Invitation `json:"invitation"`
+}
+
+type RevokeSessionInput struct {
+	SessionID gid.GID `json:"sessionId"`
+}
+
+type RevokeSessionPayload struct {
+	RevokedSessionID gid.GID `json:"revokedSessionId"`
+}
+
+type SamlConfiguration struct {
+	ID                  gid.GID   `json:"id"`
+	IdpEntityID         string    `json:"idpEntityId"`
+	IdpMetadata         string    `json:"idpMetadata"`
+	EmailAttribute      string    `json:"emailAttribute"`
+	FullNameAttribute   string    `json:"fullNameAttribute"`
+	AllowedEmailDomains []string  `json:"allowedEmailDomains"`
+	Enabled             bool      `json:"enabled"`
+	SpEntityID          string    `json:"spEntityId"`
+	SpMetadataURL       string    `json:"spMetadataUrl"`
+	AcsURL              string    `json:"acsUrl"`
+	CreatedAt           time.Time `json:"createdAt"`
+	UpdatedAt           time.Time `json:"updatedAt"`
+}
+
+type ScimConfiguration struct {
+	ID          gid.GID    `json:"id"`
+	EndpointURL string     `json:"endpointUrl"`
+	LastUsedAt  *time.Time `json:"lastUsedAt,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
 type Session struct {
-	ID        gid.GID   `json:"id"`
-	ExpiresAt time.Time `json:"expiresAt"`
+	ID             gid.GID   `json:"id"`
+	UserAgent      string    `json:"userAgent"`
+	IPAddress      string    `json:"ipAddress"`
+	Current        bool      `json:"current"`
+	LastActivityAt time.Time `json:"lastActivityAt"`
+	ExpiresAt      time.Time `json:"expiresAt"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+type StandardCoverage struct {
+	Standard            string `json:"standard"`
+	ControlCount        int    `json:"controlCount"`
+	CoveredControlCount int    `json:"coveredControlCount"`
+}
+
+type StandardMapping struct {
+	ID              gid.GID    `json:"id"`
+	SourceFramework *Framework `json:"sourceFramework"`
+	SourceStandard  string     `json:"sourceStandard"`
+	TargetFramework *Framework `json:"targetFramework"`
+	TargetStandard  string     `json:"targetStandard"`
+	CreatedAt       time.Time  `json:"createdAt"`
 }
 
 type Task struct {
-	ID           gid.GID             `json:"id"`
-	Version      int                 `json:"version"`
-	Name         string              `json:"name"`
-	Description  string              `json:"description"`
-	State        coredata.TaskState  `json:"state"`
-	TimeEstimate *time.Duration      `json:"timeEstimate,omitempty"`
-	AssignedTo   *People             `json:"assignedTo,omitempty"`
-	Evidences    *EvidenceConnection `json:"evidences"`
-	CreatedAt    time.Time           `json:"createdAt"`
-	UpdatedAt    time.Time           `json:"updatedAt"`
+	ID               gid.GID                        `json:"id"`
+	Version          int                            `json:"version"`
+	Name             string                         `json:"name"`
+	Description      string                         `json:"description"`
+	State            coredata.TaskState             `json:"state"`
+	TimeEstimate     *time.Duration                 `json:"timeEstimate,omitempty"`
+	AssignedTo       *People                        `json:"assignedTo,omitempty"`
+	Evidences        *EvidenceConnection            `json:"evidences"`
+	StateTransitions *TaskStateTransitionConnection `json:"stateTransitions"`
+	CreatedAt        time.Time                      `json:"createdAt"`
+	UpdatedAt        time.Time                      `json:"updatedAt"`
 }
 
 func (Task) IsNode()             {}
@@ -393,6 +854,25 @@ type TaskEdge struct {
 	Node   *Task          `json:"node"`
 }
 
+type TaskStateTransition struct {
+	ID        gid.GID            `json:"id"`
+	FromState coredata.TaskState `json:"fromState"`
+	ToState   coredata.TaskState `json:"toState"`
+	Reason    *string            `json:"reason,omitempty"`
+	ChangedBy *User              `json:"changedBy,omitempty"`
+	CreatedAt time.Time          `json:"createdAt"`
+}
+
+type TaskStateTransitionConnection struct {
+	Edges    []*TaskStateTransitionEdge `json:"edges"`
+	PageInfo *PageInfo                  `json:"pageInfo"`
+}
+
+type TaskStateTransitionEdge struct {
+	Cursor page.CursorKey       `json:"cursor"`
+	Node   *TaskStateTransition `json:"node"`
+}
+
 type UnassignTaskInput struct {
 	TaskID gid.GID `json:"taskId"`
 }
@@ -402,19 +882,30 @@ type UnassignTaskPayload struct {
 }
 
 type UpdateControlInput struct {
-	ID              gid.GID                     `json:"id"`
-	ExpectedVersion int                         `json:"expectedVersion"`
-	Name            *string                     `json:"name,omitempty"`
-	Description     *string                     `json:"description,omitempty"`
-	Category        *string                     `json:"category,omitempty"`
-	State           *coredata.ControlState      `json:"state,omitempty"`
-	Importance      *coredata.ControlImportance `json:"importance,omitempty"`
+	ID                    gid.GID                     `json:"id"`
+	ExpectedVersion       int                         `json:"expectedVersion"`
+	Name                  *string                     `json:"name,omitempty"`
+	Description           *string                     `json:"description,omitempty"`
+	Category              *string                     `json:"category,omitempty"`
+	State                 *coredata.ControlState      `json:"state,omitempty"`
+	Importance            *coredata.ControlImportance `json:"importance,omitempty"`
+	StateTransitionReason *string                     `json:"stateTransitionReason,omitempty"`
 }
 
 type UpdateControlPayload struct {
 	Control *Control `json:"control"`
 }
 
+type UpdateEvidenceStateInput struct {
+	EvidenceID            gid.GID                `json:"evidenceId"`
+	State                 coredata.EvidenceState `json:"state"`
+	StateTransitionReason *string                `json:"stateTransitionReason,omitempty"`
+}
+
+type UpdateEvidenceStatePayload struct {
+	Evidence *Evidence `json:"evidence"`
+}
+
 type UpdateFrameworkInput struct {
 	ID              gid.GID `json:"id"`
 	ExpectedVersion int     `json:"expectedVersion"`
@@ -427,9 +918,11 @@ type UpdateFrameworkPayload struct {
 }
 
 type UpdateOrganizationInput struct {
-	OrganizationID gid.GID         `json:"organizationId"`
-	Name           *string         `json:"name,omitempty"`
-	Logo           *graphql.Upload `json:"logo,omitempty"`
+	OrganizationID       gid.GID         `json:"organizationId"`
+	Name                 *string         `json:"name,omitempty"`
+	Logo                 *graphql.Upload `json:"logo,omitempty"`
+	MfaRequired          *bool           `json:"mfaRequired,omitempty"`
+	AutoJoinEmailDomains []string        `json:"autoJoinEmailDomains,omitempty"`
 }
 
 type UpdateOrganizationPayload struct {
@@ -464,12 +957,13 @@ type UpdatePolicyPayload struct {
 }
 
 type UpdateTaskInput struct {
-	TaskID          gid.GID             `json:"taskId"`
-	ExpectedVersion int                 `json:"expectedVersion"`
-	Name            *string             `json:"name,omitempty"`
-	Description     *string             `json:"description,omitempty"`
-	State           *coredata.TaskState `json:"state,omitempty"`
-	TimeEstimate    *time.Duration      `json:"timeEstimate,omitempty"`
+	TaskID                gid.GID             `json:"taskId"`
+	ExpectedVersion       int                 `json:"expectedVersion"`
+	Name                  *string             `json:"name,omitempty"`
+	Description           *string             `json:"description,omitempty"`
+	State                 *coredata.TaskState `json:"state,omitempty"`
+	TimeEstimate          *time.Duration      `json:"timeEstimate,omitempty"`
+	StateTransitionReason *string             `json:"stateTransitionReason,omitempty"`
 }
 
 type UpdateTaskPayload struct {
@@ -494,6 +988,26 @@ type UpdateVendorPayload struct {
 	Vendor *Vendor `json:"vendor"`
 }
 
+type UpdateViewerEmailInput struct {
+	Email string `json:"email"`
+}
+
+type UpdateViewerEmailPayload struct {
+	Success bool `json:"success"`
+}
+
+type UpgradeFrameworkInput struct {
+	FrameworkID gid.GID        `json:"frameworkId"`
+	File        graphql.Upload `json:"file"`
+	DryRun      *bool          `json:"dryRun,omitempty"`
+}
+
+type UpgradeFrameworkPayload struct {
+	Framework *Framework                `json:"framework"`
+	DryRun    bool                      `json:"dryRun"`
+	Changes   []*FrameworkUpgradeChange `json:"changes"`
+}
+
 type UploadEvidenceInput struct {
 	TaskID gid.GID        `json:"taskId"`
 	Name   string         `json:"name"`
@@ -505,11 +1019,12 @@ type UploadEvidencePayload struct {
 }
 
 type User struct {
-	ID        gid.GID   `json:"id"`
-	FullName  string    `json:"fullName"`
-	Email     string    `json:"email"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
+	ID         gid.GID   `json:"id"`
+	FullName   string    `json:"fullName"`
+	Email      string    `json:"email"`
+	MfaEnabled bool      `json:"mfaEnabled"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
 }
 
 func (User) IsNode()             {}
@@ -554,10 +1069,29 @@ type VendorEdge struct {
 	Node   *Vendor        `json:"node"`
 }
 
+type VerifyEmailDomainInput struct {
+	EmailDomainID gid.GID `json:"emailDomainId"`
+}
+
+type VerifyEmailDomainPayload struct {
+	EmailDomain *EmailDomain `json:"emailDomain"`
+}
+
 type Viewer struct {
-	ID            gid.GID                 `json:"id"`
-	User          *User                   `json:"user"`
-	Organizations *OrganizationConnection `json:"organizations"`
+	ID                  gid.GID                 `json:"id"`
+	User                *User                   `json:"user"`
+	Organizations       *OrganizationConnection `json:"organizations"`
+	WebAuthnCredentials []*WebAuthnCredential   `json:"webAuthnCredentials"`
+	Sessions            []*Session              `json:"sessions"`
+}
+
+type WebAuthnCredential struct {
+	ID             gid.GID    `json:"id"`
+	Name           string     `json:"name"`
+	BackupEligible bool       `json:"backupEligible"`
+	LastUsedAt     *time.Time `json:"lastUsedAt,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	UpdatedAt      time.Time  `json:"updatedAt"`
 }
 
 type OrganizationOrderField string
@@ -45,10 +45,11 @@ func NewUserEdge(user *coredata.User, orderBy coredata.UserOrderField) *UserEdge
 
 func NewUser(u *coredata.User) *User {
 	return &User{
-		ID:        u.ID,
-		Email:     u.EmailAddress,
-		FullName:  u.FullName,
-		CreatedAt: u.CreatedAt,
-		UpdatedAt: u.UpdatedAt,
+		ID:         u.ID,
+		Email:      u.EmailAddress,
+		FullName:   u.FullName,
+		MfaEnabled: u.MFAEnabled(),
+		CreatedAt:  u.CreatedAt,
+		UpdatedAt:  u.UpdatedAt,
 	}
 }
@@ -7,7 +7,9 @@ package console_v1
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/getprobo/probo/pkg/coredata"
@@ -16,9 +18,71 @@ import (
 	"github.com/getprobo/probo/pkg/probo"
 	"github.com/getprobo/probo/pkg/server/api/console/v1/schema"
 	"github.com/getprobo/probo/pkg/server/api/console/v1/types"
+	"github.com/getprobo/probo/pkg/usrmgr"
 	"github.com/vektah/gqlparser/v2/gqlerror"
 )
 
+// CreatedBy is the resolver for the createdBy field.
+func (r *apiTokenResolver) CreatedBy(ctx context.Context, obj *types.APIToken) (*types.User, error) {
+	r.GetTenantServiceIfAuthorized(ctx, obj.ID.TenantID())
+
+	apiToken, err := r.usrmgrSvc.GetAPIToken(ctx, obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load api token: %w", err)
+	}
+
+	user, err := r.usrmgrSvc.GetUserByID(ctx, apiToken.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load user: %w", err)
+	}
+
+	return types.NewUser(user), nil
+}
+
+// Covered is the resolver for the covered field.
+func (r *controlResolver) Covered(ctx context.Context, obj *types.Control) (bool, error) {
+	svc := r.GetTenantServiceIfAuthorized(ctx, obj.ID.TenantID())
+
+	covered, err := svc.Controls.IsCovered(ctx, obj.ID)
+	if err != nil {
+		return false, fmt.Errorf("cannot load control coverage: %w", err)
+	}
+
+	return covered, nil
+}
+
+// MappedControls is the resolver for the mappedControls field.
+func (r *controlResolver) MappedControls(ctx context.Context, obj *types.Control) ([]*types.MappedControl, error) {
+	svc := r.GetTenantServiceIfAuthorized(ctx, obj.ID.TenantID())
+
+	controls, err := svc.Controls.ListMapped(ctx, obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list mapped controls: %w", err)
+	}
+
+	frameworks := make(map[gid.GID]*types.Framework)
+	mappedControls := make([]*types.MappedControl, len(controls))
+	for i, control := range controls {
+		framework, ok := frameworks[control.FrameworkID]
+		if !ok {
+			f, err := svc.Frameworks.Get(ctx, control.FrameworkID)
+			if err != nil {
+				return nil, fmt.Errorf("cannot load framework: %w", err)
+			}
+
+			framework = types.NewFramework(f)
+			frameworks[control.FrameworkID] = framework
+		}
+
+		mappedControls[i] = &types.MappedControl{
+			Framework: framework,
+			Control:   types.NewControl(control),
+		}
+	}
+
+	return mappedControls, nil
+}
+
 // Tasks is the resolver for the tasks field.
 func (r *controlResolver) Tasks(ctx context.Context, obj *types.Control, first *int, after *page.CursorKey, last *int, before *page.CursorKey, orderBy *types.TaskOrderBy) (*types.TaskConnection, error) {
 	svc := r.GetTenantServiceIfAuthorized(ctx, obj.ID.TenantID())
@@ -44,6 +108,41 @@ func (r *controlResolver) Tasks(ctx context.Context, obj *types.Control, first *
 	return types.NewTaskConnection(page), nil
 }
 
+// StateTransitions is the resolver for the stateTransitions field.
+func (r *controlResolver) StateTransitions(ctx context.Context, obj *types.Control, first *int, after *page.CursorKey, last *int, before *page.CursorKey, orderBy *types.StateTransitionOrderBy) (*types.ControlStateTransitionConnection, error) {
+	svc := r.GetTenantServiceIfAuthorized(ctx, obj.ID.TenantID())
+
+	pageOrderBy := page.OrderBy[coredata.StateTransitionOrderField]{
+		Field:     coredata.StateTransitionOrderFieldCreatedAt,
+		Direction: page.OrderDirectionDesc,
+	}
+	if orderBy != nil {
+		pageOrderBy = page.OrderBy[coredata.StateTransitionOrderField]{
+			Field:     orderBy.Field,
+			Direction: orderBy.Direction,
+		}
+	}
+
+	cursor := types.NewCursor(first, after, last, before, pageOrderBy)
+
+	page, err := svc.Controls.ListStateTransitions(ctx, obj.ID, cursor)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list control state transitions: %w", err)
+	}
+
+	userIDs := make([]*gid.GID, len(page.Data))
+	for i, transition := range page.Data {
+		userIDs[i] = transition.ChangedByID
+	}
+
+	users, err := r.loadUsers(ctx, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load state transition users: %w", err)
+	}
+
+	return types.NewControlStateTransitionConnection(page, users), nil
+}
+
 // FileURL is the resolver for the fileUrl field.
 func (r *evidenceResolver) FileURL(ctx context.Context, obj *types.Evidence) (string, error) {
 	svc := r.GetTenantServiceIfAuthorized(ctx, obj.ID.TenantID())
@@ -56,13 +155,48 @@ func (r *evidenceResolver) FileURL(ctx context.Context, obj *types.Evidence) (st
 	return *fileURL, nil
 }
 
+// StateTransitions is the resolver for the stateTransitions field.
+func (r *evidenceResolver) StateTransitions(ctx context.Context, obj *types.Evidence, first *int, after *page.CursorKey, last *int, before *page.CursorKey, orderBy *types.StateTransitionOrderBy) (*types.EvidenceStateTransitionConnection, error) {
+	svc := r.GetTenantServiceIfAuthorized(ctx, obj.ID.TenantID())
+
+	pageOrderBy := page.OrderBy[coredata.StateTransitionOrderField]{
+		Field:     coredata.StateTransitionOrderFieldCreatedAt,
+		Direction: page.OrderDirectionDesc,
+	}
+	if orderBy != nil {
+		pageOrderBy = page.OrderBy[coredata.StateTransitionOrderField]{
+			Field:     orderBy.Field,
+			Direction: orderBy.Direction,
+		}
+	}
+
+	cursor := types.NewCursor(first, after, last, before, pageOrderBy)
+
+	page, err := svc.Evidences.ListStateTransitions(ctx, obj.ID, cursor)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list evidence state transitions: %w", err)
+	}
+
+	userIDs := make([]*gid.GID, len(page.Data))
+	for i, transition := range page.Data {
+		userIDs[i] = transition.ChangedByID
+	}
+
+	users, err := r.loadUsers(ctx, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load state transition users: %w", err)
+	}
+
+	return types.NewEvidenceStateTransitionConnection(page, users), nil
+}
+
 // Controls is the resolver for the controls field.
 func (r *frameworkResolver) Controls(ctx context.Context, obj *types.Framework, first *int, after *page.CursorKey, last *int, before *page.CursorKey, orderBy *types.ControlOrderBy) (*types.ControlConnection, error) {
 	svc := r.GetTenantServiceIfAuthorized(ctx, obj.ID.TenantID())
 
 	pageOrderBy := page.OrderBy[coredata.ControlOrderField]{
-		Field:     coredata.ControlOrderFieldCreatedAt,
-		Direction: page.OrderDirectionDesc,
+		Field:     coredata.ControlOrderFieldPosition,
+		Direction: page.OrderDirectionAsc,
 	}
 	if orderBy != nil {
 		pageOrderBy = page.OrderBy[coredata.ControlOrderField]{
@@ -81,9 +215,50 @@ func (r *frameworkResolver) Controls(ctx context.Context, obj *types.Framework,
 	return types.NewControlConnection(page), nil
 }
 
+// ExportDocument is the resolver for the exportDocument field.
+func (r *frameworkResolver) ExportDocument(ctx context.Context, obj *types.Framework) (string, error) {
+	svc := r.GetTenantServiceIfAuthorized(ctx, obj.ID.TenantID())
+
+	document, err := svc.Frameworks.Export(ctx, obj.ID)
+	if err != nil {
+		return "", fmt.Errorf("cannot export framework: %w", err)
+	}
+
+	data, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("cannot encode framework: %w", err)
+	}
+
+	return string(data), nil
+}
+
+// StandardCoverages is the resolver for the standardCoverages field.
+func (r *frameworkResolver) StandardCoverages(ctx context.Context, obj *types.Framework) ([]*types.StandardCoverage, error) {
+	svc := r.GetTenantServiceIfAuthorized(ctx, obj.ID.TenantID())
+
+	coverages, err := svc.Frameworks.ListStandardCoverages(ctx, obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list standard coverages: %w", err)
+	}
+
+	return types.NewStandardCoverages(coverages), nil
+}
+
+// Readiness is the resolver for the readiness field.
+func (r *frameworkResolver) Readiness(ctx context.Context, obj *types.Framework) (*types.Readiness, error) {
+	svc := r.GetTenantServiceIfAuthorized(ctx, obj.ID.TenantID())
+
+	readiness, err := svc.Frameworks.GetReadiness(ctx, obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load framework readiness: %w", err)
+	}
+
+	return types.NewReadiness(readiness), nil
+}
+
 // CreateVendor is the resolver for the createVendor field.
 func (r *mutationResolver) CreateVendor(ctx context.Context, input types.CreateVendorInput) (*types.CreateVendorPayload, error) {
-	svc := r.GetTenantServiceIfAuthorized(ctx, input.OrganizationID.TenantID())
+	svc := r.GetTenantServiceIfPermitted(ctx, input.OrganizationID.TenantID(), usrmgr.PermissionWrite)
 
 	vendor, err := svc.Vendors.Create(ctx, probo.CreateVendorRequest{
 		OrganizationID:       input.OrganizationID,
@@ -107,7 +282,7 @@ func (r *mutationResolver) CreateVendor(ctx context.Context, input types.CreateV
 
 // UpdateVendor is the resolver for the updateVendor field.
 func (r *mutationResolver) UpdateVendor(ctx context.Context, input types.UpdateVendorInput) (*types.UpdateVendorPayload, error) {
-	svc := r.GetTenantServiceIfAuthorized(ctx, input.ID.TenantID())
+	svc := r.GetTenantServiceIfPermitted(ctx, input.ID.TenantID(), usrmgr.PermissionWrite)
 
 	vendor, err := svc.Vendors.Update(ctx, probo.UpdateVendorRequest{
 		ID:                   input.ID,
@@ -133,7 +308,7 @@ func (r *mutationResolver) UpdateVendor(ctx context.Context, input types.UpdateV
 
 // DeleteVendor is the resolver for the deleteVendor field.
 func (r *mutationResolver) DeleteVendor(ctx context.Context, input types.DeleteVendorInput) (*types.DeleteVendorPayload, error) {
-	svc := r.GetTenantServiceIfAuthorized(ctx, input.VendorID.TenantID())
+	svc := r.GetTenantServiceIfPermitted(ctx, input.VendorID.TenantID(), usrmgr.PermissionDelete)
 
 	err := svc.Vendors.Delete(ctx, input.VendorID)
 	if err != nil {
@@ -147,7 +322,7 @@ func (r *mutationResolver) DeleteVendor(ctx context.Context, input types.DeleteV
 
 // CreatePeople is the resolver for the createPeople field.
 func (r *mutationResolver) CreatePeople(ctx context.Context, input types.CreatePeopleInput) (*types.CreatePeoplePayload, error) {
-	svc := r.GetTenantServiceIfAuthorized(ctx, input.OrganizationID.TenantID())
+	svc := r.GetTenantServiceIfPermitted(ctx, input.OrganizationID.TenantID(), usrmgr.PermissionWrite)
 
 	people, err := svc.Peoples.Create(ctx, probo.CreatePeopleRequest{
 		OrganizationID:           input.OrganizationID,
@@ -168,7 +343,7 @@ func (r *mutationResolver) CreatePeople(ctx context.Context, input types.CreateP
 
 // UpdatePeople is the resolver for the updatePeople field.
 func (r *mutationResolver) UpdatePeople(ctx context.Context, input types.UpdatePeopleInput) (*types.UpdatePeoplePayload, error) {
-	svc := r.GetTenantServiceIfAuthorized(ctx, input.ID.TenantID())
+	svc := r.GetTenantServiceIfPermitted(ctx, input.ID.TenantID(), usrmgr.PermissionWrite)
 
 	people, err := svc.Peoples.Update(ctx, probo.UpdatePeopleRequest{
 		ID:                       input.ID,
@@ -189,7 +364,7 @@ func (r *mutationResolver) UpdatePeople(ctx context.Context, input types.UpdateP
 
 // DeletePeople is the resolver for the deletePeople field.
 func (r *mutationResolver) DeletePeople(ctx context.Context, input types.DeletePeopleInput) (*types.DeletePeoplePayload, error) {
-	svc := r.GetTenantServiceIfAuthorized(ctx, input.PeopleID.TenantID())
+	svc := r.GetTenantServiceIfPermitted(ctx, input.PeopleID.TenantID(), usrmgr.PermissionDelete)
 
 	err := svc.Peoples.Delete(ctx, input.PeopleID)
 	if err != nil {
@@ -203,6 +378,10 @@ func (r *mutationResolver) DeletePeople(ctx context.Context, input types.DeleteP
 
 // CreateOrganization is the resolver for the createOrganization field.
 func (r *mutationResolver) CreateOrganization(ctx context.Context, input types.CreateOrganizationInput) (*types.CreateOrganizationPayload, error) {
+	if APITokenFromContext(ctx) != nil {
+		return nil, fmt.Errorf("organizations cannot be created with an api token")
+	}
+
 	svc := r.proboSvc.WithTenant(gid.NewTenantID())
 
 	organization, err := svc.Organizations.Create(ctx, probo.CreateOrganizationRequest{
@@ -212,7 +391,7 @@ func (r *mutationResolver) CreateOrganization(ctx context.Context, input types.C
 		return nil, fmt.Errorf("cannot create organization: %w", err)
 	}
 
-	err = r.usrmgrSvc.EnrollUserInOrganization(ctx, UserFromContext(ctx).ID, organization.ID)
+	err = r.usrmgrSvc.EnrollUserInOrganization(ctx, UserFromContext(ctx).ID, organization.ID, coredata.MembershipRoleOwner)
 	if err != nil {
 		return nil, fmt.Errorf("cannot add user to organization: %w", err)
 	}
@@ -220,6 +399,9 @@ func (r *mutationResolver) CreateOrganization(ctx context.Context, input types.C
 	tenantIDs, _ := ctx.Value(userTenantContextKey).(*[]gid.TenantID)
 	*tenantIDs = append(*tenantIDs, organization.ID.TenantID())
 
+	tenantRoles, _ := ctx.Value(userTenantRoleContextKey).(map[gid.TenantID]coredata.MembershipRole)
+	tenantRoles[organization.ID.TenantID()] = coredata.MembershipRoleOwner
+
 	return &types.CreateOrganizationPayload{
 		OrganizationEdge: types.NewOrganizationEdge(organization, coredata.OrganizationOrderFieldCreatedAt),
 	}, nil
@@ -227,17 +409,27 @@ func (r *mutationResolver) CreateOrganization(ctx context.Context, input types.C
 
 // UpdateOrganization is the resolver for the updateOrganization field.
 func (r *mutationResolver) UpdateOrganization(ctx context.Context, input types.UpdateOrganizationInput) (*types.UpdateOrganizationPayload, error) {
-	svc := r.GetTenantServiceIfAuthorized(ctx, input.OrganizationID.TenantID())
+	svc := r.GetTenantServiceIfPermitted(ctx, input.OrganizationID.TenantID(), usrmgr.PermissionManageOrganization)
+
+	if input.MfaRequired != nil && *input.MfaRequired && !UserFromContext(ctx).MFAEnabled() {
+		return nil, fmt.Errorf("cannot require multi-factor authentication without enabling it for yourself first")
+	}
 
 	req := probo.UpdateOrganizationRequest{
-		ID:   input.OrganizationID,
-		Name: input.Name,
+		ID:          input.OrganizationID,
+		Name:        input.Name,
+		MFARequired: input.MfaRequired,
 	}
 
 	if input.Logo != nil {
 		req.File = input.Logo.File
 	}
 
+	if input.AutoJoinEmailDomains != nil {
+		r.GetTenantServiceIfPermitted(ctx, input.OrganizationID.TenantID(), usrmgr.PermissionManageEmailDomains)
+		req.AutoJoinEmailDomains = &input.AutoJoinEmailDomains
+	}
+
 	organization, err := svc.Organizations.Update(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("cannot update organization: %w", err)
@@ -250,12 +442,235 @@ func (r *mutationResolver) UpdateOrganization(ctx context.Context, input types.U
 
 // DeleteOrganization is the resolver for the deleteOrganization field.
 func (r *mutationResolver) DeleteOrganization(ctx context.Context, input types.DeleteOrganizationInput) (*types.DeleteOrganizationPayload, error) {
+	r.GetTenantServiceIfPermitted(ctx, input.OrganizationID.TenantID(), usrmgr.PermissionDeleteOrganization)
+
 	panic(fmt.Errorf("not implemented: DeleteOrganization - deleteOrganization"))
 }
 
+// ConfigureOidc is the resolver for the configureOidc field.
+func (r *mutationResolver) ConfigureOidc(ctx context.Context, input types.ConfigureOidcInput) (*types.ConfigureOidcPayload, error) {
+	svc := r.GetTenantServiceIfPermitted(ctx, input.OrganizationID.TenantID(), usrmgr.PermissionManageOrganization)
+
+	configuration, err := svc.OIDC.Configure(
+		ctx,
+		probo.ConfigureOIDCRequest{
+			OrganizationID:      input.OrganizationID,
+			IssuerURL:           input.IssuerURL,
+			ClientID:            input.ClientID,
+			ClientSecret:        input.ClientSecret,
+			AllowedEmailDomains: input.AllowedEmailDomains,
+			Enabled:             input.Enabled,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot configure oidc: %w", err)
+	}
+
+	return &types.ConfigureOidcPayload{
+		OidcConfiguration: types.NewOidcConfiguration(configuration, r.usrmgrSvc.OIDCRedirectURL()),
+	}, nil
+}
+
+// DeleteOidcConfiguration is the resolver for the deleteOidcConfiguration field.
+func (r *mutationResolver) DeleteOidcConfiguration(ctx context.Context, input types.DeleteOidcConfigurationInput) (*types.DeleteOidcConfigurationPayload, error) {
+	svc := r.GetTenantServiceIfPermitted(ctx, input.OrganizationID.TenantID(), usrmgr.PermissionManageOrganization)
+
+	configuration, err := svc.OIDC.Delete(ctx, input.OrganizationID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot delete oidc configuration: %w", err)
+	}
+
+	return &types.DeleteOidcConfigurationPayload{
+		DeletedOidcConfigurationID: configuration.ID,
+	}, nil
+}
+
+// ConfigureSaml is the resolver for the configureSaml field.
+func (r *mutationResolver) ConfigureSaml(ctx context.Context, input types.ConfigureSamlInput) (*types.ConfigureSamlPayload, error) {
+	svc := r.GetTenantServiceIfPermitted(ctx, input.OrganizationID.TenantID(), usrmgr.PermissionManageOrganization)
+
+	configuration, err := svc.SAML.Configure(
+		ctx,
+		probo.ConfigureSAMLRequest{
+			OrganizationID:      input.OrganizationID,
+			IdPMetadata:         input.IdpMetadata,
+			EmailAttribute:      input.EmailAttribute,
+			FullNameAttribute:   input.FullNameAttribute,
+			AllowedEmailDomains: input.AllowedEmailDomains,
+			Enabled:             input.Enabled,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot configure saml: %w", err)
+	}
+
+	return &types.ConfigureSamlPayload{
+		SamlConfiguration: types.NewSamlConfiguration(
+			configuration,
+			r.usrmgrSvc.SAMLMetadataURL(configuration.OrganizationID),
+			r.usrmgrSvc.SAMLConsumerURL(configuration.OrganizationID),
+		),
+	}, nil
+}
+
+// DeleteSamlConfiguration is the resolver for the deleteSamlConfiguration field.
+func (r *mutationResolver) DeleteSamlConfiguration(ctx context.Context, input types.DeleteSamlConfigurationInput) (*types.DeleteSamlConfigurationPayload, error) {
+	svc := r.GetTenantServiceIfPermitted(ctx, input.OrganizationID.TenantID(), usrmgr.PermissionManageOrganization)
+
+	configuration, err := svc.SAML.Delete(ctx, input.OrganizationID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot delete saml configuration: %w", err)
+	}
+
+	return &types.DeleteSamlConfigurationPayload{
+		DeletedSamlConfigurationID: configuration.ID,
+	}, nil
+}
+
+// GenerateScimToken is the resolver for the generateScimToken field.
+func (r *mutationResolver) GenerateScimToken(ctx context.Context, input types.GenerateScimTokenInput) (*types.GenerateScimTokenPayload, error) {
+	r.GetTenantServiceIfPermitted(ctx, input.OrganizationID.TenantID(), usrmgr.PermissionManageOrganization)
+
+	configuration, token, err := r.usrmgrSvc.GenerateSCIMToken(ctx, input.OrganizationID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot generate scim token: %w", err)
+	}
+
+	return &types.GenerateScimTokenPayload{
+		ScimConfiguration: types.NewScimConfiguration(configuration, r.usrmgrSvc.SCIMBaseURL()),
+		Token:             token,
+	}, nil
+}
+
+// CreateAPIToken is the resolver for the createApiToken field.
+func (r *mutationResolver) CreateAPIToken(ctx context.Context, input types.CreateAPITokenInput) (*types.CreateAPITokenPayload, error) {
+	r.GetTenantServiceIfAuthorized(ctx, input.OrganizationID.TenantID())
+
+	// A token must not be able to outlive itself by issuing new ones.
+	if APITokenFromContext(ctx) != nil {
+		return nil, fmt.Errorf("api tokens cannot be created with an api token")
+	}
+
+	user := UserFromContext(ctx)
+
+	apiToken, token, err := r.usrmgrSvc.CreateAPIToken(
+		ctx,
+		user.ID,
+		input.OrganizationID,
+		input.Name,
+		input.ExpiresAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create api token: %w", err)
+	}
+
+	return &types.CreateAPITokenPayload{
+		APIToken: types.NewAPIToken(apiToken),
+		Token:    token,
+	}, nil
+}
+
+// RevokeAPIToken is the resolver for the revokeApiToken field.
+func (r *mutationResolver) RevokeAPIToken(ctx context.Context, input types.RevokeAPITokenInput) (*types.RevokeAPITokenPayload, error) {
+	r.GetTenantServiceIfAuthorized(ctx, input.APITokenID.TenantID())
+
+	apiToken, err := r.usrmgrSvc.GetAPIToken(ctx, input.APITokenID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load api token: %w", err)
+	}
+
+	// Members can revoke their own tokens, revoking the tokens of other
+	// members requires managing the organization.
+	if apiToken.UserID != UserFromContext(ctx).ID &&
+		!r.HasPermission(ctx, input.APITokenID.TenantID(), usrmgr.PermissionManageOrganization) {
+		return nil, fmt.Errorf("not allowed to revoke this api token")
+	}
+
+	apiToken, err = r.usrmgrSvc.RevokeAPIToken(ctx, input.APITokenID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot revoke api token: %w", err)
+	}
+
+	return &types.RevokeAPITokenPayload{
+		RevokedAPITokenID: apiToken.ID,
+	}, nil
+}
+
+// RevokeSession is the resolver for the revokeSession field.
+func (r *mutationResolver) RevokeSession(ctx context.Context, input types.RevokeSessionInput) (*types.RevokeSessionPayload, error) {
+	if APITokenFromContext(ctx) != nil {
+		return nil, fmt.Errorf("sessions cannot be revoked with an api token")
+	}
+
+	err := r.usrmgrSvc.RevokeSession(ctx, UserFromContext(ctx).ID, input.SessionID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot revoke session: %w", err)
+	}
+
+	return &types.RevokeSessionPayload{
+		RevokedSessionID: input.SessionID,
+	}, nil
+}
+
+// RevokeAllOtherSessions is the resolver for the revokeAllOtherSessions field.
+func (r *mutationResolver) RevokeAllOtherSessions(ctx context.Context) (*types.RevokeAllOtherSessionsPayload, error) {
+	session := SessionFromContext(ctx)
+	if session == nil {
+		return nil, fmt.Errorf("sessions cannot be revoked with an api token")
+	}
+
+	revoked, err := r.usrmgrSvc.RevokeAllOtherSessions(ctx, UserFromContext(ctx).ID, session.ID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot revoke sessions: %w", err)
+	}
+
+	return &types.RevokeAllOtherSessionsPayload{
+		RevokedSessionCount: revoked,
+	}, nil
+}
+
+// ChangeMemberRole is the resolver for the changeMemberRole field.
+func (r *mutationResolver) ChangeMemberRole(ctx context.Context, input types.ChangeMemberRoleInput) (*types.ChangeMemberRolePayload, error) {
+	r.GetTenantServiceIfPermitted(ctx, input.OrganizationID.TenantID(), usrmgr.PermissionManageMembers)
+
+	membership, err := r.usrmgrSvc.ChangeMemberRole(
+		ctx,
+		UserFromContext(ctx).ID,
+		input.OrganizationID,
+		input.UserID,
+		input.Role,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot change member role: %w", err)
+	}
+
+	user, err := r.usrmgrSvc.GetUserByID(ctx, membership.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load user: %w", err)
+	}
+
+	return &types.ChangeMemberRolePayload{
+		Membership: types.NewMembership(membership, user),
+	}, nil
+}
+
+// DeleteScimConfiguration is the resolver for the deleteScimConfiguration field.
+func (r *mutationResolver) DeleteScimConfiguration(ctx context.Context, input types.DeleteScimConfigurationInput) (*types.DeleteScimConfigurationPayload, error) {
+	r.GetTenantServiceIfPermitted(ctx, input.OrganizationID.TenantID(), usrmgr.PermissionManageOrganization)
+
+	configuration, err := r.usrmgrSvc.DeleteSCIMConfiguration(ctx, input.OrganizationID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot delete scim configuration: %w", err)
+	}
+
+	return &types.DeleteScimConfigurationPayload{
+		DeletedScimConfigurationID: configuration.ID,
+	}, nil
+}
+
 // CreateTask is the resolver for the createTask field.
 func (r *mutationResolver) CreateTask(ctx context.Context, input types.CreateTaskInput) (*types.CreateTaskPayload, error) {
-	svc := r.GetTenantServiceIfAuthorized(ctx, input.ControlID.TenantID())
+	svc := r.GetTenantServiceIfPermitted(ctx, input.ControlID.TenantID(), usrmgr.PermissionWrite)
 
 	task, err := svc.Tasks.Create(ctx, probo.CreateTaskRequest{
 		ControlID:    input.ControlID,
@@ -274,14 +689,16 @@ func (r *mutationResolver) CreateTask(ctx context.Context, input types.CreateTas
 
 // UpdateTask is the resolver for the updateTask field.
 func (r *mutationResolver) UpdateTask(ctx context.Context, input types.UpdateTaskInput) (*types.UpdateTaskPayload, error) {
-	svc := r.GetTenantServiceIfAuthorized(ctx, input.TaskID.TenantID())
+	svc := r.GetTenantServiceIfPermitted(ctx, input.TaskID.TenantID(), usrmgr.PermissionWrite)
 
 	task, err := svc.Tasks.Update(ctx, probo.UpdateTaskRequest{
-		ID:              input.TaskID,
-		ExpectedVersion: input.ExpectedVersion,
-		Name:            input.Name,
-		Description:     input.Description,
-		State:           input.State,
+		ID:                    input.TaskID,
+		ExpectedVersion:       input.ExpectedVersion,
+		Name:                  input.Name,
+		Description:           input.Description,
+		State:                 input.State,
+		StateTransitionReason: input.StateTransitionReason,
+		ChangedByID:           UserFromContext(ctx).ID,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("cannot update task: %w", err)
@@ -294,7 +711,7 @@ func (r *mutationResolver) UpdateTask(ctx context.Context, input types.UpdateTas
 
 // DeleteTask is the resolver for the deleteTask field.
 func (r *mutationResolver) DeleteTask(ctx context.Context, input types.DeleteTaskInput) (*types.DeleteTaskPayload, error) {
-	svc := r.GetTenantServiceIfAuthorized(ctx, input.TaskID.TenantID())
+	svc := r.GetTenantServiceIfPermitted(ctx, input.TaskID.TenantID(), usrmgr.PermissionDelete)
 
 	err := svc.Tasks.Delete(ctx, input.TaskID)
 	if err != nil {
@@ -308,7 +725,7 @@ func (r *mutationResolver) DeleteTask(ctx context.Context, input types.DeleteTas
 
 // AssignTask is the resolver for the assignTask field.
 func (r *mutationResolver) AssignTask(ctx context.Context, input types.AssignTaskInput) (*types.AssignTaskPayload, error) {
-	svc := r.GetTenantServiceIfAuthorized(ctx, input.TaskID.TenantID())
+	svc := r.GetTenantServiceIfPermitted(ctx, input.TaskID.TenantID(), usrmgr.PermissionWrite)
 
 	task, err := svc.Tasks.Assign(ctx, input.TaskID, input.AssignedToID)
 	if err != nil {
@@ -322,7 +739,7 @@ func (r *mutationResolver) AssignTask(ctx context.Context, input types.AssignTas
 
 // UnassignTask is the resolver for the unassignTask field.
 func (r *mutationResolver) UnassignTask(ctx context.Context, input types.UnassignTaskInput) (*types.UnassignTaskPayload, error) {
-	svc := r.GetTenantServiceIfAuthorized(ctx, input.TaskID.TenantID())
+	svc := r.GetTenantServiceIfPermitted(ctx, input.TaskID.TenantID(), usrmgr.PermissionWrite)
 
 	task, err := svc.Tasks.Unassign(ctx, input.TaskID)
 	if err != nil {
@@ -336,7 +753,7 @@ func (r *mutationResolver) UnassignTask(ctx context.Context, input types.Unassig
 
 // CreateFramework is the resolver for the createFramework field.
 func (r *mutationResolver) CreateFramework(ctx context.Context, input types.CreateFrameworkInput) (*types.CreateFrameworkPayload, error) {
-	svc := r.GetTenantServiceIfAuthorized(ctx, input.OrganizationID.TenantID())
+	svc := r.GetTenantServiceIfPermitted(ctx, input.OrganizationID.TenantID(), usrmgr.PermissionWrite)
 
 	framework, err := svc.Frameworks.Create(ctx, probo.CreateFrameworkRequest{
 		OrganizationID: input.OrganizationID,
@@ -354,7 +771,7 @@ func (r *mutationResolver) CreateFramework(ctx context.Context, input types.Crea
 
 // UpdateFramework is the resolver for the updateFramework field.
 func (r *mutationResolver) UpdateFramework(ctx context.Context, input types.UpdateFrameworkInput) (*types.UpdateFrameworkPayload, error) {
-	svc := r.GetTenantServiceIfAuthorized(ctx, input.ID.TenantID())
+	svc := r.GetTenantServiceIfPermitted(ctx, input.ID.TenantID(), usrmgr.PermissionWrite)
 
 	framework, err := svc.Frameworks.Update(ctx, probo.UpdateFrameworkRequest{
 		ID:              input.ID,
@@ -373,14 +790,14 @@ func (r *mutationResolver) UpdateFramework(ctx context.Context, input types.Upda
 
 // ImportFramework is the resolver for the importFramework field.
 func (r *mutationResolver) ImportFramework(ctx context.Context, input types.ImportFrameworkInput) (*types.ImportFrameworkPayload, error) {
-	svc := r.GetTenantServiceIfAuthorized(ctx, input.OrganizationID.TenantID())
+	svc := r.GetTenantServiceIfPermitted(ctx, input.OrganizationID.TenantID(), usrmgr.PermissionWrite)
 
-	req := probo.ImportFrameworkRequest{}
-	if err := json.NewDecoder(input.File.File).Decode(&req.Data); err != nil {
-		return nil, fmt.Errorf("cannot decode framework: %w", err)
+	document, err := probo.ParseFrameworkDocument(input.File.File)
+	if err != nil {
+		return nil, frameworkDocumentError(ctx, err)
 	}
 
-	framework, err := svc.Frameworks.Import(ctx, input.OrganizationID, req)
+	framework, err := svc.Frameworks.Import(ctx, input.OrganizationID, probo.ImportFrameworkRequest{Data: *document})
 	if err != nil {
 		return nil, fmt.Errorf("cannot import framework: %w", err)
 	}
@@ -390,9 +807,89 @@ func (r *mutationResolver) ImportFramework(ctx context.Context, input types.Impo
 	}, nil
 }
 
+// ImportFrameworkTemplate is the resolver for the importFrameworkTemplate field.
+func (r *mutationResolver) ImportFrameworkTemplate(ctx context.Context, input types.ImportFrameworkTemplateInput) (*types.ImportFrameworkTemplatePayload, error) {
+	svc := r.GetTenantServiceIfPermitted(ctx, input.OrganizationID.TenantID(), usrmgr.PermissionWrite)
+
+	framework, err := svc.Frameworks.ImportTemplate(ctx, input.OrganizationID, input.TemplateID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot import framework template: %w", err)
+	}
+
+	return &types.ImportFrameworkTemplatePayload{
+		FrameworkEdge: types.NewFrameworkEdge(framework, coredata.FrameworkOrderFieldCreatedAt),
+	}, nil
+}
+
+// UpgradeFramework is the resolver for the upgradeFramework field.
+func (r *mutationResolver) UpgradeFramework(ctx context.Context, input types.UpgradeFrameworkInput) (*types.UpgradeFrameworkPayload, error) {
+	svc := r.GetTenantServiceIfPermitted(ctx, input.FrameworkID.TenantID(), usrmgr.PermissionWrite)
+
+	document, err := probo.ParseFrameworkDocument(input.File.File)
+	if err != nil {
+		return nil, frameworkDocumentError(ctx, err)
+	}
+
+	upgrade, err := svc.Frameworks.Upgrade(
+		ctx,
+		probo.UpgradeFrameworkRequest{
+			FrameworkID: input.FrameworkID,
+			Data:        *document,
+			DryRun:      input.DryRun != nil && *input.DryRun,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot upgrade framework: %w", err)
+	}
+
+	return types.NewUpgradeFrameworkPayload(upgrade), nil
+}
+
+// DeleteFramework is the resolver for the deleteFramework field.
+func (r *mutationResolver) DeleteFramework(ctx context.Context, input types.DeleteFrameworkInput) (*types.DeleteFrameworkPayload, error) {
+	svc := r.GetTenantServiceIfPermitted(ctx, input.FrameworkID.TenantID(), usrmgr.PermissionDelete)
+
+	err := svc.Frameworks.Delete(ctx, probo.DeleteFrameworkRequest{
+		ID:               input.FrameworkID,
+		ConfirmationName: input.ConfirmationName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot delete framework: %w", err)
+	}
+
+	return &types.DeleteFrameworkPayload{
+		DeletedFrameworkID: input.FrameworkID,
+	}, nil
+}
+
+// CloneFramework is the resolver for the cloneFramework field.
+func (r *mutationResolver) CloneFramework(ctx context.Context, input types.CloneFrameworkInput) (*types.CloneFrameworkPayload, error) {
+	svc := r.GetTenantServiceIfAuthorized(ctx, input.FrameworkID.TenantID())
+	targetSvc := r.GetTenantServiceIfPermitted(ctx, input.OrganizationID.TenantID(), usrmgr.PermissionWrite)
+
+	framework, err := svc.Frameworks.Clone(
+		ctx,
+		targetSvc,
+		probo.CloneFrameworkRequest{
+			FrameworkID:     input.FrameworkID,
+			OrganizationID:  input.OrganizationID,
+			Name:            input.Name,
+			CopyState:       input.CopyState != nil && *input.CopyState,
+			CopyAssignments: input.CopyAssignments != nil && *input.CopyAssignments,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot clone framework: %w", err)
+	}
+
+	return &types.CloneFrameworkPayload{
+		FrameworkEdge: types.NewFrameworkEdge(framework, coredata.FrameworkOrderFieldCreatedAt),
+	}, nil
+}
+
 // CreateControl is the resolver for the createControl field.
 func (r *mutationResolver) CreateControl(ctx context.Context, input types.CreateControlInput) (*types.CreateControlPayload, error) {
-	svc := r.GetTenantServiceIfAuthorized(ctx, input.FrameworkID.TenantID())
+	svc := r.GetTenantServiceIfPermitted(ctx, input.FrameworkID.TenantID(), usrmgr.PermissionWrite)
 
 	control, err := svc.Controls.Create(ctx, probo.CreateControlRequest{
 		FrameworkID: input.FrameworkID,
@@ -406,35 +903,177 @@ func (r *mutationResolver) CreateControl(ctx context.Context, input types.Create
 	}
 
 	return &types.CreateControlPayload{
-		ControlEdge: types.NewControlEdge(control, coredata.ControlOrderFieldCreatedAt),
+		ControlEdge: types.NewControlEdge(control, coredata.ControlOrderFieldPosition),
 	}, nil
 }
 
 // UpdateControl is the resolver for the updateControl field.
 func (r *mutationResolver) UpdateControl(ctx context.Context, input types.UpdateControlInput) (*types.UpdateControlPayload, error) {
-	svc := r.GetTenantServiceIfAuthorized(ctx, input.ID.TenantID())
+	svc := r.GetTenantServiceIfPermitted(ctx, input.ID.TenantID(), usrmgr.PermissionWrite)
 
 	control, err := svc.Controls.Update(ctx, probo.UpdateControlRequest{
+		ID:                    input.ID,
+		ExpectedVersion:       input.ExpectedVersion,
+		Name:                  input.Name,
+		Description:           input.Description,
+		Category:              input.Category,
+		State:                 input.State,
+		Importance:            input.Importance,
+		StateTransitionReason: input.StateTransitionReason,
+		ChangedByID:           UserFromContext(ctx).ID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot update control: %w", err)
+	}
+
+	return &types.UpdateControlPayload{
+		Control: types.NewControl(control),
+	}, nil
+}
+
+// DeleteControl is the resolver for the deleteControl field.
+func (r *mutationResolver) DeleteControl(ctx context.Context, input types.DeleteControlInput) (*types.DeleteControlPayload, error) {
+	svc := r.GetTenantServiceIfPermitted(ctx, input.ControlID.TenantID(), usrmgr.PermissionDelete)
+
+	err := svc.Controls.Delete(ctx, probo.DeleteControlRequest{
+		ID:              input.ControlID,
+		ExpectedVersion: input.ExpectedVersion,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot delete control: %w", err)
+	}
+
+	return &types.DeleteControlPayload{
+		DeletedControlID: input.ControlID,
+	}, nil
+}
+
+// ReorderControl is the resolver for the reorderControl field.
+func (r *mutationResolver) ReorderControl(ctx context.Context, input types.ReorderControlInput) (*types.ReorderControlPayload, error) {
+	svc := r.GetTenantServiceIfPermitted(ctx, input.ID.TenantID(), usrmgr.PermissionWrite)
+
+	control, err := svc.Controls.Reorder(ctx, probo.ReorderControlRequest{
 		ID:              input.ID,
 		ExpectedVersion: input.ExpectedVersion,
-		Name:            input.Name,
-		Description:     input.Description,
-		Category:        input.Category,
-		State:           input.State,
-		Importance:      input.Importance,
+		Position:        input.Position,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("cannot update control: %w", err)
+		return nil, fmt.Errorf("cannot reorder control: %w", err)
+	}
+
+	return &types.ReorderControlPayload{
+		Control: types.NewControl(control),
+	}, nil
+}
+
+// MoveControl is the resolver for the moveControl field.
+func (r *mutationResolver) MoveControl(ctx context.Context, input types.MoveControlInput) (*types.MoveControlPayload, error) {
+	svc := r.GetTenantServiceIfPermitted(ctx, input.ID.TenantID(), usrmgr.PermissionWrite)
+
+	if input.FrameworkID.TenantID() != input.ID.TenantID() {
+		return nil, fmt.Errorf("cannot move control to a framework of another organization")
+	}
+
+	control, err := svc.Controls.Move(ctx, probo.MoveControlRequest{
+		ID:              input.ID,
+		ExpectedVersion: input.ExpectedVersion,
+		FrameworkID:     input.FrameworkID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot move control: %w", err)
+	}
+
+	return &types.MoveControlPayload{
+		Control: types.NewControl(control),
+	}, nil
+}
+
+// CreateStandardMapping is the resolver for the createStandardMapping field.
+func (r *mutationResolver) CreateStandardMapping(ctx context.Context, input types.CreateStandardMappingInput) (*types.CreateStandardMappingPayload, error) {
+	svc := r.GetTenantServiceIfPermitted(ctx, input.OrganizationID.TenantID(), usrmgr.PermissionWrite)
+
+	standardMapping, err := svc.StandardMappings.Create(
+		ctx,
+		probo.CreateStandardMappingRequest{
+			OrganizationID:    input.OrganizationID,
+			SourceFrameworkID: input.SourceFrameworkID,
+			SourceStandard:    input.SourceStandard,
+			TargetFrameworkID: input.TargetFrameworkID,
+			TargetStandard:    input.TargetStandard,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create standard mapping: %w", err)
+	}
+
+	return &types.CreateStandardMappingPayload{
+		StandardMapping: types.NewStandardMapping(standardMapping),
+	}, nil
+}
+
+// DeleteStandardMapping is the resolver for the deleteStandardMapping field.
+func (r *mutationResolver) DeleteStandardMapping(ctx context.Context, input types.DeleteStandardMappingInput) (*types.DeleteStandardMappingPayload, error) {
+	svc := r.GetTenantServiceIfPermitted(ctx, input.StandardMappingID.TenantID(), usrmgr.PermissionWrite)
+
+	if err := svc.StandardMappings.Delete(ctx, input.StandardMappingID); err != nil {
+		return nil, fmt.Errorf("cannot delete standard mapping: %w", err)
+	}
+
+	return &types.DeleteStandardMappingPayload{
+		DeletedStandardMappingID: input.StandardMappingID,
+	}, nil
+}
+
+// CreateEmailDomain is the resolver for the createEmailDomain field.
+func (r *mutationResolver) CreateEmailDomain(ctx context.Context, input types.CreateEmailDomainInput) (*types.CreateEmailDomainPayload, error) {
+	svc := r.GetTenantServiceIfPermitted(ctx, input.OrganizationID.TenantID(), usrmgr.PermissionManageEmailDomains)
+
+	emailDomain, err := svc.EmailDomains.Create(
+		ctx,
+		probo.CreateEmailDomainRequest{
+			OrganizationID: input.OrganizationID,
+			Domain:         input.Domain,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create email domain: %w", err)
+	}
+
+	return &types.CreateEmailDomainPayload{
+		EmailDomain: types.NewEmailDomain(emailDomain),
+	}, nil
+}
+
+// VerifyEmailDomain is the resolver for the verifyEmailDomain field.
+func (r *mutationResolver) VerifyEmailDomain(ctx context.Context, input types.VerifyEmailDomainInput) (*types.VerifyEmailDomainPayload, error) {
+	svc := r.GetTenantServiceIfPermitted(ctx, input.EmailDomainID.TenantID(), usrmgr.PermissionManageEmailDomains)
+
+	emailDomain, err := svc.EmailDomains.Verify(ctx, input.EmailDomainID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot verify email domain: %w", err)
+	}
+
+	return &types.VerifyEmailDomainPayload{
+		EmailDomain: types.NewEmailDomain(emailDomain),
+	}, nil
+}
+
+// DeleteEmailDomain is the resolver for the deleteEmailDomain field.
+func (r *mutationResolver) DeleteEmailDomain(ctx context.Context, input types.DeleteEmailDomainInput) (*types.DeleteEmailDomainPayload, error) {
+	svc := r.GetTenantServiceIfPermitted(ctx, input.EmailDomainID.TenantID(), usrmgr.PermissionManageEmailDomains)
+
+	if err := svc.EmailDomains.Delete(ctx, input.EmailDomainID); err != nil {
+		return nil, fmt.Errorf("cannot delete email domain: %w", err)
 	}
 
-	return &types.UpdateControlPayload{
-		Control: types.NewControl(control),
+	return &types.DeleteEmailDomainPayload{
+		DeletedEmailDomainID: input.EmailDomainID,
 	}, nil
 }
 
 // UploadEvidence is the resolver for the uploadEvidence field.
 func (r *mutationResolver) UploadEvidence(ctx context.Context, input types.UploadEvidenceInput) (*types.UploadEvidencePayload, error) {
-	svc := r.GetTenantServiceIfAuthorized(ctx, input.TaskID.TenantID())
+	svc := r.GetTenantServiceIfPermitted(ctx, input.TaskID.TenantID(), usrmgr.PermissionWrite)
 
 	req := probo.CreateEvidenceRequest{
 		TaskID: input.TaskID,
@@ -454,7 +1093,7 @@ func (r *mutationResolver) UploadEvidence(ctx context.Context, input types.Uploa
 
 // DeleteEvidence is the resolver for the deleteEvidence field.
 func (r *mutationResolver) DeleteEvidence(ctx context.Context, input types.DeleteEvidenceInput) (*types.DeleteEvidencePayload, error) {
-	svc := r.GetTenantServiceIfAuthorized(ctx, input.EvidenceID.TenantID())
+	svc := r.GetTenantServiceIfPermitted(ctx, input.EvidenceID.TenantID(), usrmgr.PermissionDelete)
 
 	err := svc.Evidences.Delete(ctx, input.EvidenceID)
 	if err != nil {
@@ -466,9 +1105,27 @@ func (r *mutationResolver) DeleteEvidence(ctx context.Context, input types.Delet
 	}, nil
 }
 
+// UpdateEvidenceState is the resolver for the updateEvidenceState field.
+func (r *mutationResolver) UpdateEvidenceState(ctx context.Context, input types.UpdateEvidenceStateInput) (*types.UpdateEvidenceStatePayload, error) {
+	svc := r.GetTenantServiceIfPermitted(ctx, input.EvidenceID.TenantID(), usrmgr.PermissionWrite)
+
+	evidence, err := svc.Evidences.UpdateState(ctx, probo.UpdateEvidenceStateRequest{
+		ID:    input.EvidenceID,
+		State: input.State, StateTransitionReason: input.StateTransitionReason,
+		ChangedByID: UserFromContext(ctx).ID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot update evidence state: %w", err)
+	}
+
+	return &types.UpdateEvidenceStatePayload{
+		Evidence: types.NewEvidence(evidence),
+	}, nil
+}
+
 // CreatePolicy is the resolver for the createPolicy field.
 func (r *mutationResolver) CreatePolicy(ctx context.Context, input types.CreatePolicyInput) (*types.CreatePolicyPayload, error) {
-	svc := r.GetTenantServiceIfAuthorized(ctx, input.OrganizationID.TenantID())
+	svc := r.GetTenantServiceIfPermitted(ctx, input.OrganizationID.TenantID(), usrmgr.PermissionWrite)
 
 	policy, err := svc.Policies.Create(ctx, probo.CreatePolicyRequest{
 		OrganizationID: input.OrganizationID,
@@ -489,7 +1146,7 @@ func (r *mutationResolver) CreatePolicy(ctx context.Context, input types.CreateP
 
 // UpdatePolicy is the resolver for the updatePolicy field.
 func (r *mutationResolver) UpdatePolicy(ctx context.Context, input types.UpdatePolicyInput) (*types.UpdatePolicyPayload, error) {
-	svc := r.GetTenantServiceIfAuthorized(ctx, input.ID.TenantID())
+	svc := r.GetTenantServiceIfPermitted(ctx, input.ID.TenantID(), usrmgr.PermissionWrite)
 
 	policy, err := svc.Policies.Update(ctx, probo.UpdatePolicyRequest{
 		ID:              input.ID,
@@ -511,7 +1168,7 @@ func (r *mutationResolver) UpdatePolicy(ctx context.Context, input types.UpdateP
 
 // DeletePolicy is the resolver for the deletePolicy field.
 func (r *mutationResolver) DeletePolicy(ctx context.Context, input types.DeletePolicyInput) (*types.DeletePolicyPayload, error) {
-	svc := r.GetTenantServiceIfAuthorized(ctx, input.PolicyID.TenantID())
+	svc := r.GetTenantServiceIfPermitted(ctx, input.PolicyID.TenantID(), usrmgr.PermissionDelete)
 
 	err := svc.Policies.Delete(ctx, input.PolicyID)
 	if err != nil {
@@ -534,50 +1191,191 @@ func (r *mutationResolver) ConfirmEmail(ctx context.Context, input types.Confirm
 	return &types.ConfirmEmailPayload{Success: true}, nil
 }
 
+// UpdateViewerEmail is the resolver for the updateViewerEmail field.
+func (r *mutationResolver) UpdateViewerEmail(ctx context.Context, input types.UpdateViewerEmailInput) (*types.UpdateViewerEmailPayload, error) {
+	if APITokenFromContext(ctx) != nil {
+		return nil, fmt.Errorf("email address cannot be changed with an api token")
+	}
+
+	err := r.usrmgrSvc.RequestEmailChange(ctx, UserFromContext(ctx).ID, input.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.UpdateViewerEmailPayload{Success: true}, nil
+}
+
+// ConfirmEmailChange is the resolver for the confirmEmailChange field.
+func (r *mutationResolver) ConfirmEmailChange(ctx context.Context, input types.ConfirmEmailChangeInput) (*types.ConfirmEmailChangePayload, error) {
+	if APITokenFromContext(ctx) != nil {
+		return nil, fmt.Errorf("email address cannot be changed with an api token")
+	}
+
+	_, err := r.usrmgrSvc.ConfirmEmailChange(ctx, UserFromContext(ctx).ID, input.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.ConfirmEmailChangePayload{Success: true}, nil
+}
+
 // InviteUser is the resolver for the inviteUser field.
 func (r *mutationResolver) InviteUser(ctx context.Context, input types.InviteUserInput) (*types.InviteUserPayload, error) {
-	user := UserFromContext(ctx)
+	r.GetTenantServiceIfPermitted(ctx, input.OrganizationID.TenantID(), usrmgr.PermissionManageMembers)
 
-	organizations, err := r.usrmgrSvc.ListOrganizationsForUserID(ctx, user.ID)
+	err := r.usrmgrSvc.InviteUser(ctx, input.OrganizationID, input.FullName, input.Email)
 	if err != nil {
-		panic(fmt.Errorf("failed to list organizations for user: %w", err))
+		return nil, err
 	}
 
-	for _, organization := range organizations {
-		if organization.ID == input.OrganizationID {
-			err := r.usrmgrSvc.InviteUser(ctx, input.OrganizationID, input.FullName, input.Email)
-			if err != nil {
-				return nil, err
-			}
+	return &types.InviteUserPayload{Success: true}, nil
+}
 
-			return &types.InviteUserPayload{Success: true}, nil
-		}
+// ResendInvitation is the resolver for the resendInvitation field.
+func (r *mutationResolver) ResendInvitation(ctx context.Context, input types.ResendInvitationInput) (*types.ResendInvitationPayload, error) {
+	r.GetTenantServiceIfPermitted(ctx, input.InvitationID.TenantID(), usrmgr.PermissionManageMembers)
+
+	invitation, err := r.usrmgrSvc.ResendInvitation(ctx, input.InvitationID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot resend invitation: %w", err)
+	}
+
+	return &types.ResendInvitationPayload{
+		Invitation: types.NewInvitation(invitation),
+	}, nil
+}
+
+// RevokeInvitation is the resolver for the revokeInvitation field.
+func (r *mutationResolver) RevokeInvitation(ctx context.Context, input types.RevokeInvitationInput) (*types.RevokeInvitationPayload, error) {
+	r.GetTenantServiceIfPermitted(ctx, input.InvitationID.TenantID(), usrmgr.PermissionManageMembers)
+
+	invitation, err := r.usrmgrSvc.RevokeInvitation(ctx, input.InvitationID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot revoke invitation: %w", err)
 	}
 
-	return nil, fmt.Errorf("organization not found")
+	return &types.RevokeInvitationPayload{
+		Invitation: types.NewInvitation(invitation),
+	}, nil
 }
 
 // RemoveUser is the resolver for the removeUser field.
 func (r *mutationResolver) RemoveUser(ctx context.Context, input types.RemoveUserInput) (*types.RemoveUserPayload, error) {
+	r.GetTenantServiceIfPermitted(ctx, input.OrganizationID.TenantID(), usrmgr.PermissionManageMembers)
+
+	revokeSessions := input.RevokeSessions != nil && *input.RevokeSessions
+
+	err := r.usrmgrSvc.RemoveUser(ctx, UserFromContext(ctx).ID, input.OrganizationID, input.UserID, revokeSessions)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.RemoveUserPayload{Success: true}, nil
+}
+
+// EnrollTotp is the resolver for the enrollTotp field.
+func (r *mutationResolver) EnrollTotp(ctx context.Context) (*types.EnrollTotpPayload, error) {
+	if APITokenFromContext(ctx) != nil {
+		return nil, fmt.Errorf("totp cannot be enrolled with an api token")
+	}
+
 	user := UserFromContext(ctx)
 
-	organizations, err := r.usrmgrSvc.ListOrganizationsForUserID(ctx, user.ID)
+	enrollment, err := r.usrmgrSvc.EnrollTOTP(ctx, user.ID)
 	if err != nil {
-		panic(fmt.Errorf("failed to list organizations for user: %w", err))
+		return nil, fmt.Errorf("cannot enroll totp: %w", err)
 	}
 
-	for _, organization := range organizations {
-		if organization.ID == input.OrganizationID {
-			err := r.usrmgrSvc.RemoveUser(ctx, input.OrganizationID, input.UserID)
-			if err != nil {
-				return nil, err
-			}
+	return &types.EnrollTotpPayload{
+		Secret: enrollment.Secret,
+		URI:    enrollment.URI,
+	}, nil
+}
 
-			return &types.RemoveUserPayload{Success: true}, nil
-		}
+// ConfirmTotp is the resolver for the confirmTotp field.
+func (r *mutationResolver) ConfirmTotp(ctx context.Context, input types.ConfirmTotpInput) (*types.ConfirmTotpPayload, error) {
+	if APITokenFromContext(ctx) != nil {
+		return nil, fmt.Errorf("totp cannot be confirmed with an api token")
+	}
+
+	user := UserFromContext(ctx)
+
+	recoveryCodes, err := r.usrmgrSvc.ConfirmTOTP(ctx, user.ID, input.Code)
+	if err != nil {
+		return nil, fmt.Errorf("cannot confirm totp: %w", err)
+	}
+
+	return &types.ConfirmTotpPayload{
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+// DisableTotp is the resolver for the disableTotp field.
+func (r *mutationResolver) DisableTotp(ctx context.Context, input types.DisableTotpInput) (*types.DisableTotpPayload, error) {
+	if APITokenFromContext(ctx) != nil {
+		return nil, fmt.Errorf("totp cannot be disabled with an api token")
+	}
+
+	user := UserFromContext(ctx)
+
+	if err := r.usrmgrSvc.DisableTOTP(ctx, user.ID, input.Code); err != nil {
+		return nil, fmt.Errorf("cannot disable totp: %w", err)
+	}
+
+	return &types.DisableTotpPayload{Success: true}, nil
+}
+
+// RegenerateRecoveryCodes is the resolver for the regenerateRecoveryCodes field.
+func (r *mutationResolver) RegenerateRecoveryCodes(ctx context.Context, input types.RegenerateRecoveryCodesInput) (*types.RegenerateRecoveryCodesPayload, error) {
+	if APITokenFromContext(ctx) != nil {
+		return nil, fmt.Errorf("recovery codes cannot be regenerated with an api token")
+	}
+
+	user := UserFromContext(ctx)
+
+	recoveryCodes, err := r.usrmgrSvc.RegenerateRecoveryCodes(ctx, user.ID, input.Code)
+	if err != nil {
+		return nil, fmt.Errorf("cannot regenerate recovery codes: %w", err)
+	}
+
+	return &types.RegenerateRecoveryCodesPayload{
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+// RenameWebAuthnCredential is the resolver for the renameWebAuthnCredential field.
+func (r *mutationResolver) RenameWebAuthnCredential(ctx context.Context, input types.RenameWebAuthnCredentialInput) (*types.RenameWebAuthnCredentialPayload, error) {
+	if APITokenFromContext(ctx) != nil {
+		return nil, fmt.Errorf("webauthn credentials cannot be renamed with an api token")
+	}
+
+	user := UserFromContext(ctx)
+
+	credential, err := r.usrmgrSvc.RenameWebAuthnCredential(ctx, user.ID, input.WebAuthnCredentialID, input.Name)
+	if err != nil {
+		return nil, fmt.Errorf("cannot rename webauthn credential: %w", err)
+	}
+
+	return &types.RenameWebAuthnCredentialPayload{
+		WebAuthnCredential: types.NewWebAuthnCredential(credential),
+	}, nil
+}
+
+// DeleteWebAuthnCredential is the resolver for the deleteWebAuthnCredential field.
+func (r *mutationResolver) DeleteWebAuthnCredential(ctx context.Context, input types.DeleteWebAuthnCredentialInput) (*types.DeleteWebAuthnCredentialPayload, error) {
+	if APITokenFromContext(ctx) != nil {
+		return nil, fmt.Errorf("webauthn credentials cannot be deleted with an api token")
+	}
+
+	user := UserFromContext(ctx)
+
+	if err := r.usrmgrSvc.DeleteWebAuthnCredential(ctx, user.ID, input.WebAuthnCredentialID); err != nil {
+		return nil, fmt.Errorf("cannot delete webauthn credential: %w", err)
 	}
 
-	return nil, fmt.Errorf("organization not found")
+	return &types.DeleteWebAuthnCredentialPayload{
+		DeletedWebAuthnCredentialID: input.WebAuthnCredentialID,
+	}, nil
 }
 
 // LogoURL is the resolver for the logoUrl field.
@@ -710,6 +1508,168 @@ func (r *organizationResolver) Policies(ctx context.Context, obj *types.Organiza
 	return types.NewPolicyConnection(page), nil
 }
 
+// OidcConfiguration is the resolver for the oidcConfiguration field.
+func (r *organizationResolver) OidcConfiguration(ctx context.Context, obj *types.Organization) (*types.OidcConfiguration, error) {
+	svc := r.GetTenantServiceIfAuthorized(ctx, obj.ID.TenantID())
+
+	configuration, err := svc.OIDC.Get(ctx, obj.ID)
+	if err != nil {
+		var errNotFound *coredata.ErrOIDCConfigurationNotFound
+		if errors.As(err, &errNotFound) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("cannot load oidc configuration: %w", err)
+	}
+
+	return types.NewOidcConfiguration(configuration, r.usrmgrSvc.OIDCRedirectURL()), nil
+}
+
+// SamlConfiguration is the resolver for the samlConfiguration field.
+func (r *organizationResolver) SamlConfiguration(ctx context.Context, obj *types.Organization) (*types.SamlConfiguration, error) {
+	svc := r.GetTenantServiceIfAuthorized(ctx, obj.ID.TenantID())
+
+	configuration, err := svc.SAML.Get(ctx, obj.ID)
+	if err != nil {
+		var errNotFound *coredata.ErrSAMLConfigurationNotFound
+		if errors.As(err, &errNotFound) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("cannot load saml configuration: %w", err)
+	}
+
+	return types.NewSamlConfiguration(
+		configuration,
+		r.usrmgrSvc.SAMLMetadataURL(obj.ID),
+		r.usrmgrSvc.SAMLConsumerURL(obj.ID),
+	), nil
+}
+
+// ScimConfiguration is the resolver for the scimConfiguration field.
+func (r *organizationResolver) ScimConfiguration(ctx context.Context, obj *types.Organization) (*types.ScimConfiguration, error) {
+	r.GetTenantServiceIfAuthorized(ctx, obj.ID.TenantID())
+
+	configuration, err := r.usrmgrSvc.GetSCIMConfiguration(ctx, obj.ID)
+	if err != nil {
+		var errNotFound *coredata.ErrSCIMConfigurationNotFound
+		if errors.As(err, &errNotFound) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("cannot load scim configuration: %w", err)
+	}
+
+	return types.NewScimConfiguration(configuration, r.usrmgrSvc.SCIMBaseURL()), nil
+}
+
+// APITokens is the resolver for the apiTokens field.
+func (r *organizationResolver) APITokens(ctx context.Context, obj *types.Organization) ([]*types.APIToken, error) {
+	r.GetTenantServiceIfAuthorized(ctx, obj.ID.TenantID())
+
+	apiTokens, err := r.usrmgrSvc.ListAPITokens(ctx, obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list api tokens: %w", err)
+	}
+
+	// Members only see their own tokens unless they manage the
+	// organization.
+	if !r.HasPermission(ctx, obj.ID.TenantID(), usrmgr.PermissionManageOrganization) {
+		userID := UserFromContext(ctx).ID
+		apiTokens = slices.DeleteFunc(apiTokens, func(t *coredata.APIToken) bool { return t.UserID != userID })
+	}
+
+	return types.NewAPITokens(apiTokens), nil
+}
+
+// Memberships is the resolver for the memberships field.
+func (r *organizationResolver) Memberships(ctx context.Context, obj *types.Organization) ([]*types.Membership, error) {
+	r.GetTenantServiceIfAuthorized(ctx, obj.ID.TenantID())
+
+	memberships, err := r.usrmgrSvc.ListMemberships(ctx, obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list memberships: %w", err)
+	}
+
+	result := make([]*types.Membership, len(memberships))
+	for i, membership := range memberships {
+		user, err := r.usrmgrSvc.GetUserByID(ctx, membership.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load user: %w", err)
+		}
+
+		result[i] = types.NewMembership(membership, user)
+	}
+
+	return result, nil
+}
+
+// Invitations is the resolver for the invitations field.
+func (r *organizationResolver) Invitations(ctx context.Context, obj *types.Organization) ([]*types.Invitation, error) {
+	r.GetTenantServiceIfAuthorized(ctx, obj.ID.TenantID())
+
+	if !r.HasPermission(ctx, obj.ID.TenantID(), usrmgr.PermissionManageMembers) {
+		return nil, fmt.Errorf("not allowed to list invitations")
+	}
+
+	invitations, err := r.usrmgrSvc.ListInvitations(ctx, obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list invitations: %w", err)
+	}
+
+	return types.NewInvitations(invitations), nil
+}
+
+// StandardMappings is the resolver for the standardMappings field.
+func (r *organizationResolver) StandardMappings(ctx context.Context, obj *types.Organization) ([]*types.StandardMapping, error) {
+	svc := r.GetTenantServiceIfAuthorized(ctx, obj.ID.TenantID())
+
+	standardMappings, err := svc.StandardMappings.ListForOrganizationID(ctx, obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list standard mappings: %w", err)
+	}
+
+	return types.NewStandardMappings(standardMappings), nil
+}
+
+// EmailDomains is the resolver for the emailDomains field.
+func (r *organizationResolver) EmailDomains(ctx context.Context, obj *types.Organization) ([]*types.EmailDomain, error) {
+	svc := r.GetTenantServiceIfAuthorized(ctx, obj.ID.TenantID())
+
+	emailDomains, err := svc.EmailDomains.ListForOrganizationID(ctx, obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list email domains: %w", err)
+	}
+
+	return types.NewEmailDomains(emailDomains), nil
+}
+
+// Readiness is the resolver for the readiness field.
+func (r *organizationResolver) Readiness(ctx context.Context, obj *types.Organization) (*types.Readiness, error) {
+	svc := r.GetTenantServiceIfAuthorized(ctx, obj.ID.TenantID())
+
+	readiness, err := svc.Organizations.GetReadiness(ctx, obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load organization readiness: %w", err)
+	}
+
+	return types.NewReadiness(readiness), nil
+}
+
+// ViewerRole is the resolver for the viewerRole field.
+func (r *organizationResolver) ViewerRole(ctx context.Context, obj *types.Organization) (coredata.MembershipRole, error) {
+	r.GetTenantServiceIfAuthorized(ctx, obj.ID.TenantID())
+
+	tenantRoles, _ := ctx.Value(userTenantRoleContextKey).(map[gid.TenantID]coredata.MembershipRole)
+
+	role, ok := tenantRoles[obj.ID.TenantID()]
+	if !ok {
+		return 0, fmt.Errorf("cannot find role in organization")
+	}
+
+	return role, nil
+}
+
 // Owner is the resolver for the owner field.
 func (r *policyResolver) Owner(ctx context.Context, obj *types.Policy) (*types.People, error) {
 	svc := r.GetTenantServiceIfAuthorized(ctx, obj.ID.TenantID())
@@ -797,14 +1757,63 @@ func (r *queryResolver) Node(ctx context.Context, id gid.GID) (types.Node, error
 // Viewer is the resolver for the viewer field.
 func (r *queryResolver) Viewer(ctx context.Context) (*types.Viewer, error) {
 	user := UserFromContext(ctx)
-	session := SessionFromContext(ctx)
+	var viewerID gid.GID
+	if session := SessionFromContext(ctx); session != nil {
+		viewerID = session.ID
+	} else if apiToken := APITokenFromContext(ctx); apiToken != nil {
+		viewerID = apiToken.ID
+	}
 
 	return &types.Viewer{
-		ID:   session.ID,
+		ID:   viewerID,
 		User: types.NewUser(user),
 	}, nil
 }
 
+// FrameworkTemplates is the resolver for the frameworkTemplates field.
+func (r *queryResolver) FrameworkTemplates(ctx context.Context) ([]*types.FrameworkTemplate, error) {
+	templates, err := probo.ListFrameworkTemplates()
+	if err != nil {
+		return nil, fmt.Errorf("cannot list framework templates: %w", err)
+	}
+
+	return types.NewFrameworkTemplates(templates), nil
+}
+
+// SourceFramework is the resolver for the sourceFramework field.
+func (r *standardMappingResolver) SourceFramework(ctx context.Context, obj *types.StandardMapping) (*types.Framework, error) {
+	svc := r.GetTenantServiceIfAuthorized(ctx, obj.ID.TenantID())
+
+	standardMapping, err := svc.StandardMappings.Get(ctx, obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load standard mapping: %w", err)
+	}
+
+	framework, err := svc.Frameworks.Get(ctx, standardMapping.SourceFrameworkID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load source framework: %w", err)
+	}
+
+	return types.NewFramework(framework), nil
+}
+
+// TargetFramework is the resolver for the targetFramework field.
+func (r *standardMappingResolver) TargetFramework(ctx context.Context, obj *types.StandardMapping) (*types.Framework, error) {
+	svc := r.GetTenantServiceIfAuthorized(ctx, obj.ID.TenantID())
+
+	standardMapping, err := svc.StandardMappings.Get(ctx, obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load standard mapping: %w", err)
+	}
+
+	framework, err := svc.Frameworks.Get(ctx, standardMapping.TargetFrameworkID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load target framework: %w", err)
+	}
+
+	return types.NewFramework(framework), nil
+}
+
 // AssignedTo is the resolver for the assignedTo field.
 func (r *taskResolver) AssignedTo(ctx context.Context, obj *types.Task) (*types.People, error) {
 	svc := r.GetTenantServiceIfAuthorized(ctx, obj.ID.TenantID())
@@ -850,6 +1859,41 @@ func (r *taskResolver) Evidences(ctx context.Context, obj *types.Task, first *in
 	return types.NewEvidenceConnection(page), nil
 }
 
+// StateTransitions is the resolver for the stateTransitions field.
+func (r *taskResolver) StateTransitions(ctx context.Context, obj *types.Task, first *int, after *page.CursorKey, last *int, before *page.CursorKey, orderBy *types.StateTransitionOrderBy) (*types.TaskStateTransitionConnection, error) {
+	svc := r.GetTenantServiceIfAuthorized(ctx, obj.ID.TenantID())
+
+	pageOrderBy := page.OrderBy[coredata.StateTransitionOrderField]{
+		Field:     coredata.StateTransitionOrderFieldCreatedAt,
+		Direction: page.OrderDirectionDesc,
+	}
+	if orderBy != nil {
+		pageOrderBy = page.OrderBy[coredata.StateTransitionOrderField]{
+			Field:     orderBy.Field,
+			Direction: orderBy.Direction,
+		}
+	}
+
+	cursor := types.NewCursor(first, after, last, before, pageOrderBy)
+
+	page, err := svc.Tasks.ListStateTransitions(ctx, obj.ID, cursor)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list task state transitions: %w", err)
+	}
+
+	userIDs := make([]*gid.GID, len(page.Data))
+	for i, transition := range page.Data {
+		userIDs[i] = transition.ChangedByID
+	}
+
+	users, err := r.loadUsers(ctx, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load state transition users: %w", err)
+	}
+
+	return types.NewTaskStateTransitionConnection(page, users), nil
+}
+
 // Organizations is the resolver for the organizations field.
 func (r *viewerResolver) Organizations(ctx context.Context, obj *types.Viewer, first *int, after *page.CursorKey, last *int, before *page.CursorKey, orderBy *types.OrganizationOrder) (*types.OrganizationConnection, error) {
 	user := UserFromContext(ctx)
@@ -875,6 +1919,37 @@ func (r *viewerResolver) Organizations(ctx context.Context, obj *types.Viewer, f
 	}, nil
 }
 
+// WebAuthnCredentials is the resolver for the webAuthnCredentials field.
+func (r *viewerResolver) WebAuthnCredentials(ctx context.Context, obj *types.Viewer) ([]*types.WebAuthnCredential, error) {
+	user := UserFromContext(ctx)
+
+	credentials, err := r.usrmgrSvc.ListWebAuthnCredentials(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list webauthn credentials: %w", err)
+	}
+
+	return types.NewWebAuthnCredentials(credentials), nil
+}
+
+// Sessions is the resolver for the sessions field.
+func (r *viewerResolver) Sessions(ctx context.Context, obj *types.Viewer) ([]*types.Session, error) {
+	if APITokenFromContext(ctx) != nil {
+		return nil, fmt.Errorf("sessions cannot be listed with an api token")
+	}
+
+	session := SessionFromContext(ctx)
+
+	sessions, err := r.usrmgrSvc.ListSessions(ctx, UserFromContext(ctx).ID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list sessions: %w", err)
+	}
+
+	return types.NewSessions(sessions, session.ID), nil
+}
+
+// ApiToken returns schema.ApiTokenResolver implementation.
+func (r *Resolver) ApiToken() schema.ApiTokenResolver { return &apiTokenResolver{r} }
+
 // Control returns schema.ControlResolver implementation.
 func (r *Resolver) Control() schema.ControlResolver { return &controlResolver{r} }
 
@@ -896,12 +1971,18 @@ func (r *Resolver) Policy() schema.PolicyResolver { return &policyResolver{r} }
 // Query returns schema.QueryResolver implementation.
 func (r *Resolver) Query() schema.QueryResolver { return &queryResolver{r} }
 
+// StandardMapping returns schema.StandardMappingResolver implementation.
+func (r *Resolver) StandardMapping() schema.StandardMappingResolver {
+	return &standardMappingResolver{r}
+}
+
 // Task returns schema.TaskResolver implementation.
 func (r *Resolver) Task() schema.TaskResolver { return &taskResolver{r} }
 
 // Viewer returns schema.ViewerResolver implementation.
 func (r *Resolver) Viewer() schema.ViewerResolver { return &viewerResolver{r} }
 
+type apiTokenResolver struct{ *Resolver }
 type controlResolver struct{ *Resolver }
 type evidenceResolver struct{ *Resolver }
 type frameworkResolver struct{ *Resolver }
@@ -909,5 +1990,6 @@ type mutationResolver struct{ *Resolver }
 type organizationResolver struct{ *Resolver }
 type policyResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type standardMappingResolver struct{ *Resolver }
 type taskResolver struct{ *Resolver }
 type viewerResolver struct{ *Resolver }
@@ -0,0 +1,108 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package console_v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/getprobo/probo/pkg/securecookie"
+	"github.com/getprobo/probo/pkg/usrmgr"
+	"github.com/go-webauthn/webauthn/protocol"
+	"go.gearno.de/kit/httpserver"
+)
+
+type (
+	WebAuthnLoginBeginResponse struct {
+		Token   string                        `json:"token"`
+		Options *protocol.CredentialAssertion `json:"options"`
+	}
+
+	WebAuthnLoginFinishRequest struct {
+		Token      string          `json:"token"`
+		Credential json.RawMessage `json:"credential"`
+	}
+)
+
+func WebAuthnLoginBeginHandler(usrmgrSvc *usrmgr.Service, authCfg AuthConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		options, token, err := usrmgrSvc.BeginWebAuthnLogin(r.Context())
+		if err != nil {
+			panic(fmt.Errorf("cannot begin webauthn login: %w", err))
+		}
+
+		httpserver.RenderJSON(
+			w,
+			http.StatusOK,
+			WebAuthnLoginBeginResponse{
+				Token:   token,
+				Options: options,
+			},
+		)
+	}
+}
+
+func WebAuthnLoginFinishHandler(usrmgrSvc *usrmgr.Service, authCfg AuthConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req WebAuthnLoginFinishRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httpserver.RenderError(w, http.StatusBadRequest, fmt.Errorf("cannot decode body: %w", err))
+			return
+		}
+
+		response, err := protocol.ParseCredentialRequestResponseBody(bytes.NewReader(req.Credential))
+		if err != nil {
+			httpserver.RenderError(w, http.StatusBadRequest, fmt.Errorf("cannot parse credential: %w", err))
+			return
+		}
+
+		user, session, err := usrmgrSvc.FinishWebAuthnLogin(r.Context(), req.Token, response)
+		if err != nil {
+			var errInvalidCredentials *usrmgr.ErrInvalidCredentials
+			if errors.As(err, &errInvalidCredentials) {
+				httpserver.RenderError(w, http.StatusUnauthorized, err)
+				return
+			}
+
+			panic(fmt.Errorf("cannot finish webauthn login: %w", err))
+		}
+
+		securecookie.Set(
+			w,
+			securecookie.DefaultConfig(
+				authCfg.CookieName,
+				authCfg.CookieKeyring,
+			),
+			session.ID.String(),
+		)
+
+		httpserver.RenderJSON(
+			w,
+			http.StatusOK,
+			SignInResponse{
+				User: UserResponse{
+					ID:        user.ID,
+					Email:     user.EmailAddress,
+					FullName:  user.FullName,
+					CreatedAt: user.CreatedAt,
+					UpdatedAt: user.UpdatedAt,
+				},
+			},
+		)
+	}
+}
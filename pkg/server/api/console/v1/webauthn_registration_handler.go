@@ -0,0 +1,151 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package console_v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/getprobo/probo/pkg/coredata"
+	"github.com/getprobo/probo/pkg/gid"
+	"github.com/getprobo/probo/pkg/securecookie"
+	"github.com/getprobo/probo/pkg/usrmgr"
+	"github.com/go-webauthn/webauthn/protocol"
+	"go.gearno.de/kit/httpserver"
+)
+
+type (
+	WebAuthnRegistrationBeginResponse struct {
+		Token   string                       `json:"token"`
+		Options *protocol.CredentialCreation `json:"options"`
+	}
+
+	WebAuthnRegistrationFinishRequest struct {
+		Token      string          `json:"token"`
+		Name       string          `json:"name"`
+		Credential json.RawMessage `json:"credential"`
+	}
+
+	WebAuthnCredentialResponse struct {
+		ID        gid.GID   `json:"id"`
+		Name      string    `json:"name"`
+		CreatedAt time.Time `json:"createdAt"`
+	}
+)
+
+func WebAuthnRegistrationBeginHandler(usrmgrSvc *usrmgr.Service, authCfg AuthConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authenticatedUser(r, usrmgrSvc, authCfg)
+		if err != nil {
+			httpserver.RenderError(w, http.StatusUnauthorized, err)
+			return
+		}
+
+		options, token, err := usrmgrSvc.BeginWebAuthnRegistration(r.Context(), user.ID)
+		if err != nil {
+			panic(fmt.Errorf("cannot begin webauthn registration: %w", err))
+		}
+
+		httpserver.RenderJSON(
+			w,
+			http.StatusOK,
+			WebAuthnRegistrationBeginResponse{
+				Token:   token,
+				Options: options,
+			},
+		)
+	}
+}
+
+func WebAuthnRegistrationFinishHandler(usrmgrSvc *usrmgr.Service, authCfg AuthConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authenticatedUser(r, usrmgrSvc, authCfg)
+		if err != nil {
+			httpserver.RenderError(w, http.StatusUnauthorized, err)
+			return
+		}
+
+		var req WebAuthnRegistrationFinishRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httpserver.RenderError(w, http.StatusBadRequest, fmt.Errorf("cannot decode body: %w", err))
+			return
+		}
+
+		response, err := protocol.ParseCredentialCreationResponseBody(bytes.NewReader(req.Credential))
+		if err != nil {
+			httpserver.RenderError(w, http.StatusBadRequest, fmt.Errorf("cannot parse credential: %w", err))
+			return
+		}
+
+		credential, err := usrmgrSvc.FinishWebAuthnRegistration(
+			r.Context(),
+			user.ID,
+			req.Token,
+			req.Name,
+			response,
+		)
+		if err != nil {
+			var errInvalidWebAuthnResponse *usrmgr.ErrInvalidWebAuthnResponse
+			if errors.As(err, &errInvalidWebAuthnResponse) {
+				httpserver.RenderError(w, http.StatusBadRequest, err)
+				return
+			}
+
+			var errInvalidWebAuthnCredentialName *usrmgr.ErrInvalidWebAuthnCredentialName
+			if errors.As(err, &errInvalidWebAuthnCredentialName) {
+				httpserver.RenderError(w, http.StatusBadRequest, err)
+				return
+			}
+
+			panic(fmt.Errorf("cannot finish webauthn registration: %w", err))
+		}
+
+		httpserver.RenderJSON(
+			w,
+			http.StatusOK,
+			WebAuthnCredentialResponse{
+				ID:        credential.ID,
+				Name:      credential.Name,
+				CreatedAt: credential.CreatedAt,
+			},
+		)
+	}
+}
+
+func authenticatedUser(r *http.Request, usrmgrSvc *usrmgr.Service, authCfg AuthConfig) (*coredata.User, error) {
+	cookieValue, err := securecookie.Get(r, securecookie.DefaultConfig(
+		authCfg.CookieName,
+		authCfg.CookieKeyring,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("authentication required")
+	}
+
+	sessionID, err := gid.ParseGID(cookieValue)
+	if err != nil {
+		return nil, fmt.Errorf("authentication required")
+	}
+
+	user, err := usrmgrSvc.GetUserBySession(r.Context(), sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("authentication required")
+	}
+
+	return user, nil
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package scim_v2
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+type (
+	// filter is an equality comparison, the only filter identity
+	// providers use to look up resources before provisioning them.
+	filter struct {
+		attribute string
+		value     string
+	}
+)
+
+// parseFilter parses a filter of the form `attribute eq "value"`, the
+// attribute name is case-insensitive as per RFC 7643 section 2.1.
+func parseFilter(expression string, attributes ...string) (*filter, error) {
+	expression = strings.TrimSpace(expression)
+	if expression == "" {
+		return nil, nil
+	}
+
+	parts := strings.SplitN(expression, " ", 3)
+	if len(parts) != 3 || !strings.EqualFold(parts[1], "eq") {
+		return nil, &errInvalidRequest{
+			scimType: "invalidFilter",
+			message:  fmt.Sprintf("unsupported filter %q: only the eq operator is supported", expression),
+		}
+	}
+
+	var value string
+	if err := json.Unmarshal([]byte(strings.TrimSpace(parts[2])), &value); err != nil {
+		return nil, &errInvalidRequest{
+			scimType: "invalidFilter",
+			message:  fmt.Sprintf("invalid filter value %q: a string is expected", parts[2]),
+		}
+	}
+
+	for _, attribute := range attributes {
+		if strings.EqualFold(parts[0], attribute) {
+			return &filter{attribute: attribute, value: value}, nil
+		}
+	}
+
+	return nil, &errInvalidRequest{
+		scimType: "invalidFilter",
+		message:  fmt.Sprintf("unsupported filter attribute %q", parts[0]),
+	}
+}
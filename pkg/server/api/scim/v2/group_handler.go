@@ -0,0 +1,389 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package scim_v2
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/getprobo/probo/pkg/coredata"
+	"github.com/getprobo/probo/pkg/gid"
+	"github.com/getprobo/probo/pkg/usrmgr"
+)
+
+type (
+	groupResource struct {
+		Schemas     []string      `json:"schemas"`
+		ID          string        `json:"id,omitempty"`
+		ExternalID  *string       `json:"externalId,omitempty"`
+		DisplayName string        `json:"displayName"`
+		Members     []groupMember `json:"members"`
+		Meta        *meta         `json:"meta,omitempty"`
+	}
+
+	groupMember struct {
+		Value string `json:"value"`
+		Ref   string `json:"$ref,omitempty"`
+	}
+)
+
+func newGroupResource(baseURL string, g *usrmgr.ProvisionedGroup) *groupResource {
+	members := make([]groupMember, 0, len(g.MemberIDs))
+	for _, memberID := range g.MemberIDs {
+		members = append(
+			members,
+			groupMember{
+				Value: memberID.String(),
+				Ref:   baseURL + "/Users/" + memberID.String(),
+			},
+		)
+	}
+
+	return &groupResource{
+		Schemas:     []string{groupSchema},
+		ID:          g.SCIMGroup.ID.String(),
+		ExternalID:  g.SCIMGroup.ExternalID,
+		DisplayName: g.SCIMGroup.DisplayName,
+		Members:     members,
+		Meta: &meta{
+			ResourceType: "Group",
+			Created:      g.SCIMGroup.CreatedAt,
+			LastModified: g.SCIMGroup.UpdatedAt,
+			Location:     baseURL + "/Groups/" + g.SCIMGroup.ID.String(),
+		},
+	}
+}
+
+func (g groupResource) request() (usrmgr.SCIMGroupRequest, error) {
+	memberIDs := make([]gid.GID, 0, len(g.Members))
+	for _, member := range g.Members {
+		memberID, err := gid.ParseGID(member.Value)
+		if err != nil {
+			return usrmgr.SCIMGroupRequest{}, &errInvalidRequest{
+				scimType: "invalidValue",
+				message:  fmt.Sprintf("invalid member %q", member.Value),
+			}
+		}
+
+		memberIDs = append(memberIDs, memberID)
+	}
+
+	return usrmgr.SCIMGroupRequest{
+		DisplayName: g.DisplayName,
+		ExternalID:  g.ExternalID,
+		MemberIDs:   memberIDs,
+	}, nil
+}
+
+// apply applies a patch operation to the resource, members are either
+// patched as a whole or filtered by value as in `members[value eq "id"]`.
+func (g *groupResource) apply(op string, path string, value json.RawMessage) error {
+	if path == "" {
+		attributes, err := patchOperation{Value: value}.valueAttributes()
+		if err != nil {
+			return err
+		}
+
+		for attribute, value := range attributes {
+			if err := g.apply(op, attribute, value); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	lowerPath := strings.ToLower(path)
+
+	if strings.HasPrefix(lowerPath, "members[") && strings.HasSuffix(lowerPath, "]") {
+		if op != "remove" {
+			return &errInvalidRequest{scimType: "invalidPath", message: fmt.Sprintf("unsupported path %q", path)}
+		}
+
+		f, err := parseFilter(path[len("members["):len(path)-1], "value")
+		if err != nil || f == nil {
+			return &errInvalidRequest{scimType: "invalidPath", message: fmt.Sprintf("unsupported path %q", path)}
+		}
+
+		g.Members = slices.DeleteFunc(g.Members, func(m groupMember) bool { return m.Value == f.value })
+
+		return nil
+	}
+
+	switch lowerPath {
+	case "id", "schemas":
+		return nil
+	case "displayname":
+		if op == "remove" {
+			return &errInvalidRequest{scimType: "mutability", message: "displayName cannot be removed"}
+		}
+
+		displayName, err := decodeString(path, value)
+		if err != nil {
+			return err
+		}
+		g.DisplayName = displayName
+	case "externalid":
+		if op == "remove" {
+			g.ExternalID = nil
+			return nil
+		}
+
+		externalID, err := decodeString(path, value)
+		if err != nil {
+			return err
+		}
+		g.ExternalID = &externalID
+	case "members":
+		var members []groupMember
+		if len(value) > 0 {
+			if err := json.Unmarshal(value, &members); err != nil {
+				return &errInvalidRequest{scimType: "invalidValue", message: "members must be an array"}
+			}
+		}
+
+		switch op {
+		case "add":
+			for _, member := range members {
+				if !slices.ContainsFunc(g.Members, func(m groupMember) bool { return m.Value == member.Value }) {
+					g.Members = append(g.Members, member)
+				}
+			}
+		case "replace":
+			g.Members = members
+		case "remove":
+			if len(members) == 0 {
+				g.Members = nil
+				return nil
+			}
+
+			g.Members = slices.DeleteFunc(
+				g.Members,
+				func(m groupMember) bool {
+					return slices.ContainsFunc(members, func(r groupMember) bool { return r.Value == m.Value })
+				},
+			)
+		}
+	default:
+		return &errInvalidRequest{scimType: "invalidPath", message: fmt.Sprintf("unsupported attribute %q", path)}
+	}
+
+	return nil
+}
+
+func ListGroupsHandler(usrmgrSvc *usrmgr.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		configuration := configurationFromContext(ctx)
+
+		startIndex, count, err := pagination(r)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+
+		f, err := parseFilter(r.URL.Query().Get("filter"), "displayName", "externalId")
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+
+		groupFilter := coredata.SCIMGroupFilter{}
+		if f != nil {
+			switch f.attribute {
+			case "displayName":
+				groupFilter.DisplayName = &f.value
+			case "externalId":
+				groupFilter.ExternalID = &f.value
+			}
+		}
+
+		groups, total, err := usrmgrSvc.ListSCIMGroups(ctx, configuration, groupFilter, startIndex-1, count)
+		if err != nil {
+			panic(fmt.Errorf("cannot list scim groups: %w", err))
+		}
+
+		resources := make([]any, 0, len(groups))
+		for _, group := range groups {
+			resources = append(resources, newGroupResource(usrmgrSvc.SCIMBaseURL(), group))
+		}
+
+		render(
+			w,
+			http.StatusOK,
+			listResponse{
+				Schemas:      []string{listResponseSchema},
+				TotalResults: total,
+				StartIndex:   startIndex,
+				ItemsPerPage: len(resources),
+				Resources:    resources,
+			},
+		)
+	}
+}
+
+func GetGroupHandler(usrmgrSvc *usrmgr.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		id, ok := resourceID(r)
+		if !ok {
+			renderError(w, http.StatusNotFound, "", "group not found")
+			return
+		}
+
+		group, err := usrmgrSvc.GetSCIMGroup(ctx, configurationFromContext(ctx), id)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+
+		render(w, http.StatusOK, newGroupResource(usrmgrSvc.SCIMBaseURL(), group))
+	}
+}
+
+func CreateGroupHandler(usrmgrSvc *usrmgr.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		var resource groupResource
+		if err := decodeRequest(w, r, &resource); err != nil {
+			handleError(w, err)
+			return
+		}
+
+		req, err := resource.request()
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+
+		group, err := usrmgrSvc.CreateSCIMGroup(ctx, configurationFromContext(ctx), req)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+
+		created := newGroupResource(usrmgrSvc.SCIMBaseURL(), group)
+		w.Header().Set("Location", created.Meta.Location)
+		render(w, http.StatusCreated, created)
+	}
+}
+
+func ReplaceGroupHandler(usrmgrSvc *usrmgr.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		id, ok := resourceID(r)
+		if !ok {
+			renderError(w, http.StatusNotFound, "", "group not found")
+			return
+		}
+
+		var resource groupResource
+		if err := decodeRequest(w, r, &resource); err != nil {
+			handleError(w, err)
+			return
+		}
+
+		req, err := resource.request()
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+
+		group, err := usrmgrSvc.ReplaceSCIMGroup(ctx, configurationFromContext(ctx), id, req)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+
+		render(w, http.StatusOK, newGroupResource(usrmgrSvc.SCIMBaseURL(), group))
+	}
+}
+
+func PatchGroupHandler(usrmgrSvc *usrmgr.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		configuration := configurationFromContext(ctx)
+
+		id, ok := resourceID(r)
+		if !ok {
+			renderError(w, http.StatusNotFound, "", "group not found")
+			return
+		}
+
+		var patch patchRequest
+		if err := decodeRequest(w, r, &patch); err != nil {
+			handleError(w, err)
+			return
+		}
+
+		if err := patch.validate(); err != nil {
+			handleError(w, err)
+			return
+		}
+
+		group, err := usrmgrSvc.GetSCIMGroup(ctx, configuration, id)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+
+		resource := newGroupResource(usrmgrSvc.SCIMBaseURL(), group)
+		for _, operation := range patch.Operations {
+			if err := resource.apply(operation.Op, operation.Path, operation.Value); err != nil {
+				handleError(w, err)
+				return
+			}
+		}
+
+		req, err := resource.request()
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+
+		group, err = usrmgrSvc.ReplaceSCIMGroup(ctx, configuration, id, req)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+
+		render(w, http.StatusOK, newGroupResource(usrmgrSvc.SCIMBaseURL(), group))
+	}
+}
+
+func DeleteGroupHandler(usrmgrSvc *usrmgr.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		id, ok := resourceID(r)
+		if !ok {
+			renderError(w, http.StatusNotFound, "", "group not found")
+			return
+		}
+
+		if err := usrmgrSvc.DeleteSCIMGroup(ctx, configurationFromContext(ctx), id); err != nil {
+			handleError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package scim_v2
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type (
+	patchRequest struct {
+		Schemas    []string         `json:"schemas"`
+		Operations []patchOperation `json:"Operations"`
+	}
+
+	patchOperation struct {
+		Op    string          `json:"op"`
+		Path  string          `json:"path"`
+		Value json.RawMessage `json:"value"`
+	}
+)
+
+// validate checks the operations of the request, the op keyword is
+// matched case-insensitively as some identity providers capitalize it.
+func (p patchRequest) validate() error {
+	if len(p.Operations) == 0 {
+		return &errInvalidRequest{scimType: "invalidSyntax", message: "patch request has no operations"}
+	}
+
+	for i, operation := range p.Operations {
+		op := strings.ToLower(operation.Op)
+		switch op {
+		case "add", "replace":
+			if len(operation.Value) == 0 {
+				return &errInvalidRequest{
+					scimType: "invalidValue",
+					message:  fmt.Sprintf("operation %d has no value", i),
+				}
+			}
+		case "remove":
+			if operation.Path == "" {
+				return &errInvalidRequest{
+					scimType: "noTarget",
+					message:  fmt.Sprintf("remove operation %d has no path", i),
+				}
+			}
+		default:
+			return &errInvalidRequest{
+				scimType: "invalidSyntax",
+				message:  fmt.Sprintf("unsupported patch operation %q", operation.Op),
+			}
+		}
+
+		p.Operations[i].Op = op
+	}
+
+	return nil
+}
+
+// valueAttributes splits the value of an operation without path into the
+// attributes it replaces.
+func (o patchOperation) valueAttributes() (map[string]json.RawMessage, error) {
+	var attributes map[string]json.RawMessage
+	if err := json.Unmarshal(o.Value, &attributes); err != nil {
+		return nil, &errInvalidRequest{scimType: "invalidValue", message: "operation value must be an object when path is empty"}
+	}
+
+	return attributes, nil
+}
+
+func decodeString(path string, value json.RawMessage) (string, error) {
+	var s string
+	if err := json.Unmarshal(value, &s); err != nil {
+		return "", &errInvalidRequest{scimType: "invalidValue", message: fmt.Sprintf("%s must be a string", path)}
+	}
+
+	return s, nil
+}
+
+// decodeBool accepts JSON booleans as well as the "True" and "False"
+// strings sent by some identity providers.
+func decodeBool(path string, value json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(value, &b); err == nil {
+		return b, nil
+	}
+
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b, nil
+		}
+	}
+
+	return false, &errInvalidRequest{scimType: "invalidValue", message: fmt.Sprintf("%s must be a boolean", path)}
+}
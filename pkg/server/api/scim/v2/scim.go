@@ -0,0 +1,325 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+// Package scim_v2 implements the SCIM 2.0 provisioning protocol (RFC 7643
+// and RFC 7644) identity providers use to manage the users and groups of
+// an organization.
+package scim_v2
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/getprobo/probo/pkg/coredata"
+	"github.com/getprobo/probo/pkg/gid"
+	"github.com/getprobo/probo/pkg/usrmgr"
+	"github.com/go-chi/chi/v5"
+)
+
+type (
+	ctxKey struct{ name string }
+
+	errorResponse struct {
+		Schemas  []string `json:"schemas"`
+		Status   string   `json:"status"`
+		SCIMType string   `json:"scimType,omitempty"`
+		Detail   string   `json:"detail,omitempty"`
+	}
+
+	listResponse struct {
+		Schemas      []string `json:"schemas"`
+		TotalResults int      `json:"totalResults"`
+		StartIndex   int      `json:"startIndex"`
+		ItemsPerPage int      `json:"itemsPerPage"`
+		Resources    []any    `json:"Resources"`
+	}
+
+	meta struct {
+		ResourceType string    `json:"resourceType"`
+		Created      time.Time `json:"created"`
+		LastModified time.Time `json:"lastModified"`
+		Location     string    `json:"location"`
+	}
+
+	// errInvalidRequest is returned when the request does not follow the
+	// protocol, scimType is one of the detail error keywords of RFC 7644
+	// section 3.12.
+	errInvalidRequest struct {
+		scimType string
+		message  string
+	}
+)
+
+const (
+	userSchema                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	enterpriseUserSchema        = "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User"
+	groupSchema                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	serviceProviderConfigSchema = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	listResponseSchema          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	patchOpSchema               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	errorSchema                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+
+	maxResults      = 100
+	maxRequestBytes = 1 << 20
+)
+
+var (
+	configurationContextKey = &ctxKey{name: "scim_configuration"}
+)
+
+func (e errInvalidRequest) Error() string {
+	return e.message
+}
+
+func configurationFromContext(ctx context.Context) *coredata.SCIMConfiguration {
+	configuration, _ := ctx.Value(configurationContextKey).(*coredata.SCIMConfiguration)
+	return configuration
+}
+
+func NewMux(usrmgrSvc *usrmgr.Service) *chi.Mux {
+	r := chi.NewMux()
+
+	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		renderError(w, http.StatusNotFound, "", "not found")
+	})
+	r.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		renderError(w, http.StatusMethodNotAllowed, "", "method not allowed")
+	})
+
+	r.Group(func(r chi.Router) {
+		r.Use(authenticate(usrmgrSvc))
+
+		r.Get("/ServiceProviderConfig", ServiceProviderConfigHandler(usrmgrSvc))
+
+		r.Get("/Users", ListUsersHandler(usrmgrSvc))
+		r.Post("/Users", CreateUserHandler(usrmgrSvc))
+		r.Get("/Users/{id}", GetUserHandler(usrmgrSvc))
+		r.Put("/Users/{id}", ReplaceUserHandler(usrmgrSvc))
+		r.Patch("/Users/{id}", PatchUserHandler(usrmgrSvc))
+		r.Delete("/Users/{id}", DeleteUserHandler(usrmgrSvc))
+
+		r.Get("/Groups", ListGroupsHandler(usrmgrSvc))
+		r.Post("/Groups", CreateGroupHandler(usrmgrSvc))
+		r.Get("/Groups/{id}", GetGroupHandler(usrmgrSvc))
+		r.Put("/Groups/{id}", ReplaceGroupHandler(usrmgrSvc))
+		r.Patch("/Groups/{id}", PatchGroupHandler(usrmgrSvc))
+		r.Delete("/Groups/{id}", DeleteGroupHandler(usrmgrSvc))
+	})
+
+	return r
+}
+
+// authenticate resolves the organization from the bearer token issued for
+// its identity provider.
+func authenticate(usrmgrSvc *usrmgr.Service) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !found || token == "" {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="scim"`)
+				renderError(w, http.StatusUnauthorized, "", "authentication required")
+				return
+			}
+
+			configuration, err := usrmgrSvc.AuthenticateSCIMToken(r.Context(), strings.TrimSpace(token))
+			if err != nil {
+				var errInvalidToken *usrmgr.ErrInvalidSCIMToken
+				if errors.As(err, &errInvalidToken) {
+					w.Header().Set("WWW-Authenticate", `Bearer realm="scim", error="invalid_token"`)
+					renderError(w, http.StatusUnauthorized, "", errInvalidToken.Error())
+					return
+				}
+
+				panic(fmt.Errorf("cannot authenticate scim token: %w", err))
+			}
+
+			ctx := context.WithValue(r.Context(), configurationContextKey, configuration)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func render(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		panic(fmt.Errorf("cannot encode scim response: %w", err))
+	}
+}
+
+func renderError(w http.ResponseWriter, status int, scimType string, detail string) {
+	render(
+		w,
+		status,
+		errorResponse{
+			Schemas:  []string{errorSchema},
+			Status:   strconv.Itoa(status),
+			SCIMType: scimType,
+			Detail:   detail,
+		},
+	)
+}
+
+// handleError renders the errors a client can act upon and panics on
+// the others like the other API handlers.
+func handleError(w http.ResponseWriter, err error) {
+	var (
+		errInvalidRequest         *errInvalidRequest
+		errSCIMUserNotFound       *coredata.ErrSCIMUserNotFound
+		errSCIMGroupNotFound      *coredata.ErrSCIMGroupNotFound
+		errSCIMUserAlreadyExists  *usrmgr.ErrSCIMUserAlreadyExists
+		errSCIMGroupAlreadyExists *usrmgr.ErrSCIMGroupAlreadyExists
+		errInvalidEmail           *usrmgr.ErrInvalidEmail
+		errInvalidDisplayName     *usrmgr.ErrInvalidDisplayName
+		errLastOwner              *usrmgr.ErrLastOwner
+		errNotProvisionable       *usrmgr.ErrSCIMUserNotProvisionable
+	)
+
+	switch {
+	case errors.As(err, &errInvalidRequest):
+		renderError(w, http.StatusBadRequest, errInvalidRequest.scimType, errInvalidRequest.Error())
+	case errors.As(err, &errSCIMUserNotFound):
+		renderError(w, http.StatusNotFound, "", errSCIMUserNotFound.Error())
+	case errors.As(err, &errSCIMGroupNotFound):
+		renderError(w, http.StatusNotFound, "", errSCIMGroupNotFound.Error())
+	case errors.As(err, &errSCIMUserAlreadyExists):
+		renderError(w, http.StatusConflict, "uniqueness", errSCIMUserAlreadyExists.Error())
+	case errors.As(err, &errSCIMGroupAlreadyExists):
+		renderError(w, http.StatusConflict, "uniqueness", errSCIMGroupAlreadyExists.Error())
+	case errors.As(err, &errInvalidEmail):
+		renderError(w, http.StatusBadRequest, "invalidValue", errInvalidEmail.Error())
+	case errors.As(err, &errInvalidDisplayName):
+		renderError(w, http.StatusBadRequest, "invalidValue", errInvalidDisplayName.Error())
+	case errors.As(err, &errLastOwner):
+		renderError(w, http.StatusConflict, "", errLastOwner.Error())
+	case errors.As(err, &errNotProvisionable):
+		renderError(w, http.StatusForbidden, "", errNotProvisionable.Error())
+	default:
+		panic(err)
+	}
+}
+
+func decodeRequest(w http.ResponseWriter, r *http.Request, v any) error {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes))
+	if err := dec.Decode(v); err != nil {
+		return &errInvalidRequest{scimType: "invalidSyntax", message: fmt.Sprintf("cannot decode request: %s", err)}
+	}
+
+	return nil
+}
+
+// resourceID parses the id of the resource in the request path, an
+// unknown id is reported as a missing resource.
+func resourceID(r *http.Request) (gid.GID, bool) {
+	id, err := gid.ParseGID(chi.URLParam(r, "id"))
+	if err != nil {
+		return gid.GID{}, false
+	}
+
+	return id, true
+}
+
+// pagination reads the 1-based startIndex and count query parameters of
+// RFC 7644 section 3.4.2.4 and returns the matching offset and limit.
+func pagination(r *http.Request) (startIndex int, count int, err error) {
+	startIndex, count = 1, maxResults
+
+	if v := r.URL.Query().Get("startIndex"); v != "" {
+		startIndex, err = strconv.Atoi(v)
+		if err != nil {
+			return 0, 0, &errInvalidRequest{scimType: "invalidValue", message: "invalid startIndex"}
+		}
+		startIndex = max(startIndex, 1)
+	}
+
+	if v := r.URL.Query().Get("count"); v != "" {
+		count, err = strconv.Atoi(v)
+		if err != nil {
+			return 0, 0, &errInvalidRequest{scimType: "invalidValue", message: "invalid count"}
+		}
+		count = min(max(count, 0), maxResults)
+	}
+
+	return startIndex, count, nil
+}
+
+func ServiceProviderConfigHandler(usrmgrSvc *usrmgr.Service) http.HandlerFunc {
+	type (
+		supported struct {
+			Supported bool `json:"supported"`
+		}
+
+		filter struct {
+			Supported  bool `json:"supported"`
+			MaxResults int  `json:"maxResults"`
+		}
+
+		bulk struct {
+			Supported      bool `json:"supported"`
+			MaxOperations  int  `json:"maxOperations"`
+			MaxPayloadSize int  `json:"maxPayloadSize"`
+		}
+
+		authenticationScheme struct {
+			Type        string `json:"type"`
+			Name        string `json:"name"`
+			Description string `json:"description"`
+			Primary     bool   `json:"primary"`
+		}
+
+		serviceProviderConfig struct {
+			Schemas               []string               `json:"schemas"`
+			Patch                 supported              `json:"patch"`
+			Bulk                  bulk                   `json:"bulk"`
+			Filter                filter                 `json:"filter"`
+			ChangePassword        supported              `json:"changePassword"`
+			Sort                  supported              `json:"sort"`
+			ETag                  supported              `json:"etag"`
+			AuthenticationSchemes []authenticationScheme `json:"authenticationSchemes"`
+			Meta                  map[string]string      `json:"meta"`
+		}
+	)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		render(
+			w,
+			http.StatusOK,
+			serviceProviderConfig{
+				Schemas: []string{serviceProviderConfigSchema},
+				Patch:   supported{Supported: true},
+				Bulk:    bulk{Supported: false},
+				Filter:  filter{Supported: true, MaxResults: maxResults},
+				AuthenticationSchemes: []authenticationScheme{
+					{
+						Type:        "oauthbearertoken",
+						Name:        "OAuth Bearer Token",
+						Description: "Authentication with the SCIM token of the organization",
+						Primary:     true,
+					},
+				},
+				Meta: map[string]string{
+					"resourceType": "ServiceProviderConfig",
+					"location":     usrmgrSvc.SCIMBaseURL() + "/ServiceProviderConfig",
+				},
+			},
+		)
+	}
+}
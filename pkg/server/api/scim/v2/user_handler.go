@@ -0,0 +1,409 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package scim_v2
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/getprobo/probo/pkg/coredata"
+	"github.com/getprobo/probo/pkg/usrmgr"
+)
+
+type (
+	userResource struct {
+		Schemas     []string    `json:"schemas"`
+		ID          string      `json:"id,omitempty"`
+		ExternalID  *string     `json:"externalId,omitempty"`
+		UserName    string      `json:"userName"`
+		Name        *userName   `json:"name,omitempty"`
+		DisplayName string      `json:"displayName,omitempty"`
+		UserType    string      `json:"userType,omitempty"`
+		Active      *bool       `json:"active,omitempty"`
+		Emails      []userEmail `json:"emails,omitempty"`
+		Meta        *meta       `json:"meta,omitempty"`
+	}
+
+	userName struct {
+		Formatted  string `json:"formatted,omitempty"`
+		GivenName  string `json:"givenName,omitempty"`
+		FamilyName string `json:"familyName,omitempty"`
+	}
+
+	userEmail struct {
+		Value   string `json:"value"`
+		Type    string `json:"type,omitempty"`
+		Primary bool   `json:"primary,omitempty"`
+	}
+)
+
+const (
+	userTypeEmployee   = "Employee"
+	userTypeContractor = "Contractor"
+)
+
+func newUserResource(baseURL string, u *usrmgr.ProvisionedUser) *userResource {
+	userType := userTypeEmployee
+	if u.People.Kind == coredata.PeopleKindContractor {
+		userType = userTypeContractor
+	}
+
+	active := u.SCIMUser.Active
+
+	return &userResource{
+		Schemas:     []string{userSchema},
+		ID:          u.SCIMUser.ID.String(),
+		ExternalID:  u.SCIMUser.ExternalID,
+		UserName:    u.SCIMUser.UserName,
+		Name:        &userName{Formatted: u.People.FullName},
+		DisplayName: u.People.FullName,
+		UserType:    userType,
+		Active:      &active,
+		Emails: []userEmail{
+			{
+				Value:   u.People.PrimaryEmailAddress,
+				Type:    "work",
+				Primary: true,
+			},
+		},
+		Meta: &meta{
+			ResourceType: "User",
+			Created:      u.SCIMUser.CreatedAt,
+			LastModified: u.SCIMUser.UpdatedAt,
+			Location:     baseURL + "/Users/" + u.SCIMUser.ID.String(),
+		},
+	}
+}
+
+// request maps the resource to the attributes kept by Probo. The name of
+// the people record is the formatted name when provided, otherwise it is
+// built from its components or the display name.
+func (u userResource) request() usrmgr.SCIMUserRequest {
+	var fullName string
+	if u.Name != nil {
+		fullName = strings.TrimSpace(u.Name.Formatted)
+		if fullName == "" {
+			fullName = strings.TrimSpace(u.Name.GivenName + " " + u.Name.FamilyName)
+		}
+	}
+
+	if fullName == "" {
+		fullName = strings.TrimSpace(u.DisplayName)
+	}
+
+	kind := coredata.PeopleKindEmployee
+	if strings.EqualFold(u.UserType, userTypeContractor) {
+		kind = coredata.PeopleKindContractor
+	}
+
+	active := true
+	if u.Active != nil {
+		active = *u.Active
+	}
+
+	return usrmgr.SCIMUserRequest{
+		UserName:   u.UserName,
+		ExternalID: u.ExternalID,
+		FullName:   fullName,
+		Kind:       kind,
+		Active:     active,
+	}
+}
+
+// apply applies a patch operation to the resource. Only the attributes
+// Probo keeps can be patched; email addresses and enterprise extension
+// attributes are accepted and ignored as the user name is the email
+// address of the user.
+func (u *userResource) apply(op string, path string, value json.RawMessage) error {
+	if path == "" {
+		attributes, err := patchOperation{Value: value}.valueAttributes()
+		if err != nil {
+			return err
+		}
+
+		for attribute, value := range attributes {
+			if err := u.apply(op, attribute, value); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	lowerPath := strings.ToLower(path)
+	if lowerPath == "emails" ||
+		strings.HasPrefix(lowerPath, "emails[") ||
+		strings.HasPrefix(lowerPath, "emails.") ||
+		strings.HasPrefix(lowerPath, strings.ToLower(enterpriseUserSchema)) ||
+		lowerPath == "schemas" ||
+		lowerPath == "id" {
+		return nil
+	}
+
+	if u.Name == nil {
+		u.Name = &userName{}
+	}
+
+	if op == "remove" {
+		switch lowerPath {
+		case "externalid":
+			u.ExternalID = nil
+		case "displayname":
+			u.DisplayName = ""
+		case "name":
+			u.Name = &userName{}
+		case "name.formatted":
+			u.Name.Formatted = ""
+		case "name.givenname":
+			u.Name.GivenName = ""
+		case "name.familyname":
+			u.Name.FamilyName = ""
+		case "usertype":
+			u.UserType = ""
+		default:
+			return &errInvalidRequest{scimType: "mutability", message: fmt.Sprintf("attribute %q cannot be removed", path)}
+		}
+
+		return nil
+	}
+
+	var err error
+	switch lowerPath {
+	case "active":
+		var active bool
+		active, err = decodeBool(path, value)
+		u.Active = &active
+	case "username":
+		u.UserName, err = decodeString(path, value)
+	case "externalid":
+		var externalID string
+		externalID, err = decodeString(path, value)
+		u.ExternalID = &externalID
+	case "displayname":
+		previous := u.DisplayName
+		u.DisplayName, err = decodeString(path, value)
+		// Both hold the name of the people record until the identity
+		// provider sets them apart.
+		if u.Name.Formatted == previous {
+			u.Name.Formatted = u.DisplayName
+		}
+	case "usertype":
+		u.UserType, err = decodeString(path, value)
+	case "name":
+		name := userName{}
+		if err := json.Unmarshal(value, &name); err != nil {
+			return &errInvalidRequest{scimType: "invalidValue", message: "name must be an object"}
+		}
+		u.Name = &name
+	case "name.formatted":
+		u.Name.Formatted, err = decodeString(path, value)
+	case "name.givenname":
+		u.Name.GivenName, err = decodeString(path, value)
+		u.Name.Formatted = ""
+	case "name.familyname":
+		u.Name.FamilyName, err = decodeString(path, value)
+		u.Name.Formatted = ""
+	default:
+		return &errInvalidRequest{scimType: "invalidPath", message: fmt.Sprintf("unsupported attribute %q", path)}
+	}
+
+	return err
+}
+
+func ListUsersHandler(usrmgrSvc *usrmgr.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		configuration := configurationFromContext(ctx)
+
+		startIndex, count, err := pagination(r)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+
+		f, err := parseFilter(r.URL.Query().Get("filter"), "userName", "externalId")
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+
+		userFilter := coredata.SCIMUserFilter{}
+		if f != nil {
+			switch f.attribute {
+			case "userName":
+				userFilter.UserName = &f.value
+			case "externalId":
+				userFilter.ExternalID = &f.value
+			}
+		}
+
+		users, total, err := usrmgrSvc.ListSCIMUsers(ctx, configuration, userFilter, startIndex-1, count)
+		if err != nil {
+			panic(fmt.Errorf("cannot list scim users: %w", err))
+		}
+
+		resources := make([]any, 0, len(users))
+		for _, user := range users {
+			resources = append(resources, newUserResource(usrmgrSvc.SCIMBaseURL(), user))
+		}
+
+		render(
+			w,
+			http.StatusOK,
+			listResponse{
+				Schemas:      []string{listResponseSchema},
+				TotalResults: total,
+				StartIndex:   startIndex,
+				ItemsPerPage: len(resources),
+				Resources:    resources,
+			},
+		)
+	}
+}
+
+func GetUserHandler(usrmgrSvc *usrmgr.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		id, ok := resourceID(r)
+		if !ok {
+			renderError(w, http.StatusNotFound, "", "user not found")
+			return
+		}
+
+		user, err := usrmgrSvc.GetSCIMUser(ctx, configurationFromContext(ctx), id)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+
+		render(w, http.StatusOK, newUserResource(usrmgrSvc.SCIMBaseURL(), user))
+	}
+}
+
+func CreateUserHandler(usrmgrSvc *usrmgr.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		var resource userResource
+		if err := decodeRequest(w, r, &resource); err != nil {
+			handleError(w, err)
+			return
+		}
+
+		user, err := usrmgrSvc.CreateSCIMUser(ctx, configurationFromContext(ctx), resource.request())
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+
+		created := newUserResource(usrmgrSvc.SCIMBaseURL(), user)
+		w.Header().Set("Location", created.Meta.Location)
+		render(w, http.StatusCreated, created)
+	}
+}
+
+func ReplaceUserHandler(usrmgrSvc *usrmgr.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		id, ok := resourceID(r)
+		if !ok {
+			renderError(w, http.StatusNotFound, "", "user not found")
+			return
+		}
+
+		var resource userResource
+		if err := decodeRequest(w, r, &resource); err != nil {
+			handleError(w, err)
+			return
+		}
+
+		user, err := usrmgrSvc.ReplaceSCIMUser(ctx, configurationFromContext(ctx), id, resource.request())
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+
+		render(w, http.StatusOK, newUserResource(usrmgrSvc.SCIMBaseURL(), user))
+	}
+}
+
+func PatchUserHandler(usrmgrSvc *usrmgr.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		configuration := configurationFromContext(ctx)
+
+		id, ok := resourceID(r)
+		if !ok {
+			renderError(w, http.StatusNotFound, "", "user not found")
+			return
+		}
+
+		var req patchRequest
+		if err := decodeRequest(w, r, &req); err != nil {
+			handleError(w, err)
+			return
+		}
+
+		if err := req.validate(); err != nil {
+			handleError(w, err)
+			return
+		}
+
+		user, err := usrmgrSvc.GetSCIMUser(ctx, configuration, id)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+
+		resource := newUserResource(usrmgrSvc.SCIMBaseURL(), user)
+		for _, operation := range req.Operations {
+			if err := resource.apply(operation.Op, operation.Path, operation.Value); err != nil {
+				handleError(w, err)
+				return
+			}
+		}
+
+		user, err = usrmgrSvc.ReplaceSCIMUser(ctx, configuration, id, resource.request())
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+
+		render(w, http.StatusOK, newUserResource(usrmgrSvc.SCIMBaseURL(), user))
+	}
+}
+
+func DeleteUserHandler(usrmgrSvc *usrmgr.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		id, ok := resourceID(r)
+		if !ok {
+			renderError(w, http.StatusNotFound, "", "user not found")
+			return
+		}
+
+		if err := usrmgrSvc.DeleteSCIMUser(ctx, configurationFromContext(ctx), id); err != nil {
+			handleError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -22,13 +22,15 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/getprobo/probo/pkg/crypto/keyring"
 )
 
 type (
 	// Config holds the configuration for tokens
 	Config struct {
-		// Secret is the secret key used for signing tokens
-		Secret string
+		// Keyring holds the keys used for signing tokens
+		Keyring *keyring.Keyring
 
 		// ExpirationTime is the duration after which a token expires
 		ExpirationTime time.Duration
@@ -66,8 +68,12 @@ func (e ErrExpiredToken) Error() string {
 	return e.message
 }
 
-// NewToken creates a new token with the specified type, data, and expiration time
-func NewToken[T any](secret string, tokenType string, expirationTime time.Duration, data T) (string, error) {
+// NewToken creates a new token with the specified type, data, and expiration time.
+// The token is signed with the current key of the keyring and has the
+// following format:
+//
+//	<key id>.<payload>.<signature>
+func NewToken[T any](kr *keyring.Keyring, tokenType string, expirationTime time.Duration, data T) (string, error) {
 	now := time.Now()
 
 	payload := Payload[T]{
@@ -84,31 +90,53 @@ func NewToken[T any](secret string, tokenType string, expirationTime time.Durati
 
 	encodedPayload := base64.RawURLEncoding.EncodeToString(payloadBytes)
 
-	h := hmac.New(sha256.New, []byte(secret))
-	h.Write([]byte(encodedPayload))
-	signature := base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+	key := kr.Current()
+	signedPart := key.ID + "." + encodedPayload
 
-	tokenString := encodedPayload + "." + signature
+	tokenString := signedPart + "." + signature(key.Secret, signedPart)
 
 	return tokenString, nil
 }
 
 // ValidateToken validates a token and unmarshals the payload
-// It returns an error if the token is invalid or expired
-func ValidateToken[T any](secret string, tokenType string, tokenString string) (*Payload[T], error) {
+// It returns an error if the token is invalid or expired. Tokens issued
+// before key IDs were introduced, with the "<payload>.<signature>" format,
+// are checked against every key of the keyring.
+func ValidateToken[T any](kr *keyring.Keyring, tokenType string, tokenString string) (*Payload[T], error) {
+	var (
+		encodedPayload string
+		candidates     []keyring.Key
+	)
+
 	parts := strings.Split(tokenString, ".")
-	if len(parts) != 2 {
+	switch len(parts) {
+	case 2:
+		encodedPayload = parts[0]
+		candidates = kr.Keys()
+	case 3:
+		key, ok := kr.Key(parts[0])
+		if !ok {
+			return nil, &ErrInvalidToken{message: "unknown token key"}
+		}
+
+		encodedPayload = parts[1]
+		candidates = []keyring.Key{key}
+	default:
 		return nil, &ErrInvalidToken{message: "invalid token format"}
 	}
 
-	encodedPayload := parts[0]
-	providedSignature := parts[1]
+	signedPart := tokenString[:strings.LastIndex(tokenString, ".")]
+	providedSignature := parts[len(parts)-1]
 
-	h := hmac.New(sha256.New, []byte(secret))
-	h.Write([]byte(encodedPayload))
-	expectedSignature := base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+	valid := false
+	for _, key := range candidates {
+		if hmac.Equal([]byte(providedSignature), []byte(signature(key.Secret, signedPart))) {
+			valid = true
+			break
+		}
+	}
 
-	if providedSignature != expectedSignature {
+	if !valid {
 		return nil, &ErrInvalidToken{message: "invalid token signature"}
 	}
 
@@ -132,3 +160,10 @@ func ValidateToken[T any](secret string, tokenType string, tokenString string) (
 
 	return &payload, nil
 }
+
+func signature(secret []byte, value string) string {
+	h := hmac.New(sha256.New, secret)
+	h.Write([]byte(value))
+
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
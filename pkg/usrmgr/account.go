@@ -0,0 +1,349 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package usrmgr
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/getprobo/probo/pkg/coredata"
+	"github.com/getprobo/probo/pkg/gid"
+	"go.gearno.de/kit/pg"
+)
+
+type (
+	// PersonalData is everything stored about a user, as handed to them
+	// when they request a copy of their data. Secrets such as the password
+	// hash or the TOTP secret are left out.
+	PersonalData struct {
+		ExportedAt          time.Time                        `json:"exportedAt"`
+		User                PersonalDataUser                 `json:"user"`
+		Sessions            []PersonalDataSession            `json:"sessions"`
+		Memberships         []PersonalDataMembership         `json:"memberships"`
+		WebAuthnCredentials []PersonalDataWebAuthnCredential `json:"webAuthnCredentials"`
+		APITokens           []PersonalDataAPIToken           `json:"apiTokens"`
+		People              []PersonalDataPeople             `json:"people"`
+	}
+
+	PersonalDataUser struct {
+		ID                   gid.GID   `json:"id"`
+		EmailAddress         string    `json:"emailAddress"`
+		EmailAddressVerified bool      `json:"emailAddressVerified"`
+		FullName             string    `json:"fullName"`
+		MFAEnabled           bool      `json:"mfaEnabled"`
+		CreatedAt            time.Time `json:"createdAt"`
+		UpdatedAt            time.Time `json:"updatedAt"`
+	}
+
+	PersonalDataSession struct {
+		ID             gid.GID   `json:"id"`
+		UserAgent      string    `json:"userAgent"`
+		IPAddress      string    `json:"ipAddress"`
+		LastActivityAt time.Time `json:"lastActivityAt"`
+		ExpiresAt      time.Time `json:"expiresAt"`
+		CreatedAt      time.Time `json:"createdAt"`
+	}
+
+	PersonalDataMembership struct {
+		OrganizationID   gid.GID                 `json:"organizationId"`
+		OrganizationName string                  `json:"organizationName"`
+		Role             coredata.MembershipRole `json:"role"`
+		CreatedAt        time.Time               `json:"createdAt"`
+	}
+
+	PersonalDataWebAuthnCredential struct {
+		ID         gid.GID    `json:"id"`
+		Name       string     `json:"name"`
+		LastUsedAt *time.Time `json:"lastUsedAt"`
+		CreatedAt  time.Time  `json:"createdAt"`
+	}
+
+	PersonalDataAPIToken struct {
+		ID             gid.GID    `json:"id"`
+		OrganizationID gid.GID    `json:"organizationId"`
+		Name           string     `json:"name"`
+		ExpiresAt      *time.Time `json:"expiresAt"`
+		LastUsedAt     *time.Time `json:"lastUsedAt"`
+		CreatedAt      time.Time  `json:"createdAt"`
+	}
+
+	// PersonalDataPeople is a people record of an organization matching
+	// the email address of the user, with what is assigned to it.
+	PersonalDataPeople struct {
+		ID                       gid.GID              `json:"id"`
+		OrganizationID           gid.GID              `json:"organizationId"`
+		Kind                     coredata.PeopleKind  `json:"kind"`
+		FullName                 string               `json:"fullName"`
+		PrimaryEmailAddress      string               `json:"primaryEmailAddress"`
+		AdditionalEmailAddresses []string             `json:"additionalEmailAddresses"`
+		AssignedTasks            []PersonalDataTask   `json:"assignedTasks"`
+		OwnedPolicies            []PersonalDataPolicy `json:"ownedPolicies"`
+		CreatedAt                time.Time            `json:"createdAt"`
+		UpdatedAt                time.Time            `json:"updatedAt"`
+	}
+
+	PersonalDataTask struct {
+		ID        gid.GID            `json:"id"`
+		ControlID gid.GID            `json:"controlId"`
+		Name      string             `json:"name"`
+		State     coredata.TaskState `json:"state"`
+		CreatedAt time.Time          `json:"createdAt"`
+	}
+
+	PersonalDataPolicy struct {
+		ID        gid.GID               `json:"id"`
+		Name      string                `json:"name"`
+		Status    coredata.PolicyStatus `json:"status"`
+		CreatedAt time.Time             `json:"createdAt"`
+	}
+
+	ErrAccountDeletionNotConfirmed struct {
+		message string
+	}
+)
+
+func (e ErrAccountDeletionNotConfirmed) Error() string {
+	return e.message
+}
+
+// ExportPersonalData collects everything stored about the user.
+func (s Service) ExportPersonalData(
+	ctx context.Context,
+	userID gid.GID,
+) (*PersonalData, error) {
+	now := time.Now()
+	data := &PersonalData{ExportedAt: now}
+
+	err := s.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			user := &coredata.User{}
+			if err := user.LoadByID(ctx, conn, userID); err != nil {
+				return fmt.Errorf("cannot load user: %w", err)
+			}
+
+			data.User = PersonalDataUser{
+				ID:                   user.ID,
+				EmailAddress:         user.EmailAddress,
+				EmailAddressVerified: user.EmailAddressVerified,
+				FullName:             user.FullName,
+				MFAEnabled:           user.MFAEnabled(),
+				CreatedAt:            user.CreatedAt,
+				UpdatedAt:            user.UpdatedAt,
+			}
+
+			var sessions coredata.Sessions
+			if err := sessions.LoadActiveByUserID(ctx, conn, userID, now); err != nil {
+				return fmt.Errorf("cannot load sessions: %w", err)
+			}
+
+			data.Sessions = make([]PersonalDataSession, len(sessions))
+			for i, session := range sessions {
+				data.Sessions[i] = PersonalDataSession{
+					ID:             session.ID,
+					UserAgent:      session.UserAgent,
+					IPAddress:      session.IPAddress,
+					LastActivityAt: session.LastActivityAt,
+					ExpiresAt:      session.ExpiredAt,
+					CreatedAt:      session.CreatedAt,
+				}
+			}
+
+			var memberships coredata.UserOrganizations
+			if err := memberships.ForUserID(ctx, conn, userID); err != nil {
+				return fmt.Errorf("cannot load memberships: %w", err)
+			}
+
+			data.Memberships = make([]PersonalDataMembership, len(memberships))
+			for i, membership := range memberships {
+				organization := &coredata.Organization{}
+				scope := coredata.NewScope(membership.OrganizationID.TenantID())
+
+				if err := organization.LoadByID(ctx, conn, scope, membership.OrganizationID); err != nil {
+					return fmt.Errorf("cannot load organization: %w", err)
+				}
+
+				data.Memberships[i] = PersonalDataMembership{
+					OrganizationID:   organization.ID,
+					OrganizationName: organization.Name,
+					Role:             membership.Role,
+					CreatedAt:        membership.CreatedAt,
+				}
+			}
+
+			var credentials coredata.WebAuthnCredentials
+			if err := credentials.LoadByUserID(ctx, conn, userID); err != nil {
+				return fmt.Errorf("cannot load webauthn credentials: %w", err)
+			}
+
+			data.WebAuthnCredentials = make([]PersonalDataWebAuthnCredential, len(credentials))
+			for i, credential := range credentials {
+				data.WebAuthnCredentials[i] = PersonalDataWebAuthnCredential{
+					ID:         credential.ID,
+					Name:       credential.Name,
+					LastUsedAt: credential.LastUsedAt,
+					CreatedAt:  credential.CreatedAt,
+				}
+			}
+
+			var apiTokens coredata.APITokens
+			if err := apiTokens.LoadByUserID(ctx, conn, userID); err != nil {
+				return fmt.Errorf("cannot load api tokens: %w", err)
+			}
+
+			data.APITokens = make([]PersonalDataAPIToken, len(apiTokens))
+			for i, apiToken := range apiTokens {
+				data.APITokens[i] = PersonalDataAPIToken{
+					ID:             apiToken.ID,
+					OrganizationID: apiToken.OrganizationID,
+					Name:           apiToken.Name,
+					ExpiresAt:      apiToken.ExpiresAt,
+					LastUsedAt:     apiToken.LastUsedAt,
+					CreatedAt:      apiToken.CreatedAt,
+				}
+			}
+
+			people, err := exportPeople(ctx, conn, user)
+			if err != nil {
+				return err
+			}
+
+			data.People = people
+
+			return nil
+		},
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot export personal data: %w", err)
+	}
+
+	return data, nil
+}
+
+func exportPeople(
+	ctx context.Context,
+	conn pg.Conn,
+	user *coredata.User,
+) ([]PersonalDataPeople, error) {
+	var peoples coredata.Peoples
+	if err := peoples.LoadByEmailAddressForUserID(ctx, conn, user.ID, user.EmailAddress); err != nil {
+		return nil, fmt.Errorf("cannot load people: %w", err)
+	}
+
+	result := make([]PersonalDataPeople, len(peoples))
+	for i, people := range peoples {
+		scope := coredata.NewScope(people.ID.TenantID())
+
+		var tasks coredata.Tasks
+		if err := tasks.LoadByAssignedToID(ctx, conn, scope, people.ID); err != nil {
+			return nil, fmt.Errorf("cannot load assigned tasks: %w", err)
+		}
+
+		var policies coredata.Policies
+		if err := policies.LoadByOwnerID(ctx, conn, scope, people.ID); err != nil {
+			return nil, fmt.Errorf("cannot load owned policies: %w", err)
+		}
+
+		result[i] = PersonalDataPeople{
+			ID:                       people.ID,
+			OrganizationID:           people.OrganizationID,
+			Kind:                     people.Kind,
+			FullName:                 people.FullName,
+			PrimaryEmailAddress:      people.PrimaryEmailAddress,
+			AdditionalEmailAddresses: people.AdditionalEmailAddresses,
+			AssignedTasks:            make([]PersonalDataTask, len(tasks)),
+			OwnedPolicies:            make([]PersonalDataPolicy, len(policies)),
+			CreatedAt:                people.CreatedAt,
+			UpdatedAt:                people.UpdatedAt,
+		}
+
+		for j, task := range tasks {
+			result[i].AssignedTasks[j] = PersonalDataTask{
+				ID:        task.ID,
+				ControlID: task.ControlID,
+				Name:      task.Name,
+				State:     task.State,
+				CreatedAt: task.CreatedAt,
+			}
+		}
+
+		for j, policy := range policies {
+			result[i].OwnedPolicies[j] = PersonalDataPolicy{
+				ID:        policy.ID,
+				Name:      policy.Name,
+				Status:    policy.Status,
+				CreatedAt: policy.CreatedAt,
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// DeleteAccount deletes the user, their sessions and memberships. The
+// email address of the account must be given back as confirmation, and
+// the last owner of an organization cannot leave it ownerless. People
+// records belong to the organizations and are kept.
+func (s Service) DeleteAccount(
+	ctx context.Context,
+	userID gid.GID,
+	emailConfirmation string,
+) error {
+	return s.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+			user := &coredata.User{}
+			if err := user.LoadByID(ctx, tx, userID); err != nil {
+				return fmt.Errorf("cannot load user: %w", err)
+			}
+
+			if !strings.EqualFold(strings.TrimSpace(emailConfirmation), user.EmailAddress) {
+				return &ErrAccountDeletionNotConfirmed{message: "the email address does not match the account"}
+			}
+
+			var memberships coredata.UserOrganizations
+			if err := memberships.ForUserID(ctx, tx, userID); err != nil {
+				return fmt.Errorf("cannot load memberships: %w", err)
+			}
+
+			for _, membership := range memberships {
+				if membership.Role != coredata.MembershipRoleOwner {
+					continue
+				}
+
+				if err := ensureNotLastOwner(ctx, tx, membership.OrganizationID); err != nil {
+					return err
+				}
+			}
+
+			if err := coredata.DeleteUserSessions(ctx, tx, userID); err != nil {
+				return fmt.Errorf("cannot delete sessions: %w", err)
+			}
+
+			if err := coredata.DeleteUserOrganizations(ctx, tx, userID); err != nil {
+				return fmt.Errorf("cannot delete memberships: %w", err)
+			}
+
+			if err := user.Delete(ctx, tx); err != nil {
+				return fmt.Errorf("cannot delete user: %w", err)
+			}
+
+			return nil
+		},
+	)
+}
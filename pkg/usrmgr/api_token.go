@@ -0,0 +1,271 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package usrmgr
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/getprobo/probo/pkg/coredata"
+	"github.com/getprobo/probo/pkg/gid"
+	"go.gearno.de/kit/pg"
+)
+
+type (
+	ErrInvalidAPIToken struct {
+		message string
+	}
+
+	ErrInvalidAPITokenName struct {
+		name string
+	}
+
+	ErrInvalidAPITokenExpiration struct {
+		expiresAt time.Time
+	}
+)
+
+const (
+	apiTokenPrefix         = "probo_api_"
+	maxAPITokenNameLength  = 100
+	apiTokenLastUsedWindow = time.Minute
+)
+
+func (e ErrInvalidAPIToken) Error() string {
+	return e.message
+}
+
+func (e ErrInvalidAPITokenName) Error() string {
+	return fmt.Sprintf("invalid api token name %q: must be between 1 and %d characters", e.name, maxAPITokenNameLength)
+}
+
+func (e ErrInvalidAPITokenExpiration) Error() string {
+	return fmt.Sprintf("invalid api token expiration %s: must be in the future", e.expiresAt.Format(time.RFC3339))
+}
+
+func hashAPIToken(token string) []byte {
+	hash := sha256.Sum256([]byte(token))
+	return hash[:]
+}
+
+// CreateAPIToken issues a token acting as the user on a single
+// organization. Only a digest of the token is stored, so it is returned
+// once here.
+func (s Service) CreateAPIToken(
+	ctx context.Context,
+	userID gid.GID,
+	organizationID gid.GID,
+	name string,
+	expiresAt *time.Time,
+) (*coredata.APIToken, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxAPITokenNameLength {
+		return nil, "", &ErrInvalidAPITokenName{name: name}
+	}
+
+	now := time.Now()
+	if expiresAt != nil && !expiresAt.After(now) {
+		return nil, "", &ErrInvalidAPITokenExpiration{expiresAt: *expiresAt}
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", fmt.Errorf("cannot generate api token: %w", err)
+	}
+
+	token := apiTokenPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	scope := coredata.NewScope(organizationID.TenantID())
+
+	apiToken := &coredata.APIToken{
+		ID:             gid.New(organizationID.TenantID(), coredata.APITokenEntityType),
+		TenantID:       organizationID.TenantID(),
+		OrganizationID: organizationID,
+		UserID:         userID,
+		Name:           name,
+		HashedToken:    hashAPIToken(token),
+		ExpiresAt:      expiresAt,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	err := s.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+			organization := &coredata.Organization{}
+			if err := organization.LoadByID(ctx, tx, scope, organizationID); err != nil {
+				return fmt.Errorf("cannot load organization: %w", err)
+			}
+
+			if err := apiToken.Insert(ctx, tx, scope); err != nil {
+				return fmt.Errorf("cannot insert api token: %w", err)
+			}
+
+			return nil
+		},
+	)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	return apiToken, token, nil
+}
+
+func (s Service) GetAPIToken(
+	ctx context.Context,
+	apiTokenID gid.GID,
+) (*coredata.APIToken, error) {
+	apiToken := &coredata.APIToken{}
+	scope := coredata.NewScope(apiTokenID.TenantID())
+
+	err := s.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			return apiToken.LoadByID(ctx, conn, scope, apiTokenID)
+		},
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot load api token: %w", err)
+	}
+
+	return apiToken, nil
+}
+
+func (s Service) ListAPITokens(
+	ctx context.Context,
+	organizationID gid.GID,
+) (coredata.APITokens, error) {
+	var apiTokens coredata.APITokens
+	scope := coredata.NewScope(organizationID.TenantID())
+
+	err := s.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			return apiTokens.LoadByOrganizationID(ctx, conn, scope, organizationID)
+		},
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot list api tokens: %w", err)
+	}
+
+	return apiTokens, nil
+}
+
+func (s Service) RevokeAPIToken(
+	ctx context.Context,
+	apiTokenID gid.GID,
+) (*coredata.APIToken, error) {
+	apiToken := &coredata.APIToken{}
+	scope := coredata.NewScope(apiTokenID.TenantID())
+
+	err := s.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+			if err := apiToken.LoadByID(ctx, tx, scope, apiTokenID); err != nil {
+				return fmt.Errorf("cannot load api token: %w", err)
+			}
+
+			if err := apiToken.Delete(ctx, tx, scope); err != nil {
+				return fmt.Errorf("cannot delete api token: %w", err)
+			}
+
+			return nil
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return apiToken, nil
+}
+
+// AuthenticateAPIToken resolves the token and the user it acts as. The
+// token stops working once expired or when its user leaves the
+// organization.
+func (s Service) AuthenticateAPIToken(
+	ctx context.Context,
+	token string,
+) (*coredata.APIToken, *coredata.User, error) {
+	if !strings.HasPrefix(token, apiTokenPrefix) {
+		return nil, nil, &ErrInvalidAPIToken{message: "invalid api token"}
+	}
+
+	now := time.Now()
+	apiToken := &coredata.APIToken{}
+	user := &coredata.User{}
+
+	err := s.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			if err := apiToken.LoadByHashedToken(ctx, conn, hashAPIToken(token)); err != nil {
+				var errNotFound *coredata.ErrAPITokenNotFound
+				if errors.As(err, &errNotFound) {
+					return &ErrInvalidAPIToken{message: "invalid api token"}
+				}
+
+				return fmt.Errorf("cannot load api token: %w", err)
+			}
+
+			if apiToken.Expired(now) {
+				return &ErrInvalidAPIToken{message: "api token expired"}
+			}
+
+			var userOrganizations coredata.UserOrganizations
+			if err := userOrganizations.ForUserID(ctx, conn, apiToken.UserID); err != nil {
+				return fmt.Errorf("cannot load user organizations: %w", err)
+			}
+
+			member := false
+			for _, uo := range userOrganizations {
+				if uo.OrganizationID == apiToken.OrganizationID {
+					member = true
+					break
+				}
+			}
+
+			if !member {
+				return &ErrInvalidAPIToken{message: "invalid api token"}
+			}
+
+			if err := user.LoadByID(ctx, conn, apiToken.UserID); err != nil {
+				return fmt.Errorf("cannot load user: %w", err)
+			}
+
+			// Avoids a write on every request of busy integrations.
+			if apiToken.LastUsedAt == nil || now.Sub(*apiToken.LastUsedAt) > apiTokenLastUsedWindow {
+				if err := apiToken.UpdateLastUsedAt(ctx, conn, now); err != nil {
+					return fmt.Errorf("cannot update api token: %w", err)
+				}
+			}
+
+			return nil
+		},
+	)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return apiToken, user, nil
+}
@@ -0,0 +1,238 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package usrmgr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/getprobo/probo/pkg/coredata"
+	"github.com/getprobo/probo/pkg/gid"
+	"github.com/getprobo/probo/pkg/statelesstoken"
+	"go.gearno.de/kit/pg"
+)
+
+type (
+	EmailChangeData struct {
+		UserID gid.GID `json:"uid"`
+		// CurrentEmail makes the token single-use, once the address
+		// changed the token does not match the user anymore.
+		CurrentEmail string `json:"current_email"`
+		NewEmail     string `json:"new_email"`
+	}
+
+	ErrInvalidEmailChangeToken struct {
+		message string
+	}
+)
+
+var (
+	emailChangeConfirmationEmailSubject  = "Confirm your new email address"
+	emailChangeConfirmationEmailTemplate = `
+	You asked to use this email address for your Probo account.
+	Please confirm it by clicking the link below[1]
+
+	If you did not request this change, you can safely ignore this email.
+
+	[1] %s
+	`
+
+	emailChangeNoticeEmailSubject  = "Your email address is being changed"
+	emailChangeNoticeEmailTemplate = `
+	A request was made to change the email address of your Probo account to %s.
+	The change will only take effect once the new address is confirmed.
+
+	If you did not request this change, please reset your password[1]
+	and contact your administrator.
+
+	[1] %s
+	`
+)
+
+func (e ErrInvalidEmailChangeToken) Error() string {
+	return e.message
+}
+
+// RequestEmailChange sends a confirmation link to the new address and a
+// notice to the current one. The address is only changed once the link is
+// followed, see ConfirmEmailChange. The link is sent even when another
+// account uses the address, so the request does not reveal which
+// addresses have an account. Addresses are lower-cased as single sign-on
+// and provisioning do.
+func (s Service) RequestEmailChange(
+	ctx context.Context,
+	userID gid.GID,
+	newEmail string,
+) error {
+	newEmail = strings.ToLower(strings.TrimSpace(newEmail))
+
+	if !strings.Contains(newEmail, "@") {
+		return &ErrInvalidEmail{newEmail}
+	}
+
+	return s.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+			user := &coredata.User{}
+			if err := user.LoadByID(ctx, tx, userID); err != nil {
+				return fmt.Errorf("cannot load user: %w", err)
+			}
+
+			if strings.EqualFold(user.EmailAddress, newEmail) {
+				return &ErrInvalidEmail{newEmail}
+			}
+
+			changeToken, err := statelesstoken.NewToken(
+				s.tokenKeyring,
+				TokenTypeEmailChange,
+				24*time.Hour,
+				EmailChangeData{
+					UserID:       user.ID,
+					CurrentEmail: user.EmailAddress,
+					NewEmail:     newEmail,
+				},
+			)
+			if err != nil {
+				return fmt.Errorf("cannot generate email change token: %w", err)
+			}
+
+			confirmationUrl := url.URL{
+				Scheme: "https",
+				Host:   s.hostname,
+				Path:   "/confirm-email-change",
+				RawQuery: url.Values{
+					"token": []string{changeToken},
+				}.Encode(),
+			}
+
+			forgotPasswordUrl := url.URL{
+				Scheme: "https",
+				Host:   s.hostname,
+				Path:   "/forgot-password",
+			}
+
+			confirmationEmail := coredata.NewEmail(
+				user.FullName,
+				newEmail,
+				emailChangeConfirmationEmailSubject,
+				fmt.Sprintf(emailChangeConfirmationEmailTemplate, confirmationUrl.String()),
+			)
+
+			noticeEmail := coredata.NewEmail(
+				user.FullName,
+				user.EmailAddress,
+				emailChangeNoticeEmailSubject,
+				fmt.Sprintf(emailChangeNoticeEmailTemplate, newEmail, forgotPasswordUrl.String()),
+			)
+
+			if err := confirmationEmail.Insert(ctx, tx); err != nil {
+				return fmt.Errorf("cannot insert email: %w", err)
+			}
+
+			if err := noticeEmail.Insert(ctx, tx); err != nil {
+				return fmt.Errorf("cannot insert email: %w", err)
+			}
+
+			return nil
+		},
+	)
+}
+
+// ConfirmEmailChange switches the address of the user to the one the
+// token was sent to. The token must have been issued for the user. When
+// another account got the address in the meantime, the token is reported
+// as no longer valid.
+func (s Service) ConfirmEmailChange(
+	ctx context.Context,
+	userID gid.GID,
+	tokenString string,
+) (*coredata.User, error) {
+	token, err := statelesstoken.ValidateToken[EmailChangeData](
+		s.tokenKeyring,
+		TokenTypeEmailChange,
+		tokenString,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot validate email change token: %w", err)
+	}
+
+	if token.Data.UserID != userID {
+		return nil, &ErrInvalidEmailChangeToken{message: "email change token was issued for another user"}
+	}
+
+	user := &coredata.User{}
+
+	err = s.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+			if err := user.LoadByID(ctx, tx, token.Data.UserID); err != nil {
+				return fmt.Errorf("cannot load user: %w", err)
+			}
+
+			if user.EmailAddress != token.Data.CurrentEmail {
+				return &ErrInvalidEmailChangeToken{message: "email change token is no longer valid"}
+			}
+
+			newEmail := strings.ToLower(token.Data.NewEmail)
+
+			if err := ensureEmailAvailable(ctx, tx, newEmail); err != nil {
+				return err
+			}
+
+			if err := user.UpdateEmailAddress(ctx, tx, newEmail); err != nil {
+				var errUserAlreadyExists *coredata.ErrUserAlreadyExists
+				if errors.As(err, &errUserAlreadyExists) {
+					return &ErrInvalidEmailChangeToken{message: "email change token is no longer valid"}
+				}
+
+				return fmt.Errorf("cannot update user email address: %w", err)
+			}
+
+			if err := autoJoinOrganizations(ctx, tx, user); err != nil {
+				return fmt.Errorf("cannot auto-join organizations: %w", err)
+			}
+
+			return nil
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// ensureEmailAvailable checks no account uses the email address yet. The
+// unique constraint on users still guards against concurrent changes.
+func ensureEmailAvailable(ctx context.Context, conn pg.Conn, email string) error {
+	existingUser := &coredata.User{}
+
+	err := existingUser.LoadByEmail(ctx, conn, email)
+	if err == nil {
+		return &ErrInvalidEmailChangeToken{message: "email change token is no longer valid"}
+	}
+
+	var errUserNotFound *coredata.ErrUserNotFound
+	if !errors.As(err, &errUserNotFound) {
+		return fmt.Errorf("cannot load user by email: %w", err)
+	}
+
+	return nil
+}
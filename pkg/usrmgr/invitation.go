@@ -0,0 +1,376 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package usrmgr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/getprobo/probo/pkg/coredata"
+	"github.com/getprobo/probo/pkg/gid"
+	"github.com/getprobo/probo/pkg/statelesstoken"
+	"go.gearno.de/kit/pg"
+)
+
+type (
+	ErrInvitationNotPending struct {
+		message string
+	}
+)
+
+const (
+	invitationDuration = 12 * time.Hour
+)
+
+func (e ErrInvitationNotPending) Error() string {
+	return e.message
+}
+
+// InviteUser adds an existing user to the organization right away,
+// otherwise it records a pending invitation and emails a sign up link.
+// Inviting the same address again refreshes the pending invitation.
+func (s Service) InviteUser(
+	ctx context.Context,
+	organizationID gid.GID,
+	fullName string,
+	emailAddress string,
+) error {
+	if !strings.Contains(emailAddress, "@") {
+		return &ErrInvalidEmail{emailAddress}
+	}
+	if fullName == "" {
+		return &ErrInvalidFullName{fullName}
+	}
+
+	scope := coredata.NewScope(organizationID.TenantID())
+
+	return s.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+			user := &coredata.User{}
+
+			err := user.LoadByEmail(ctx, tx, emailAddress)
+			if err == nil {
+				uo := coredata.UserOrganization{
+					UserID:         user.ID,
+					OrganizationID: organizationID,
+					Role:           coredata.MembershipRoleMember,
+					CreatedAt:      time.Now(),
+				}
+
+				if err := uo.Insert(ctx, tx); err != nil {
+					return fmt.Errorf("cannot insert user organization: %w", err)
+				}
+
+				return nil
+			}
+
+			var errUserNotFound *coredata.ErrUserNotFound
+			if !errors.As(err, &errUserNotFound) {
+				return fmt.Errorf("cannot load user by email: %w", err)
+			}
+
+			now := time.Now()
+			invitation := &coredata.Invitation{}
+
+			var errInvitationNotFound *coredata.ErrInvitationNotFound
+
+			err = invitation.LoadPendingByEmailAddress(ctx, tx, scope, organizationID, emailAddress)
+			switch {
+			case err == nil:
+				invitation.FullName = fullName
+				invitation.ExpiresAt = now.Add(invitationDuration)
+				invitation.UpdatedAt = now
+
+				if err := invitation.Update(ctx, tx, scope); err != nil {
+					return fmt.Errorf("cannot update invitation: %w", err)
+				}
+			case errors.As(err, &errInvitationNotFound):
+				invitation = &coredata.Invitation{
+					ID:             gid.New(organizationID.TenantID(), coredata.InvitationEntityType),
+					OrganizationID: organizationID,
+					EmailAddress:   emailAddress,
+					FullName:       fullName,
+					Status:         coredata.InvitationStatusPending,
+					ExpiresAt:      now.Add(invitationDuration),
+					CreatedAt:      now,
+					UpdatedAt:      now,
+				}
+
+				if err := invitation.Insert(ctx, tx, scope); err != nil {
+					return fmt.Errorf("cannot insert invitation: %w", err)
+				}
+			default:
+				return fmt.Errorf("cannot load pending invitation: %w", err)
+			}
+
+			return s.sendInvitationEmail(ctx, tx, invitation)
+		},
+	)
+}
+
+func (s Service) ListInvitations(
+	ctx context.Context,
+	organizationID gid.GID,
+) (coredata.Invitations, error) {
+	var invitations coredata.Invitations
+
+	err := s.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			return invitations.LoadByOrganizationID(
+				ctx,
+				conn,
+				coredata.NewScope(organizationID.TenantID()),
+				organizationID,
+			)
+		},
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot list invitations: %w", err)
+	}
+
+	return invitations, nil
+}
+
+// ResendInvitation emails a new sign up link for a pending invitation and
+// extends its expiration.
+func (s Service) ResendInvitation(
+	ctx context.Context,
+	invitationID gid.GID,
+) (*coredata.Invitation, error) {
+	scope := coredata.NewScope(invitationID.TenantID())
+	invitation := &coredata.Invitation{}
+
+	err := s.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+			if err := invitation.LoadByID(ctx, tx, scope, invitationID); err != nil {
+				return fmt.Errorf("cannot load invitation: %w", err)
+			}
+
+			if invitation.Status != coredata.InvitationStatusPending {
+				return &ErrInvitationNotPending{message: "only pending invitations can be resent"}
+			}
+
+			now := time.Now()
+			invitation.ExpiresAt = now.Add(invitationDuration)
+			invitation.UpdatedAt = now
+
+			if err := invitation.Update(ctx, tx, scope); err != nil {
+				return fmt.Errorf("cannot update invitation: %w", err)
+			}
+
+			return s.sendInvitationEmail(ctx, tx, invitation)
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return invitation, nil
+}
+
+// RevokeInvitation cancels a pending invitation, the links already sent
+// cannot be used anymore.
+func (s Service) RevokeInvitation(
+	ctx context.Context,
+	invitationID gid.GID,
+) (*coredata.Invitation, error) {
+	scope := coredata.NewScope(invitationID.TenantID())
+	invitation := &coredata.Invitation{}
+
+	err := s.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+			if err := invitation.LoadByID(ctx, tx, scope, invitationID); err != nil {
+				return fmt.Errorf("cannot load invitation: %w", err)
+			}
+
+			if invitation.Status != coredata.InvitationStatusPending {
+				return &ErrInvitationNotPending{message: "only pending invitations can be revoked"}
+			}
+
+			invitation.Status = coredata.InvitationStatusRevoked
+			invitation.UpdatedAt = time.Now()
+
+			if err := invitation.Update(ctx, tx, scope); err != nil {
+				return fmt.Errorf("cannot update invitation: %w", err)
+			}
+
+			return nil
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return invitation, nil
+}
+
+func (s Service) ConfirmInvitation(ctx context.Context, tokenString string, password string) error {
+	token, err := statelesstoken.ValidateToken[InvitationData](
+		s.tokenKeyring,
+		TokenTypeOrganizationInvitation,
+		tokenString,
+	)
+	if err != nil {
+		return fmt.Errorf("cannot validate organization invitation token: %w", err)
+	}
+
+	if len(password) < 8 {
+		return &ErrInvalidPassword{len(password)}
+	}
+
+	now := time.Now()
+
+	hashedPassword, err := s.hp.HashPassword([]byte(password))
+	if err != nil {
+		return fmt.Errorf("cannot hash password: %w", err)
+	}
+
+	return s.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+			if token.Data.InvitationID != gid.Nil {
+				if err := acceptInvitation(ctx, tx, token.Data.InvitationID, now); err != nil {
+					return err
+				}
+			}
+
+			user := &coredata.User{}
+
+			if err := user.LoadByEmail(ctx, tx, token.Data.Email); err != nil {
+				var errUserNotFound *coredata.ErrUserNotFound
+
+				if errors.As(err, &errUserNotFound) {
+					user = &coredata.User{
+						ID:                   gid.New(gid.NilTenant, coredata.UserEntityType),
+						EmailAddress:         token.Data.Email,
+						HashedPassword:       hashedPassword,
+						EmailAddressVerified: true,
+						FullName:             token.Data.FullName,
+						CreatedAt:            now,
+						UpdatedAt:            now,
+					}
+
+					if err := user.Insert(ctx, tx); err != nil {
+						return fmt.Errorf("cannot insert user: %w", err)
+					}
+				}
+			}
+
+			uo := coredata.UserOrganization{
+				UserID:         user.ID,
+				OrganizationID: token.Data.OrganizationID,
+				Role:           coredata.MembershipRoleMember,
+				CreatedAt:      now,
+			}
+
+			if err := uo.Insert(ctx, tx); err != nil {
+				return fmt.Errorf("cannot insert user organization: %w", err)
+			}
+
+			return nil
+		},
+	)
+}
+
+// acceptInvitation marks the invitation as accepted, refusing revoked,
+// already accepted or expired invitations.
+func acceptInvitation(
+	ctx context.Context,
+	conn pg.Conn,
+	invitationID gid.GID,
+	now time.Time,
+) error {
+	scope := coredata.NewScope(invitationID.TenantID())
+	invitation := &coredata.Invitation{}
+
+	if err := invitation.LoadByID(ctx, conn, scope, invitationID); err != nil {
+		return fmt.Errorf("cannot load invitation: %w", err)
+	}
+
+	switch invitation.Status {
+	case coredata.InvitationStatusRevoked:
+		return &ErrInvitationNotPending{message: "invitation has been revoked"}
+	case coredata.InvitationStatusAccepted:
+		return &ErrInvitationNotPending{message: "invitation has already been accepted"}
+	}
+
+	if invitation.Expired(now) {
+		return &ErrInvitationNotPending{message: "invitation has expired"}
+	}
+
+	invitation.Status = coredata.InvitationStatusAccepted
+	invitation.AcceptedAt = &now
+	invitation.UpdatedAt = now
+
+	if err := invitation.Update(ctx, conn, scope); err != nil {
+		return fmt.Errorf("cannot update invitation: %w", err)
+	}
+
+	return nil
+}
+
+func (s Service) sendInvitationEmail(
+	ctx context.Context,
+	conn pg.Conn,
+	invitation *coredata.Invitation,
+) error {
+	confirmationToken, err := statelesstoken.NewToken(
+		s.tokenKeyring,
+		TokenTypeOrganizationInvitation,
+		time.Until(invitation.ExpiresAt),
+		InvitationData{
+			InvitationID:   invitation.ID,
+			OrganizationID: invitation.OrganizationID,
+			Email:          invitation.EmailAddress,
+			FullName:       invitation.FullName,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("cannot generate confirmation token: %w", err)
+	}
+
+	confirmationInvitationUrl := url.URL{
+		Scheme: "https",
+		Host:   s.hostname,
+		Path:   "/confirm-invitation",
+		RawQuery: url.Values{
+			"token": []string{confirmationToken},
+		}.Encode(),
+	}
+
+	confirmationEmail := coredata.NewEmail(
+		invitation.FullName,
+		invitation.EmailAddress,
+		invitationEmailSubject,
+		fmt.Sprintf(invitationEmailTemplate, confirmationInvitationUrl.String()),
+	)
+
+	if err := confirmationEmail.Insert(ctx, conn); err != nil {
+		return fmt.Errorf("cannot insert email: %w", err)
+	}
+
+	return nil
+}
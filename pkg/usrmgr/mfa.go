@@ -0,0 +1,512 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package usrmgr
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/getprobo/probo/pkg/coredata"
+	"github.com/getprobo/probo/pkg/crypto/passwdhash"
+	"github.com/getprobo/probo/pkg/crypto/totp"
+	"github.com/getprobo/probo/pkg/gid"
+	"github.com/getprobo/probo/pkg/statelesstoken"
+	"go.gearno.de/kit/log"
+	"go.gearno.de/kit/pg"
+)
+
+type (
+	ErrMFARequired struct {
+		ChallengeToken string
+	}
+
+	ErrInvalidMFACode struct{}
+
+	ErrMFAAlreadyEnabled struct{}
+
+	ErrMFANotEnabled struct{}
+
+	MFAChallengeData struct {
+		UserID gid.GID `json:"uid"`
+		// ChallengeID identifies the challenge to count its attempts.
+		ChallengeID string `json:"cid"`
+	}
+
+	TOTPEnrollment struct {
+		Secret string
+		URI    string
+	}
+)
+
+const (
+	totpIssuer        = "Probo"
+	recoveryCodeCount = 10
+	// recoveryCodeLength is the length of a normalized recovery code.
+	recoveryCodeLength = 16
+)
+
+func (e ErrMFARequired) Error() string {
+	return "multi-factor authentication required"
+}
+
+func (e ErrInvalidMFACode) Error() string {
+	return "invalid multi-factor authentication code"
+}
+
+func (e ErrMFAAlreadyEnabled) Error() string {
+	return "multi-factor authentication is already enabled"
+}
+
+func (e ErrMFANotEnabled) Error() string {
+	return "multi-factor authentication is not enabled"
+}
+
+// VerifyMFAChallenge completes a sign in started with SignIn for a user
+// with multi-factor authentication enabled. The code is either a TOTP
+// code or one of the user recovery codes. Failures are throttled like
+// password failures and the challenge is invalidated after
+// mfaChallengeMaxAttempts attempts.
+func (s Service) VerifyMFAChallenge(
+	ctx context.Context,
+	challengeToken string,
+	code string,
+	ipAddress string,
+) (*coredata.User, *coredata.Session, error) {
+	token, err := statelesstoken.ValidateToken[MFAChallengeData](
+		s.tokenKeyring,
+		TokenTypeMFAChallenge,
+		challengeToken,
+	)
+	if err != nil || token.Data.ChallengeID == "" {
+		return nil, nil, &ErrInvalidCredentials{message: "invalid or expired mfa challenge"}
+	}
+
+	user, err := s.GetUserByID(ctx, token.Data.UserID)
+	if err != nil {
+		var errUserNotFound *coredata.ErrUserNotFound
+		if errors.As(err, &errUserNotFound) {
+			return nil, nil, &ErrInvalidCredentials{message: "invalid or expired mfa challenge"}
+		}
+
+		return nil, nil, fmt.Errorf("cannot load user: %w", err)
+	}
+
+	if err := s.checkSignInThrottles(ctx, user.EmailAddress, ipAddress); err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.recordMFAChallengeAttempt(ctx, token.Data.ChallengeID); err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	session := &coredata.Session{
+		ID:             gid.New(gid.NilTenant, coredata.SessionEntityType),
+		UserID:         user.ID,
+		LastActivityAt: now,
+		ExpiredAt:      now.Add(24 * time.Hour),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	err = s.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+			if err := user.LoadByID(ctx, tx, token.Data.UserID); err != nil {
+				return fmt.Errorf("cannot load user: %w", err)
+			}
+
+			if !user.MFAEnabled() {
+				return &ErrMFANotEnabled{}
+			}
+
+			if err := s.verifyMFACode(ctx, tx, user, code); err != nil {
+				return err
+			}
+
+			if err := session.Insert(ctx, tx); err != nil {
+				return fmt.Errorf("cannot insert session: %w", err)
+			}
+
+			return nil
+		},
+	)
+
+	var errInvalidMFACode *ErrInvalidMFACode
+
+	switch {
+	case err == nil:
+		// The sign in is only complete once the second factor is
+		// verified, the account failures are forgotten now.
+		if err := s.resetAccountSignInThrottle(ctx, user.EmailAddress); err != nil {
+			return nil, nil, err
+		}
+	case errors.As(err, &errInvalidMFACode):
+		if err := s.recordSignInFailure(ctx, user.EmailAddress, ipAddress); err != nil {
+			return nil, nil, fmt.Errorf("cannot record sign in failure: %w", err)
+		}
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, session, nil
+}
+
+// EnrollTOTP generates a new TOTP secret for the user. The secret is not
+// enforced until it is confirmed with ConfirmTOTP.
+func (s Service) EnrollTOTP(
+	ctx context.Context,
+	userID gid.GID,
+) (*TOTPEnrollment, error) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, fmt.Errorf("cannot generate totp secret: %w", err)
+	}
+
+	user := &coredata.User{}
+
+	err = s.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+			if err := user.LoadByID(ctx, tx, userID); err != nil {
+				return fmt.Errorf("cannot load user: %w", err)
+			}
+
+			if user.MFAEnabled() {
+				return &ErrMFAAlreadyEnabled{}
+			}
+
+			if err := user.UpdateTOTP(ctx, tx, &secret, nil); err != nil {
+				return fmt.Errorf("cannot update user totp: %w", err)
+			}
+
+			return nil
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &TOTPEnrollment{
+		Secret: secret,
+		URI:    totp.URI(totpIssuer, user.EmailAddress, secret),
+	}, nil
+}
+
+// ConfirmTOTP enables multi-factor authentication once the user proves
+// the authenticator is correctly configured, and returns the recovery
+// codes in clear text. They cannot be retrieved afterwards.
+func (s Service) ConfirmTOTP(
+	ctx context.Context,
+	userID gid.GID,
+	code string,
+) ([]string, error) {
+	var recoveryCodes []string
+
+	err := s.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+			user := &coredata.User{}
+			if err := user.LoadByID(ctx, tx, userID); err != nil {
+				return fmt.Errorf("cannot load user: %w", err)
+			}
+
+			if user.MFAEnabled() {
+				return &ErrMFAAlreadyEnabled{}
+			}
+
+			if user.TOTPSecret == nil {
+				return &ErrMFANotEnabled{}
+			}
+
+			counter, ok, err := totp.ValidateCounter(*user.TOTPSecret, code, time.Now())
+			if err != nil {
+				return fmt.Errorf("cannot validate totp code: %w", err)
+			}
+
+			if !ok {
+				return &ErrInvalidMFACode{}
+			}
+
+			if err := useTOTPCounter(ctx, tx, user, counter); err != nil {
+				return err
+			}
+
+			now := time.Now()
+			if err := user.UpdateTOTP(ctx, tx, user.TOTPSecret, &now); err != nil {
+				return fmt.Errorf("cannot update user totp: %w", err)
+			}
+
+			recoveryCodes, err = s.generateRecoveryCodes(ctx, tx, user.ID)
+			if err != nil {
+				return fmt.Errorf("cannot generate recovery codes: %w", err)
+			}
+
+			return nil
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
+
+// DisableTOTP turns off multi-factor authentication and deletes the
+// recovery codes of the user. The code is throttled, see
+// checkMFAVerificationThrottle.
+func (s Service) DisableTOTP(
+	ctx context.Context,
+	userID gid.GID,
+	code string,
+) error {
+	user, err := s.GetUserByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("cannot load user: %w", err)
+	}
+
+	if err := s.checkMFAVerificationThrottle(ctx, user); err != nil {
+		return err
+	}
+
+	err = s.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+			if err := user.LoadByID(ctx, tx, userID); err != nil {
+				return fmt.Errorf("cannot load user: %w", err)
+			}
+
+			if !user.MFAEnabled() {
+				return &ErrMFANotEnabled{}
+			}
+
+			if err := s.verifyMFACode(ctx, tx, user, code); err != nil {
+				return err
+			}
+
+			if err := user.UpdateTOTP(ctx, tx, nil, nil); err != nil {
+				return fmt.Errorf("cannot update user totp: %w", err)
+			}
+
+			if err := coredata.DeleteUserRecoveryCodes(ctx, tx, user.ID); err != nil {
+				return fmt.Errorf("cannot delete recovery codes: %w", err)
+			}
+
+			return nil
+		},
+	)
+
+	return s.recordMFAVerificationResult(ctx, user, err)
+}
+
+// RegenerateRecoveryCodes replaces all the recovery codes of the user,
+// including unused ones. The code is throttled, see
+// checkMFAVerificationThrottle.
+func (s Service) RegenerateRecoveryCodes(
+	ctx context.Context,
+	userID gid.GID,
+	code string,
+) ([]string, error) {
+	var recoveryCodes []string
+
+	user, err := s.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load user: %w", err)
+	}
+
+	if err := s.checkMFAVerificationThrottle(ctx, user); err != nil {
+		return nil, err
+	}
+
+	err = s.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+			if err := user.LoadByID(ctx, tx, userID); err != nil {
+				return fmt.Errorf("cannot load user: %w", err)
+			}
+
+			if !user.MFAEnabled() {
+				return &ErrMFANotEnabled{}
+			}
+
+			if err := s.verifyMFACode(ctx, tx, user, code); err != nil {
+				return err
+			}
+
+			var err error
+			recoveryCodes, err = s.generateRecoveryCodes(ctx, tx, user.ID)
+			if err != nil {
+				return fmt.Errorf("cannot generate recovery codes: %w", err)
+			}
+
+			return nil
+		},
+	)
+
+	if err := s.recordMFAVerificationResult(ctx, user, err); err != nil {
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
+
+func (s Service) verifyMFACode(
+	ctx context.Context,
+	conn pg.Conn,
+	user *coredata.User,
+	code string,
+) error {
+	counter, ok, err := totp.ValidateCounter(*user.TOTPSecret, code, time.Now())
+	if err != nil {
+		return fmt.Errorf("cannot validate totp code: %w", err)
+	}
+
+	if ok {
+		return useTOTPCounter(ctx, conn, user, counter)
+	}
+
+	// Comparing a recovery code costs one password hash per unused code,
+	// codes which cannot be recovery codes are rejected upfront.
+	normalizedCode := normalizeRecoveryCode(code)
+	if len(normalizedCode) != recoveryCodeLength {
+		return &ErrInvalidMFACode{}
+	}
+
+	recoveryCodes := coredata.RecoveryCodes{}
+	if err := recoveryCodes.LoadUnusedByUserID(ctx, conn, user.ID); err != nil {
+		return fmt.Errorf("cannot load recovery codes: %w", err)
+	}
+
+	for _, recoveryCode := range recoveryCodes {
+		ok, err := s.hp.ComparePasswordAndHash([]byte(normalizedCode), recoveryCode.HashedCode)
+		if errors.Is(err, passwdhash.ErrUnknownPepper) {
+			s.logger.WarnCtx(
+				ctx,
+				"cannot verify recovery code, the pepper of the hash is not configured",
+				log.String("user_id", user.ID.String()),
+			)
+
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("cannot compare recovery code: %w", err)
+		}
+
+		if ok {
+			if err := recoveryCode.MarkAsUsed(ctx, conn); err != nil {
+				return fmt.Errorf("cannot mark recovery code as used: %w", err)
+			}
+
+			return nil
+		}
+	}
+
+	return &ErrInvalidMFACode{}
+}
+
+// useTOTPCounter rejects a TOTP code already accepted once, an attacker
+// observing a code must not be able to replay it within its validity.
+func useTOTPCounter(
+	ctx context.Context,
+	conn pg.Conn,
+	user *coredata.User,
+	counter int64,
+) error {
+	if err := user.UseTOTPCounter(ctx, conn, counter); err != nil {
+		var errAlreadyUsed *coredata.ErrTOTPCounterAlreadyUsed
+		if errors.As(err, &errAlreadyUsed) {
+			return &ErrInvalidMFACode{}
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (s Service) generateRecoveryCodes(
+	ctx context.Context,
+	conn pg.Conn,
+	userID gid.GID,
+) ([]string, error) {
+	if err := coredata.DeleteUserRecoveryCodes(ctx, conn, userID); err != nil {
+		return nil, fmt.Errorf("cannot delete recovery codes: %w", err)
+	}
+
+	codes := make([]string, recoveryCodeCount)
+	now := time.Now()
+
+	for i := range codes {
+		code, err := newRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+
+		hashedCode, err := s.hp.HashPassword([]byte(normalizeRecoveryCode(code)))
+		if err != nil {
+			return nil, fmt.Errorf("cannot hash recovery code: %w", err)
+		}
+
+		recoveryCode := &coredata.RecoveryCode{
+			ID:         gid.New(gid.NilTenant, coredata.RecoveryCodeEntityType),
+			UserID:     userID,
+			HashedCode: hashedCode,
+			CreatedAt:  now,
+		}
+
+		if err := recoveryCode.Insert(ctx, conn); err != nil {
+			return nil, fmt.Errorf("cannot insert recovery code: %w", err)
+		}
+
+		codes[i] = code
+	}
+
+	return codes, nil
+}
+
+// newRecoveryCode returns a code formatted as four groups of four
+// characters (e.g. "abcd-efgh-ijkl-mnop") to ease manual input.
+func newRecoveryCode() (string, error) {
+	b := make([]byte, 10)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("cannot generate recovery code: %w", err)
+	}
+
+	encoded := strings.ToLower(base32.StdEncoding.EncodeToString(b))
+
+	groups := make([]string, 0, 4)
+	for i := 0; i < len(encoded); i += 4 {
+		groups = append(groups, encoded[i:i+4])
+	}
+
+	return strings.Join(groups, "-"), nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.ReplaceAll(code, "-", "")
+	code = strings.ReplaceAll(code, " ", "")
+
+	return code
+}
@@ -0,0 +1,261 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package usrmgr
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/getprobo/probo/pkg/coredata"
+	"github.com/getprobo/probo/pkg/gid"
+	"github.com/getprobo/probo/pkg/statelesstoken"
+	"go.gearno.de/kit/pg"
+	"golang.org/x/oauth2"
+)
+
+type (
+	ErrOIDCNotConfigured struct {
+		organizationID gid.GID
+	}
+
+	ErrInvalidOIDCResponse struct {
+		message string
+	}
+
+	// OIDCLoginData is the state of an authorization code flow. It is kept
+	// client side in a signed cookie between the redirection to the
+	// identity provider and the callback, so the PKCE verifier never
+	// leaves the browser of the user who started the flow.
+	OIDCLoginData struct {
+		OrganizationID gid.GID `json:"oid"`
+		State          string  `json:"state"`
+		Nonce          string  `json:"nonce"`
+		CodeVerifier   string  `json:"verifier"`
+	}
+
+	oidcClaims struct {
+		Email         string `json:"email"`
+		EmailVerified *bool  `json:"email_verified"`
+		Name          string `json:"name"`
+	}
+)
+
+const (
+	oidcLoginTimeout = 10 * time.Minute
+	oidcCallbackPath = "/api/console/v1/auth/oidc/callback"
+)
+
+func (e ErrOIDCNotConfigured) Error() string {
+	return fmt.Sprintf("single sign-on is not configured for organization %q", e.organizationID)
+}
+
+func (e ErrInvalidOIDCResponse) Error() string {
+	return e.message
+}
+
+// OIDCRedirectURL returns the callback URL to register in the identity
+// provider of an organization.
+func (s Service) OIDCRedirectURL() string {
+	return s.ssoURL(oidcCallbackPath)
+}
+
+// BeginOIDCLogin starts an authorization code flow with PKCE against the
+// identity provider of the organization. It returns the URL to redirect
+// the user to and a signed token holding the flow state, which must be
+// given back to FinishOIDCLogin.
+func (s Service) BeginOIDCLogin(
+	ctx context.Context,
+	organizationID gid.GID,
+) (string, string, error) {
+	configuration, err := s.loadOIDCConfiguration(ctx, organizationID)
+	if err != nil {
+		return "", "", err
+	}
+
+	provider, err := oidc.NewProvider(ctx, configuration.IssuerURL)
+	if err != nil {
+		return "", "", fmt.Errorf("cannot discover oidc provider: %w", err)
+	}
+
+	state, err := randomOIDCValue()
+	if err != nil {
+		return "", "", fmt.Errorf("cannot generate state: %w", err)
+	}
+
+	nonce, err := randomOIDCValue()
+	if err != nil {
+		return "", "", fmt.Errorf("cannot generate nonce: %w", err)
+	}
+
+	verifier := oauth2.GenerateVerifier()
+
+	loginToken, err := statelesstoken.NewToken(
+		s.tokenKeyring,
+		TokenTypeOIDCLogin,
+		oidcLoginTimeout,
+		OIDCLoginData{
+			OrganizationID: organizationID,
+			State:          state,
+			Nonce:          nonce,
+			CodeVerifier:   verifier,
+		},
+	)
+	if err != nil {
+		return "", "", fmt.Errorf("cannot generate oidc login token: %w", err)
+	}
+
+	authURL := s.oauth2Config(configuration, provider).AuthCodeURL(
+		state,
+		oidc.Nonce(nonce),
+		oauth2.S256ChallengeOption(verifier),
+	)
+
+	return authURL, loginToken, nil
+}
+
+// FinishOIDCLogin exchanges the authorization code returned by the
+// identity provider, validates the ID token and opens a session. Users
+// who do not exist yet are created and users who are not yet members of
+// the organization are enrolled in it.
+func (s Service) FinishOIDCLogin(
+	ctx context.Context,
+	loginToken string,
+	state string,
+	code string,
+) (*coredata.User, *coredata.Session, error) {
+	token, err := statelesstoken.ValidateToken[OIDCLoginData](
+		s.tokenKeyring,
+		TokenTypeOIDCLogin,
+		loginToken,
+	)
+	if err != nil {
+		return nil, nil, &ErrInvalidOIDCResponse{message: "invalid or expired login flow"}
+	}
+
+	if state == "" || state != token.Data.State {
+		return nil, nil, &ErrInvalidOIDCResponse{message: "state mismatch"}
+	}
+
+	configuration, err := s.loadOIDCConfiguration(ctx, token.Data.OrganizationID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	provider, err := oidc.NewProvider(ctx, configuration.IssuerURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot discover oidc provider: %w", err)
+	}
+
+	oauth2Token, err := s.oauth2Config(configuration, provider).Exchange(
+		ctx,
+		code,
+		oauth2.VerifierOption(token.Data.CodeVerifier),
+	)
+	if err != nil {
+		return nil, nil, &ErrInvalidOIDCResponse{message: fmt.Sprintf("cannot exchange authorization code: %s", err)}
+	}
+
+	rawIDToken, ok := oauth2Token.Extra("id_token").(string)
+	if !ok {
+		return nil, nil, &ErrInvalidOIDCResponse{message: "missing id token"}
+	}
+
+	idToken, err := provider.Verifier(&oidc.Config{ClientID: configuration.ClientID}).Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, nil, &ErrInvalidOIDCResponse{message: fmt.Sprintf("invalid id token: %s", err)}
+	}
+
+	if idToken.Nonce != token.Data.Nonce {
+		return nil, nil, &ErrInvalidOIDCResponse{message: "nonce mismatch"}
+	}
+
+	var claims oidcClaims
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, nil, &ErrInvalidOIDCResponse{message: fmt.Sprintf("cannot decode id token claims: %s", err)}
+	}
+
+	if claims.EmailVerified != nil && !*claims.EmailVerified {
+		return nil, nil, &ErrInvalidOIDCResponse{message: "email address is not verified by the identity provider"}
+	}
+
+	email := strings.ToLower(claims.Email)
+	if !emailDomainAllowed(email, configuration.AllowedEmailDomains) {
+		return nil, nil, &ErrEmailDomainNotAllowed{email: claims.Email}
+	}
+
+	fullName := claims.Name
+	if fullName == "" {
+		fullName = email
+	}
+
+	return s.signInFederatedUser(ctx, configuration.OrganizationID, email, fullName)
+}
+
+func (s Service) loadOIDCConfiguration(
+	ctx context.Context,
+	organizationID gid.GID,
+) (*coredata.OIDCConfiguration, error) {
+	configuration := &coredata.OIDCConfiguration{}
+
+	err := s.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			return configuration.LoadByOrganizationID(ctx, conn, coredata.NewNoScope(), organizationID)
+		},
+	)
+
+	if err != nil {
+		var errNotFound *coredata.ErrOIDCConfigurationNotFound
+		if errors.As(err, &errNotFound) {
+			return nil, &ErrOIDCNotConfigured{organizationID: organizationID}
+		}
+
+		return nil, fmt.Errorf("cannot load oidc configuration: %w", err)
+	}
+
+	if !configuration.Enabled {
+		return nil, &ErrOIDCNotConfigured{organizationID: organizationID}
+	}
+
+	return configuration, nil
+}
+
+func (s Service) oauth2Config(
+	configuration *coredata.OIDCConfiguration,
+	provider *oidc.Provider,
+) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     configuration.ClientID,
+		ClientSecret: configuration.ClientSecret,
+		Endpoint:     provider.Endpoint(),
+		RedirectURL:  s.OIDCRedirectURL(),
+		Scopes:       []string{oidc.ScopeOpenID, "email", "profile"},
+	}
+}
+
+func randomOIDCValue() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,298 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package usrmgr
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/getprobo/probo/pkg/coredata"
+	"github.com/getprobo/probo/pkg/gid"
+	"go.gearno.de/kit/pg"
+)
+
+type (
+	// Permission is an action a member of an organization may be allowed
+	// to perform depending on its role.
+	Permission uint8
+
+	ErrLastOwner struct {
+		message string
+	}
+
+	ErrPermissionDenied struct {
+		message string
+	}
+)
+
+const (
+	// PermissionRead allows reading the compliance data of the
+	// organization.
+	PermissionRead Permission = iota
+	// PermissionWrite allows creating and updating compliance data.
+	PermissionWrite
+	// PermissionDelete allows deleting compliance data, including
+	// evidences.
+	PermissionDelete
+	// PermissionManageMembers allows inviting and removing members and
+	// changing their role.
+	PermissionManageMembers
+	// PermissionManageOrganization allows updating the organization and
+	// its settings such as single sign-on, provisioning and API tokens.
+	PermissionManageOrganization
+	// PermissionDeleteOrganization allows deleting the organization.
+	PermissionDeleteOrganization
+	// PermissionManageEmailDomains allows claiming email domains and
+	// letting their users join the organization automatically.
+	PermissionManageEmailDomains
+)
+
+var (
+	// Auditors have the same read-only access as viewers, the role tells
+	// external auditors apart from the members of the organization.
+	rolePermissions = map[coredata.MembershipRole][]Permission{
+		coredata.MembershipRoleOwner: {
+			PermissionRead,
+			PermissionWrite,
+			PermissionDelete,
+			PermissionManageMembers,
+			PermissionManageOrganization,
+			PermissionDeleteOrganization,
+			PermissionManageEmailDomains,
+		},
+		coredata.MembershipRoleAdmin: {
+			PermissionRead,
+			PermissionWrite,
+			PermissionDelete,
+			PermissionManageMembers,
+			PermissionManageOrganization,
+		},
+		coredata.MembershipRoleMember: {
+			PermissionRead,
+			PermissionWrite,
+		},
+		coredata.MembershipRoleViewer: {
+			PermissionRead,
+		},
+		coredata.MembershipRoleAuditor: {
+			PermissionRead,
+		},
+	}
+)
+
+func (e ErrLastOwner) Error() string {
+	return e.message
+}
+
+func (e ErrPermissionDenied) Error() string {
+	return e.message
+}
+
+// RoleHasPermission reports whether members with the role are allowed the
+// permission.
+func RoleHasPermission(role coredata.MembershipRole, permission Permission) bool {
+	return slices.Contains(rolePermissions[role], permission)
+}
+
+// ListTenantRolesForUserID returns the role of the user in the
+// organization of each of its tenants.
+func (s Service) ListTenantRolesForUserID(
+	ctx context.Context,
+	userID gid.GID,
+) (map[gid.TenantID]coredata.MembershipRole, error) {
+	uos := coredata.UserOrganizations{}
+
+	err := s.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			return uos.ForUserID(ctx, conn, userID)
+		},
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot list user organizations: %w", err)
+	}
+
+	roles := make(map[gid.TenantID]coredata.MembershipRole, len(uos))
+	for _, uo := range uos {
+		roles[uo.OrganizationID.TenantID()] = uo.Role
+	}
+
+	return roles, nil
+}
+
+func (s Service) ListMemberships(
+	ctx context.Context,
+	organizationID gid.GID,
+) (coredata.UserOrganizations, error) {
+	uos := coredata.UserOrganizations{}
+
+	err := s.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			return uos.ForOrganizationID(ctx, conn, organizationID)
+		},
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot list memberships: %w", err)
+	}
+
+	return uos, nil
+}
+
+// ChangeMemberRole changes the role of a member of the organization. Only
+// owners can grant or revoke the owner role and the last owner cannot be
+// demoted.
+func (s Service) ChangeMemberRole(
+	ctx context.Context,
+	actorID gid.GID,
+	organizationID gid.GID,
+	userID gid.GID,
+	role coredata.MembershipRole,
+) (*coredata.UserOrganization, error) {
+	membership := &coredata.UserOrganization{}
+
+	err := s.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+			actor := &coredata.UserOrganization{}
+			if err := actor.LoadByUserIDAndOrganizationID(ctx, tx, actorID, organizationID); err != nil {
+				return fmt.Errorf("cannot load actor membership: %w", err)
+			}
+
+			if !RoleHasPermission(actor.Role, PermissionManageMembers) {
+				return &ErrPermissionDenied{message: "not allowed to manage members"}
+			}
+
+			if err := membership.LoadByUserIDAndOrganizationID(ctx, tx, userID, organizationID); err != nil {
+				return fmt.Errorf("cannot load membership: %w", err)
+			}
+
+			if membership.Role == role {
+				return nil
+			}
+
+			if (membership.Role == coredata.MembershipRoleOwner || role == coredata.MembershipRoleOwner) &&
+				actor.Role != coredata.MembershipRoleOwner {
+				return &ErrPermissionDenied{message: "only owners can grant or revoke the owner role"}
+			}
+
+			if membership.Role == coredata.MembershipRoleOwner {
+				if err := ensureNotLastOwner(ctx, tx, organizationID); err != nil {
+					return err
+				}
+			}
+
+			membership.Role = role
+			if err := membership.UpdateRole(ctx, tx); err != nil {
+				return fmt.Errorf("cannot update membership role: %w", err)
+			}
+
+			return nil
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return membership, nil
+}
+
+// RemoveUser removes a member from the organization. Only owners can
+// remove owners and the last owner cannot be removed. When revokeSessions
+// is set, the user is also signed out everywhere so an already open
+// session cannot outlive the removal.
+func (s Service) RemoveUser(
+	ctx context.Context,
+	actorID gid.GID,
+	organizationID gid.GID,
+	userID gid.GID,
+	revokeSessions bool,
+) error {
+	return s.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+			actor := &coredata.UserOrganization{}
+			if err := actor.LoadByUserIDAndOrganizationID(ctx, tx, actorID, organizationID); err != nil {
+				return fmt.Errorf("cannot load actor membership: %w", err)
+			}
+
+			if !RoleHasPermission(actor.Role, PermissionManageMembers) {
+				return &ErrPermissionDenied{message: "not allowed to manage members"}
+			}
+
+			uo := &coredata.UserOrganization{}
+			if err := uo.LoadByUserIDAndOrganizationID(ctx, tx, userID, organizationID); err != nil {
+				return fmt.Errorf("cannot load membership: %w", err)
+			}
+
+			if uo.Role == coredata.MembershipRoleOwner && actor.Role != coredata.MembershipRoleOwner {
+				return &ErrPermissionDenied{message: "only owners can remove owners"}
+			}
+
+			return removeMember(ctx, tx, uo, revokeSessions)
+		},
+	)
+}
+
+// removeMember deletes the membership, refusing to remove the last owner
+// of the organization, and revokes the API tokens of the user for the
+// organization. Sessions are not bound to an organization, they are only
+// revoked when revokeSessions is set. The membership must have been
+// loaded with LoadByUserIDAndOrganizationID in the same transaction.
+func removeMember(
+	ctx context.Context,
+	conn pg.Conn,
+	uo *coredata.UserOrganization,
+	revokeSessions bool,
+) error {
+	if uo.Role == coredata.MembershipRoleOwner {
+		if err := ensureNotLastOwner(ctx, conn, uo.OrganizationID); err != nil {
+			return err
+		}
+	}
+
+	if err := uo.Delete(ctx, conn); err != nil {
+		return fmt.Errorf("cannot delete user organization: %w", err)
+	}
+
+	if err := coredata.DeleteUserOrganizationAPITokens(ctx, conn, uo.UserID, uo.OrganizationID); err != nil {
+		return fmt.Errorf("cannot delete user api tokens: %w", err)
+	}
+
+	if revokeSessions {
+		if err := coredata.DeleteUserSessions(ctx, conn, uo.UserID); err != nil {
+			return fmt.Errorf("cannot delete user sessions: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func ensureNotLastOwner(ctx context.Context, conn pg.Conn, organizationID gid.GID) error {
+	owners, err := coredata.UserOrganizations{}.CountOwnersForUpdate(ctx, conn, organizationID)
+	if err != nil {
+		return fmt.Errorf("cannot count owners: %w", err)
+	}
+
+	if owners <= 1 {
+		return &ErrLastOwner{message: "the organization must keep at least one owner"}
+	}
+
+	return nil
+}
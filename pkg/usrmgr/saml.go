@@ -0,0 +1,356 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package usrmgr
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/crewjam/saml"
+	"github.com/getprobo/probo/pkg/coredata"
+	"github.com/getprobo/probo/pkg/gid"
+	"github.com/getprobo/probo/pkg/statelesstoken"
+	"go.gearno.de/kit/pg"
+)
+
+type (
+	ErrSAMLNotConfigured struct {
+		organizationID gid.GID
+	}
+
+	ErrInvalidSAMLResponse struct {
+		message string
+	}
+
+	// SAMLLoginData is the state of a service provider initiated login.
+	// It is kept client side in a signed cookie so the assertion can be
+	// bound to the authentication request it answers.
+	SAMLLoginData struct {
+		OrganizationID gid.GID `json:"oid"`
+		RequestID      string  `json:"rid"`
+	}
+)
+
+const (
+	samlLoginTimeout = 10 * time.Minute
+)
+
+func (e ErrSAMLNotConfigured) Error() string {
+	return fmt.Sprintf("saml single sign-on is not configured for organization %q", e.organizationID)
+}
+
+func (e ErrInvalidSAMLResponse) Error() string {
+	return e.message
+}
+
+// SAMLMetadataURL returns the URL of the service provider metadata of the
+// organization, it is also used as service provider entity ID.
+func (s Service) SAMLMetadataURL(organizationID gid.GID) string {
+	return s.ssoURL(fmt.Sprintf("/api/console/v1/auth/saml/%s/metadata", organizationID))
+}
+
+// SAMLConsumerURL returns the assertion consumer service URL of the
+// organization.
+func (s Service) SAMLConsumerURL(organizationID gid.GID) string {
+	return s.ssoURL(fmt.Sprintf("/api/console/v1/auth/saml/%s/acs", organizationID))
+}
+
+// GetSAMLServiceProviderMetadata returns the service provider metadata
+// document to import in the identity provider of the organization.
+func (s Service) GetSAMLServiceProviderMetadata(
+	ctx context.Context,
+	organizationID gid.GID,
+) ([]byte, error) {
+	configuration, err := s.loadSAMLConfiguration(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	sp, err := s.samlServiceProvider(configuration)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := xml.MarshalIndent(sp.Metadata(), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal service provider metadata: %w", err)
+	}
+
+	return metadata, nil
+}
+
+// BeginSAMLLogin creates an authentication request for the identity
+// provider of the organization. It returns the URL to redirect the user
+// to, using the HTTP-Redirect binding, and a signed token holding the
+// request ID, which must be given back to FinishSAMLLogin.
+func (s Service) BeginSAMLLogin(
+	ctx context.Context,
+	organizationID gid.GID,
+) (string, string, error) {
+	configuration, err := s.loadSAMLConfiguration(ctx, organizationID)
+	if err != nil {
+		return "", "", err
+	}
+
+	sp, err := s.samlServiceProvider(configuration)
+	if err != nil {
+		return "", "", err
+	}
+
+	ssoURL := sp.GetSSOBindingLocation(saml.HTTPRedirectBinding)
+	if ssoURL == "" {
+		return "", "", fmt.Errorf("identity provider does not support the http-redirect binding")
+	}
+
+	authnRequest, err := sp.MakeAuthenticationRequest(ssoURL, saml.HTTPRedirectBinding, saml.HTTPPostBinding)
+	if err != nil {
+		return "", "", fmt.Errorf("cannot create authentication request: %w", err)
+	}
+
+	redirectURL, err := authnRequest.Redirect("", sp)
+	if err != nil {
+		return "", "", fmt.Errorf("cannot create authentication request url: %w", err)
+	}
+
+	loginToken, err := statelesstoken.NewToken(
+		s.tokenKeyring,
+		TokenTypeSAMLLogin,
+		samlLoginTimeout,
+		SAMLLoginData{
+			OrganizationID: organizationID,
+			RequestID:      authnRequest.ID,
+		},
+	)
+	if err != nil {
+		return "", "", fmt.Errorf("cannot generate saml login token: %w", err)
+	}
+
+	return redirectURL.String(), loginToken, nil
+}
+
+// FinishSAMLLogin validates the signed response posted by the identity
+// provider to the assertion consumer service and opens a session, the
+// same way SignIn does. Each assertion is accepted once. Users are
+// created and enrolled in the organization just in time when needed.
+func (s Service) FinishSAMLLogin(
+	ctx context.Context,
+	organizationID gid.GID,
+	loginToken string,
+	samlResponse string,
+) (*coredata.User, *coredata.Session, error) {
+	token, err := statelesstoken.ValidateToken[SAMLLoginData](
+		s.tokenKeyring,
+		TokenTypeSAMLLogin,
+		loginToken,
+	)
+	if err != nil {
+		return nil, nil, &ErrInvalidSAMLResponse{message: "invalid or expired login flow"}
+	}
+
+	if token.Data.OrganizationID != organizationID {
+		return nil, nil, &ErrInvalidSAMLResponse{message: "login flow does not belong to organization"}
+	}
+
+	configuration, err := s.loadSAMLConfiguration(ctx, organizationID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sp, err := s.samlServiceProvider(configuration)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rawResponse, err := base64.StdEncoding.DecodeString(samlResponse)
+	if err != nil {
+		return nil, nil, &ErrInvalidSAMLResponse{message: "cannot decode saml response"}
+	}
+
+	assertion, err := sp.ParseXMLResponse(rawResponse, []string{token.Data.RequestID})
+	if err != nil {
+		return nil, nil, &ErrInvalidSAMLResponse{message: "invalid saml response"}
+	}
+
+	if err := s.consumeSAMLAssertion(ctx, organizationID, assertion); err != nil {
+		return nil, nil, err
+	}
+
+	email := strings.ToLower(samlAttributeValue(assertion, configuration.EmailAttribute))
+	if email == "" && assertion.Subject != nil && assertion.Subject.NameID != nil &&
+		strings.Contains(assertion.Subject.NameID.Value, "@") {
+		email = strings.ToLower(assertion.Subject.NameID.Value)
+	}
+
+	if email == "" {
+		return nil, nil, &ErrInvalidSAMLResponse{message: "saml assertion does not contain an email address"}
+	}
+
+	if !emailDomainAllowed(email, configuration.AllowedEmailDomains) {
+		return nil, nil, &ErrEmailDomainNotAllowed{email: email}
+	}
+
+	fullName := samlAttributeValue(assertion, configuration.FullNameAttribute)
+	if fullName == "" {
+		fullName = email
+	}
+
+	return s.signInFederatedUser(ctx, organizationID, email, fullName)
+}
+
+// consumeSAMLAssertion records the assertion as used until it expires, so
+// a captured response cannot be posted again to open another session.
+func (s Service) consumeSAMLAssertion(
+	ctx context.Context,
+	organizationID gid.GID,
+	assertion *saml.Assertion,
+) error {
+	if assertion.ID == "" {
+		return &ErrInvalidSAMLResponse{message: "saml assertion does not have an id"}
+	}
+
+	now := time.Now()
+	usedAssertion := coredata.UsedSAMLAssertion{
+		OrganizationID: organizationID,
+		AssertionID:    assertion.ID,
+		ExpiresAt:      samlAssertionExpiresAt(assertion, now).Add(saml.MaxClockSkew),
+		CreatedAt:      now,
+	}
+
+	return s.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+			if err := coredata.DeleteExpiredUsedSAMLAssertions(ctx, tx, now); err != nil {
+				return err
+			}
+
+			if err := usedAssertion.Insert(ctx, tx); err != nil {
+				var errAlreadyUsed *coredata.ErrSAMLAssertionAlreadyUsed
+				if errors.As(err, &errAlreadyUsed) {
+					return &ErrInvalidSAMLResponse{message: "saml assertion was already used"}
+				}
+
+				return err
+			}
+
+			return nil
+		},
+	)
+}
+
+// samlAssertionExpiresAt returns the latest time the assertion is
+// accepted at, falling back to the login flow timeout when the identity
+// provider does not bound it.
+func samlAssertionExpiresAt(assertion *saml.Assertion, now time.Time) time.Time {
+	var expiresAt time.Time
+
+	if assertion.Conditions != nil && assertion.Conditions.NotOnOrAfter.After(expiresAt) {
+		expiresAt = assertion.Conditions.NotOnOrAfter
+	}
+
+	if assertion.Subject != nil {
+		for _, confirmation := range assertion.Subject.SubjectConfirmations {
+			data := confirmation.SubjectConfirmationData
+			if data != nil && data.NotOnOrAfter.After(expiresAt) {
+				expiresAt = data.NotOnOrAfter
+			}
+		}
+	}
+
+	if expiresAt.IsZero() {
+		expiresAt = now.Add(samlLoginTimeout)
+	}
+
+	return expiresAt
+}
+
+func (s Service) loadSAMLConfiguration(
+	ctx context.Context,
+	organizationID gid.GID,
+) (*coredata.SAMLConfiguration, error) {
+	configuration := &coredata.SAMLConfiguration{}
+
+	err := s.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			return configuration.LoadByOrganizationID(ctx, conn, coredata.NewNoScope(), organizationID)
+		},
+	)
+
+	if err != nil {
+		var errNotFound *coredata.ErrSAMLConfigurationNotFound
+		if errors.As(err, &errNotFound) {
+			return nil, &ErrSAMLNotConfigured{organizationID: organizationID}
+		}
+
+		return nil, fmt.Errorf("cannot load saml configuration: %w", err)
+	}
+
+	if !configuration.Enabled {
+		return nil, &ErrSAMLNotConfigured{organizationID: organizationID}
+	}
+
+	return configuration, nil
+}
+
+func (s Service) samlServiceProvider(
+	configuration *coredata.SAMLConfiguration,
+) (*saml.ServiceProvider, error) {
+	var idpMetadata saml.EntityDescriptor
+	if err := xml.Unmarshal([]byte(configuration.IdPMetadata), &idpMetadata); err != nil {
+		return nil, fmt.Errorf("cannot parse identity provider metadata: %w", err)
+	}
+
+	metadataURL, err := url.Parse(s.SAMLMetadataURL(configuration.OrganizationID))
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse metadata url: %w", err)
+	}
+
+	acsURL, err := url.Parse(s.SAMLConsumerURL(configuration.OrganizationID))
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse acs url: %w", err)
+	}
+
+	return &saml.ServiceProvider{
+		EntityID:          metadataURL.String(),
+		MetadataURL:       *metadataURL,
+		AcsURL:            *acsURL,
+		IDPMetadata:       &idpMetadata,
+		AuthnNameIDFormat: saml.UnspecifiedNameIDFormat,
+	}, nil
+}
+
+func samlAttributeValue(assertion *saml.Assertion, name string) string {
+	for _, statement := range assertion.AttributeStatements {
+		for _, attribute := range statement.Attributes {
+			if attribute.Name != name && attribute.FriendlyName != name {
+				continue
+			}
+
+			for _, value := range attribute.Values {
+				if v := strings.TrimSpace(value.Value); v != "" {
+					return v
+				}
+			}
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,973 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package usrmgr
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/getprobo/probo/pkg/coredata"
+	"github.com/getprobo/probo/pkg/gid"
+	"go.gearno.de/kit/pg"
+)
+
+type (
+	// SCIMUserRequest holds the attributes of a user the identity provider
+	// of an organization provisions.
+	SCIMUserRequest struct {
+		UserName   string
+		ExternalID *string
+		FullName   string
+		Kind       coredata.PeopleKind
+		Active     bool
+	}
+
+	SCIMGroupRequest struct {
+		DisplayName string
+		ExternalID  *string
+		MemberIDs   []gid.GID
+	}
+
+	ProvisionedUser struct {
+		SCIMUser *coredata.SCIMUser
+		People   *coredata.People
+	}
+
+	ProvisionedGroup struct {
+		SCIMGroup *coredata.SCIMGroup
+		MemberIDs []gid.GID
+	}
+
+	ErrInvalidSCIMToken struct {
+		message string
+	}
+
+	ErrSCIMUserAlreadyExists struct {
+		userName string
+	}
+
+	ErrSCIMGroupAlreadyExists struct {
+		displayName string
+	}
+
+	ErrInvalidDisplayName struct {
+		displayName string
+	}
+
+	ErrSCIMUserNotProvisionable struct {
+		message string
+	}
+)
+
+const (
+	scimTokenPrefix = "probo_scim_"
+)
+
+func (e ErrInvalidSCIMToken) Error() string {
+	return e.message
+}
+
+func (e ErrSCIMUserAlreadyExists) Error() string {
+	return fmt.Sprintf("user %q is already provisioned", e.userName)
+}
+
+func (e ErrSCIMGroupAlreadyExists) Error() string {
+	return fmt.Sprintf("group %q already exists", e.displayName)
+}
+
+func (e ErrInvalidDisplayName) Error() string {
+	return fmt.Sprintf("invalid display name: %q", e.displayName)
+}
+
+func (e ErrSCIMUserNotProvisionable) Error() string {
+	return e.message
+}
+
+// SCIMBaseURL returns the URL identity providers are configured with to
+// provision users.
+func (s Service) SCIMBaseURL() string {
+	return s.ssoURL("/api/scim/v2")
+}
+
+func hashSCIMToken(token string) []byte {
+	hash := sha256.Sum256([]byte(token))
+	return hash[:]
+}
+
+func (s Service) GetSCIMConfiguration(
+	ctx context.Context,
+	organizationID gid.GID,
+) (*coredata.SCIMConfiguration, error) {
+	configuration := &coredata.SCIMConfiguration{}
+	scope := coredata.NewScope(organizationID.TenantID())
+
+	err := s.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			return configuration.LoadByOrganizationID(ctx, conn, scope, organizationID)
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return configuration, nil
+}
+
+// GenerateSCIMToken issues the bearer token the identity provider of the
+// organization uses to call the SCIM API, replacing any previous one.
+// Only a digest of the token is stored, so it is returned once here.
+func (s Service) GenerateSCIMToken(
+	ctx context.Context,
+	organizationID gid.GID,
+) (*coredata.SCIMConfiguration, string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", fmt.Errorf("cannot generate scim token: %w", err)
+	}
+
+	token := scimTokenPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	now := time.Now()
+	scope := coredata.NewScope(organizationID.TenantID())
+
+	configuration := &coredata.SCIMConfiguration{
+		ID:             gid.New(organizationID.TenantID(), coredata.SCIMConfigurationEntityType),
+		TenantID:       organizationID.TenantID(),
+		OrganizationID: organizationID,
+		HashedToken:    hashSCIMToken(token),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	err := s.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+			organization := &coredata.Organization{}
+			if err := organization.LoadByID(ctx, tx, scope, organizationID); err != nil {
+				return fmt.Errorf("cannot load organization: %w", err)
+			}
+
+			if err := configuration.Upsert(ctx, tx); err != nil {
+				return fmt.Errorf("cannot upsert scim configuration: %w", err)
+			}
+
+			return nil
+		},
+	)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	return configuration, token, nil
+}
+
+func (s Service) DeleteSCIMConfiguration(
+	ctx context.Context,
+	organizationID gid.GID,
+) (*coredata.SCIMConfiguration, error) {
+	configuration := &coredata.SCIMConfiguration{}
+	scope := coredata.NewScope(organizationID.TenantID())
+
+	err := s.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+			if err := configuration.LoadByOrganizationID(ctx, tx, scope, organizationID); err != nil {
+				return fmt.Errorf("cannot load scim configuration: %w", err)
+			}
+
+			if err := configuration.Delete(ctx, tx, scope); err != nil {
+				return fmt.Errorf("cannot delete scim configuration: %w", err)
+			}
+
+			return nil
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return configuration, nil
+}
+
+// AuthenticateSCIMToken resolves the SCIM configuration a bearer token
+// was issued for.
+func (s Service) AuthenticateSCIMToken(
+	ctx context.Context,
+	token string,
+) (*coredata.SCIMConfiguration, error) {
+	if !strings.HasPrefix(token, scimTokenPrefix) {
+		return nil, &ErrInvalidSCIMToken{message: "invalid scim token"}
+	}
+
+	configuration := &coredata.SCIMConfiguration{}
+
+	err := s.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			if err := configuration.LoadByHashedToken(ctx, conn, hashSCIMToken(token)); err != nil {
+				var errNotFound *coredata.ErrSCIMConfigurationNotFound
+				if errors.As(err, &errNotFound) {
+					return &ErrInvalidSCIMToken{message: "invalid scim token"}
+				}
+
+				return fmt.Errorf("cannot load scim configuration: %w", err)
+			}
+
+			if err := configuration.UpdateLastUsedAt(ctx, conn, time.Now()); err != nil {
+				return fmt.Errorf("cannot update scim configuration: %w", err)
+			}
+
+			return nil
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return configuration, nil
+}
+
+func (s Service) ListSCIMUsers(
+	ctx context.Context,
+	configuration *coredata.SCIMConfiguration,
+	filter coredata.SCIMUserFilter,
+	offset int,
+	limit int,
+) ([]*ProvisionedUser, int, error) {
+	var (
+		scope     = coredata.NewScope(configuration.TenantID)
+		scimUsers coredata.SCIMUsers
+		users     []*ProvisionedUser
+		total     int
+	)
+
+	err := s.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			var err error
+			total, err = scimUsers.CountByOrganizationID(ctx, conn, scope, configuration.OrganizationID, filter)
+			if err != nil {
+				return fmt.Errorf("cannot count scim users: %w", err)
+			}
+
+			if err := scimUsers.LoadByOrganizationID(ctx, conn, scope, configuration.OrganizationID, filter, offset, limit); err != nil {
+				return fmt.Errorf("cannot load scim users: %w", err)
+			}
+
+			for _, scimUser := range scimUsers {
+				people := &coredata.People{}
+				if err := people.LoadByID(ctx, conn, scope, scimUser.PeopleID); err != nil {
+					return fmt.Errorf("cannot load people: %w", err)
+				}
+
+				users = append(users, &ProvisionedUser{SCIMUser: scimUser, People: people})
+			}
+
+			return nil
+		},
+	)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+func (s Service) GetSCIMUser(
+	ctx context.Context,
+	configuration *coredata.SCIMConfiguration,
+	scimUserID gid.GID,
+) (*ProvisionedUser, error) {
+	scope := coredata.NewScope(configuration.TenantID)
+	scimUser := &coredata.SCIMUser{}
+	people := &coredata.People{}
+
+	err := s.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			if err := scimUser.LoadByID(ctx, conn, scope, configuration.OrganizationID, scimUserID); err != nil {
+				return fmt.Errorf("cannot load scim user: %w", err)
+			}
+
+			if err := people.LoadByID(ctx, conn, scope, scimUser.PeopleID); err != nil {
+				return fmt.Errorf("cannot load people: %w", err)
+			}
+
+			return nil
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProvisionedUser{SCIMUser: scimUser, People: people}, nil
+}
+
+// CreateSCIMUser provisions a user in the organization. The user account
+// and the people record are reused when they already exist for the email
+// address, otherwise they are created, see loadOrCreateProvisionedUser.
+func (s Service) CreateSCIMUser(
+	ctx context.Context,
+	configuration *coredata.SCIMConfiguration,
+	req SCIMUserRequest,
+) (*ProvisionedUser, error) {
+	email, err := normalizeSCIMUserName(req.UserName)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	scope := coredata.NewScope(configuration.TenantID)
+	scimUser := &coredata.SCIMUser{
+		ID:             gid.New(configuration.TenantID, coredata.SCIMUserEntityType),
+		TenantID:       configuration.TenantID,
+		OrganizationID: configuration.OrganizationID,
+		ExternalID:     req.ExternalID,
+		UserName:       email,
+		Active:         req.Active,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	var people *coredata.People
+
+	err = s.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+			var scimUsers coredata.SCIMUsers
+			count, err := scimUsers.CountByOrganizationID(
+				ctx,
+				tx,
+				scope,
+				configuration.OrganizationID,
+				coredata.SCIMUserFilter{UserName: &email},
+			)
+			if err != nil {
+				return fmt.Errorf("cannot count scim users: %w", err)
+			}
+
+			if count > 0 {
+				return &ErrSCIMUserAlreadyExists{userName: email}
+			}
+
+			user, err := s.loadOrCreateProvisionedUser(ctx, tx, configuration.OrganizationID, email, req.FullName, now)
+			if err != nil {
+				return err
+			}
+
+			people, err = s.syncProvisionedPeople(ctx, tx, scope, configuration.OrganizationID, nil, email, req, now)
+			if err != nil {
+				return err
+			}
+
+			scimUser.UserID = user.ID
+			scimUser.PeopleID = people.ID
+
+			if err := scimUser.Insert(ctx, tx, scope); err != nil {
+				return fmt.Errorf("cannot insert scim user: %w", err)
+			}
+
+			return s.syncProvisionedMembership(ctx, tx, scimUser, now)
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProvisionedUser{SCIMUser: scimUser, People: people}, nil
+}
+
+// ReplaceSCIMUser updates a provisioned user with the attributes sent by
+// the identity provider. Deactivating the user removes it from the
+// organization while keeping its people record.
+func (s Service) ReplaceSCIMUser(
+	ctx context.Context,
+	configuration *coredata.SCIMConfiguration,
+	scimUserID gid.GID,
+	req SCIMUserRequest,
+) (*ProvisionedUser, error) {
+	email, err := normalizeSCIMUserName(req.UserName)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	scope := coredata.NewScope(configuration.TenantID)
+	scimUser := &coredata.SCIMUser{}
+
+	var people *coredata.People
+
+	err = s.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+			if err := scimUser.LoadByID(ctx, tx, scope, configuration.OrganizationID, scimUserID); err != nil {
+				return fmt.Errorf("cannot load scim user: %w", err)
+			}
+
+			if !strings.EqualFold(scimUser.UserName, email) {
+				var scimUsers coredata.SCIMUsers
+				count, err := scimUsers.CountByOrganizationID(
+					ctx,
+					tx,
+					scope,
+					configuration.OrganizationID,
+					coredata.SCIMUserFilter{UserName: &email},
+				)
+				if err != nil {
+					return fmt.Errorf("cannot count scim users: %w", err)
+				}
+
+				if count > 0 {
+					return &ErrSCIMUserAlreadyExists{userName: email}
+				}
+
+				// The identity provider cannot rename a user account as
+				// it may be shared with other organizations: the scim user
+				// is linked to the account of the new email address
+				// instead.
+				if err := removeProvisionedMember(ctx, tx, scimUser); err != nil {
+					return err
+				}
+
+				user, err := s.loadOrCreateProvisionedUser(ctx, tx, configuration.OrganizationID, email, req.FullName, now)
+				if err != nil {
+					return err
+				}
+
+				scimUser.UserID = user.ID
+			}
+
+			people, err = s.syncProvisionedPeople(ctx, tx, scope, configuration.OrganizationID, &scimUser.PeopleID, email, req, now)
+			if err != nil {
+				return err
+			}
+
+			scimUser.UserName = email
+			scimUser.ExternalID = req.ExternalID
+			scimUser.Active = req.Active
+			scimUser.UpdatedAt = now
+
+			if err := scimUser.Update(ctx, tx, scope); err != nil {
+				return fmt.Errorf("cannot update scim user: %w", err)
+			}
+
+			return s.syncProvisionedMembership(ctx, tx, scimUser, now)
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProvisionedUser{SCIMUser: scimUser, People: people}, nil
+}
+
+// DeleteSCIMUser deprovisions a user: it is removed from the organization
+// and no longer managed by the identity provider. The people record is
+// kept as it may still be referenced by policies and tasks.
+func (s Service) DeleteSCIMUser(
+	ctx context.Context,
+	configuration *coredata.SCIMConfiguration,
+	scimUserID gid.GID,
+) error {
+	scope := coredata.NewScope(configuration.TenantID)
+	scimUser := &coredata.SCIMUser{}
+
+	return s.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+			if err := scimUser.LoadByID(ctx, tx, scope, configuration.OrganizationID, scimUserID); err != nil {
+				return fmt.Errorf("cannot load scim user: %w", err)
+			}
+
+			if err := removeProvisionedMember(ctx, tx, scimUser); err != nil {
+				return err
+			}
+
+			if err := scimUser.Delete(ctx, tx, scope); err != nil {
+				return fmt.Errorf("cannot delete scim user: %w", err)
+			}
+
+			return nil
+		},
+	)
+}
+
+func (s Service) ListSCIMGroups(
+	ctx context.Context,
+	configuration *coredata.SCIMConfiguration,
+	filter coredata.SCIMGroupFilter,
+	offset int,
+	limit int,
+) ([]*ProvisionedGroup, int, error) {
+	var (
+		scope      = coredata.NewScope(configuration.TenantID)
+		scimGroups coredata.SCIMGroups
+		groups     []*ProvisionedGroup
+		total      int
+	)
+
+	err := s.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			var err error
+			total, err = scimGroups.CountByOrganizationID(ctx, conn, scope, configuration.OrganizationID, filter)
+			if err != nil {
+				return fmt.Errorf("cannot count scim groups: %w", err)
+			}
+
+			if err := scimGroups.LoadByOrganizationID(ctx, conn, scope, configuration.OrganizationID, filter, offset, limit); err != nil {
+				return fmt.Errorf("cannot load scim groups: %w", err)
+			}
+
+			for _, scimGroup := range scimGroups {
+				memberIDs, err := scimGroup.LoadMemberIDs(ctx, conn)
+				if err != nil {
+					return fmt.Errorf("cannot load scim group members: %w", err)
+				}
+
+				groups = append(groups, &ProvisionedGroup{SCIMGroup: scimGroup, MemberIDs: memberIDs})
+			}
+
+			return nil
+		},
+	)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return groups, total, nil
+}
+
+func (s Service) GetSCIMGroup(
+	ctx context.Context,
+	configuration *coredata.SCIMConfiguration,
+	scimGroupID gid.GID,
+) (*ProvisionedGroup, error) {
+	scope := coredata.NewScope(configuration.TenantID)
+	scimGroup := &coredata.SCIMGroup{}
+
+	var memberIDs []gid.GID
+
+	err := s.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			if err := scimGroup.LoadByID(ctx, conn, scope, configuration.OrganizationID, scimGroupID); err != nil {
+				return fmt.Errorf("cannot load scim group: %w", err)
+			}
+
+			var err error
+			memberIDs, err = scimGroup.LoadMemberIDs(ctx, conn)
+			if err != nil {
+				return fmt.Errorf("cannot load scim group members: %w", err)
+			}
+
+			return nil
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProvisionedGroup{SCIMGroup: scimGroup, MemberIDs: memberIDs}, nil
+}
+
+func (s Service) CreateSCIMGroup(
+	ctx context.Context,
+	configuration *coredata.SCIMConfiguration,
+	req SCIMGroupRequest,
+) (*ProvisionedGroup, error) {
+	displayName := strings.TrimSpace(req.DisplayName)
+	if displayName == "" {
+		return nil, &ErrInvalidDisplayName{req.DisplayName}
+	}
+
+	now := time.Now()
+	scope := coredata.NewScope(configuration.TenantID)
+	scimGroup := &coredata.SCIMGroup{
+		ID:             gid.New(configuration.TenantID, coredata.SCIMGroupEntityType),
+		TenantID:       configuration.TenantID,
+		OrganizationID: configuration.OrganizationID,
+		ExternalID:     req.ExternalID,
+		DisplayName:    displayName,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	var memberIDs []gid.GID
+
+	err := s.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+			var scimGroups coredata.SCIMGroups
+			count, err := scimGroups.CountByOrganizationID(
+				ctx,
+				tx,
+				scope,
+				configuration.OrganizationID,
+				coredata.SCIMGroupFilter{DisplayName: &displayName},
+			)
+			if err != nil {
+				return fmt.Errorf("cannot count scim groups: %w", err)
+			}
+
+			if count > 0 {
+				return &ErrSCIMGroupAlreadyExists{displayName: displayName}
+			}
+
+			if err := scimGroup.Insert(ctx, tx, scope); err != nil {
+				return fmt.Errorf("cannot insert scim group: %w", err)
+			}
+
+			if err := scimGroup.ReplaceMembers(ctx, tx, scope, req.MemberIDs, now); err != nil {
+				return fmt.Errorf("cannot replace scim group members: %w", err)
+			}
+
+			memberIDs, err = scimGroup.LoadMemberIDs(ctx, tx)
+			if err != nil {
+				return fmt.Errorf("cannot load scim group members: %w", err)
+			}
+
+			return nil
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProvisionedGroup{SCIMGroup: scimGroup, MemberIDs: memberIDs}, nil
+}
+
+func (s Service) ReplaceSCIMGroup(
+	ctx context.Context,
+	configuration *coredata.SCIMConfiguration,
+	scimGroupID gid.GID,
+	req SCIMGroupRequest,
+) (*ProvisionedGroup, error) {
+	displayName := strings.TrimSpace(req.DisplayName)
+	if displayName == "" {
+		return nil, &ErrInvalidDisplayName{req.DisplayName}
+	}
+
+	now := time.Now()
+	scope := coredata.NewScope(configuration.TenantID)
+	scimGroup := &coredata.SCIMGroup{}
+
+	var memberIDs []gid.GID
+
+	err := s.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+			if err := scimGroup.LoadByID(ctx, tx, scope, configuration.OrganizationID, scimGroupID); err != nil {
+				return fmt.Errorf("cannot load scim group: %w", err)
+			}
+
+			if !strings.EqualFold(scimGroup.DisplayName, displayName) {
+				var scimGroups coredata.SCIMGroups
+				count, err := scimGroups.CountByOrganizationID(
+					ctx,
+					tx,
+					scope,
+					configuration.OrganizationID,
+					coredata.SCIMGroupFilter{DisplayName: &displayName},
+				)
+				if err != nil {
+					return fmt.Errorf("cannot count scim groups: %w", err)
+				}
+
+				if count > 0 {
+					return &ErrSCIMGroupAlreadyExists{displayName: displayName}
+				}
+			}
+
+			scimGroup.DisplayName = displayName
+			scimGroup.ExternalID = req.ExternalID
+			scimGroup.UpdatedAt = now
+
+			if err := scimGroup.Update(ctx, tx, scope); err != nil {
+				return fmt.Errorf("cannot update scim group: %w", err)
+			}
+
+			if err := scimGroup.ReplaceMembers(ctx, tx, scope, req.MemberIDs, now); err != nil {
+				return fmt.Errorf("cannot replace scim group members: %w", err)
+			}
+
+			var err error
+			memberIDs, err = scimGroup.LoadMemberIDs(ctx, tx)
+			if err != nil {
+				return fmt.Errorf("cannot load scim group members: %w", err)
+			}
+
+			return nil
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProvisionedGroup{SCIMGroup: scimGroup, MemberIDs: memberIDs}, nil
+}
+
+func (s Service) DeleteSCIMGroup(
+	ctx context.Context,
+	configuration *coredata.SCIMConfiguration,
+	scimGroupID gid.GID,
+) error {
+	scope := coredata.NewScope(configuration.TenantID)
+	scimGroup := &coredata.SCIMGroup{}
+
+	return s.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+			if err := scimGroup.LoadByID(ctx, tx, scope, configuration.OrganizationID, scimGroupID); err != nil {
+				return fmt.Errorf("cannot load scim group: %w", err)
+			}
+
+			if err := scimGroup.Delete(ctx, tx, scope); err != nil {
+				return fmt.Errorf("cannot delete scim group: %w", err)
+			}
+
+			return nil
+		},
+	)
+}
+
+func normalizeSCIMUserName(userName string) (string, error) {
+	email := strings.ToLower(strings.TrimSpace(userName))
+	if !strings.Contains(email, "@") {
+		return "", &ErrInvalidEmail{userName}
+	}
+
+	return email, nil
+}
+
+// loadOrCreateProvisionedUser returns the user account of the email
+// address, creating it without a usable password when it does not exist
+// yet: provisioned users sign in with single sign-on or reset their
+// password. The identity provider is administered by the organization, so
+// existing accounts are only linked when they are already members, and
+// accounts are only created in the domains the organization verified it
+// owns.
+func (s Service) loadOrCreateProvisionedUser(
+	ctx context.Context,
+	conn pg.Conn,
+	organizationID gid.GID,
+	email string,
+	fullName string,
+	now time.Time,
+) (*coredata.User, error) {
+	user := &coredata.User{}
+
+	err := user.LoadByEmail(ctx, conn, email)
+	if err == nil {
+		uo := &coredata.UserOrganization{}
+		if err := uo.LoadByUserIDAndOrganizationID(ctx, conn, user.ID, organizationID); err != nil {
+			var errMembershipNotFound *coredata.ErrMembershipNotFound
+			if errors.As(err, &errMembershipNotFound) {
+				return nil, &ErrSCIMUserNotProvisionable{
+					message: fmt.Sprintf("account %q is not a member of the organization, invite it first", email),
+				}
+			}
+
+			return nil, fmt.Errorf("cannot load membership: %w", err)
+		}
+
+		return user, nil
+	}
+
+	var errUserNotFound *coredata.ErrUserNotFound
+	if !errors.As(err, &errUserNotFound) {
+		return nil, fmt.Errorf("cannot load user: %w", err)
+	}
+
+	ownsEmailDomain, err := organizationOwnsEmailDomain(ctx, conn, organizationID, email)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ownsEmailDomain {
+		return nil, &ErrSCIMUserNotProvisionable{
+			message: fmt.Sprintf("the organization has not verified the email domain of %q", email),
+		}
+	}
+
+	randomPassword := make([]byte, 32)
+	if _, err := rand.Read(randomPassword); err != nil {
+		return nil, fmt.Errorf("cannot generate password: %w", err)
+	}
+
+	hashedPassword, err := s.hp.HashPassword(randomPassword)
+	if err != nil {
+		return nil, fmt.Errorf("cannot hash password: %w", err)
+	}
+
+	user = &coredata.User{
+		ID:                   gid.New(gid.NilTenant, coredata.UserEntityType),
+		EmailAddress:         email,
+		HashedPassword:       hashedPassword,
+		FullName:             fullName,
+		EmailAddressVerified: true,
+		CreatedAt:            now,
+		UpdatedAt:            now,
+	}
+
+	if err := user.Insert(ctx, conn); err != nil {
+		return nil, fmt.Errorf("cannot insert user: %w", err)
+	}
+
+	return user, nil
+}
+
+// syncProvisionedPeople updates the people record linked to a provisioned
+// user, or the one with the same email address, creating it when there is
+// none.
+func (s Service) syncProvisionedPeople(
+	ctx context.Context,
+	conn pg.Conn,
+	scope coredata.Scoper,
+	organizationID gid.GID,
+	peopleID *gid.GID,
+	email string,
+	req SCIMUserRequest,
+	now time.Time,
+) (*coredata.People, error) {
+	people := &coredata.People{}
+
+	if peopleID != nil {
+		if err := people.LoadByID(ctx, conn, scope, *peopleID); err != nil {
+			return nil, fmt.Errorf("cannot load people: %w", err)
+		}
+	} else if err := people.LoadByPrimaryEmailAddress(ctx, conn, scope, organizationID, email); err != nil {
+		var errPeopleNotFound *coredata.ErrPeopleNotFound
+		if !errors.As(err, &errPeopleNotFound) {
+			return nil, fmt.Errorf("cannot load people: %w", err)
+		}
+
+		people = &coredata.People{
+			ID:                       gid.New(scope.GetTenantID(), coredata.PeopleEntityType),
+			OrganizationID:           organizationID,
+			Kind:                     req.Kind,
+			FullName:                 req.FullName,
+			PrimaryEmailAddress:      email,
+			AdditionalEmailAddresses: []string{},
+			CreatedAt:                now,
+			UpdatedAt:                now,
+		}
+
+		if err := people.Insert(ctx, conn, scope); err != nil {
+			return nil, fmt.Errorf("cannot insert people: %w", err)
+		}
+
+		return people, nil
+	}
+
+	fullName := req.FullName
+	if fullName == "" {
+		fullName = people.FullName
+	}
+
+	additionalEmailAddresses := people.AdditionalEmailAddresses
+	if additionalEmailAddresses == nil {
+		additionalEmailAddresses = []string{}
+	}
+
+	err := people.Update(
+		ctx,
+		conn,
+		scope,
+		coredata.UpdatePeopleParams{
+			ExpectedVersion:          people.Version,
+			FullName:                 &fullName,
+			PrimaryEmailAddress:      &email,
+			AdditionalEmailAddresses: &additionalEmailAddresses,
+			Kind:                     &req.Kind,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot update people: %w", err)
+	}
+
+	return people, nil
+}
+
+func (s Service) syncProvisionedMembership(
+	ctx context.Context,
+	conn pg.Conn,
+	scimUser *coredata.SCIMUser,
+	now time.Time,
+) error {
+	uo := coredata.UserOrganization{
+		UserID:         scimUser.UserID,
+		OrganizationID: scimUser.OrganizationID,
+		Role:           coredata.MembershipRoleMember,
+		CreatedAt:      now,
+	}
+
+	if scimUser.Active {
+		if err := uo.InsertIfNotExists(ctx, conn); err != nil {
+			return fmt.Errorf("cannot enroll user in organization: %w", err)
+		}
+
+		return nil
+	}
+
+	return removeProvisionedMember(ctx, conn, scimUser)
+}
+
+// removeProvisionedMember removes the user of the scim user from the
+// organization the same way RemoveUser does, revoking their API tokens
+// for the organization. A user who already left the organization is
+// ignored.
+func removeProvisionedMember(
+	ctx context.Context,
+	conn pg.Conn,
+	scimUser *coredata.SCIMUser,
+) error {
+	uo := &coredata.UserOrganization{}
+	if err := uo.LoadByUserIDAndOrganizationID(ctx, conn, scimUser.UserID, scimUser.OrganizationID); err != nil {
+		var errMembershipNotFound *coredata.ErrMembershipNotFound
+		if errors.As(err, &errMembershipNotFound) {
+			return nil
+		}
+
+		return fmt.Errorf("cannot load membership: %w", err)
+	}
+
+	// Sessions are shared by all the organizations of the user, they are
+	// only revoked when the user has no other organization left.
+	var memberships coredata.UserOrganizations
+	if err := memberships.ForUserID(ctx, conn, scimUser.UserID); err != nil {
+		return fmt.Errorf("cannot load memberships: %w", err)
+	}
+
+	return removeMember(ctx, conn, uo, len(memberships) == 1)
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package usrmgr
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/getprobo/probo/pkg/coredata"
+	"github.com/getprobo/probo/pkg/gid"
+	"go.gearno.de/kit/pg"
+)
+
+// ListSessions returns the sessions of the user which are not expired,
+// most recently active first.
+func (s Service) ListSessions(
+	ctx context.Context,
+	userID gid.GID,
+) (coredata.Sessions, error) {
+	var sessions coredata.Sessions
+
+	err := s.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			return sessions.LoadActiveByUserID(ctx, conn, userID, time.Now())
+		},
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot list sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+func (s Service) RevokeSession(
+	ctx context.Context,
+	userID gid.GID,
+	sessionID gid.GID,
+) error {
+	return s.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			deleted, err := coredata.DeleteUserSession(ctx, conn, userID, sessionID)
+			if err != nil {
+				return fmt.Errorf("cannot delete session: %w", err)
+			}
+
+			if !deleted {
+				return &ErrSessionNotFound{message: "session not found"}
+			}
+
+			return nil
+		},
+	)
+}
+
+// RevokeAllOtherSessions signs the user out of every session but the
+// current one and returns how many sessions were revoked.
+func (s Service) RevokeAllOtherSessions(
+	ctx context.Context,
+	userID gid.GID,
+	currentSessionID gid.GID,
+) (int, error) {
+	var revoked int64
+
+	err := s.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			var err error
+			revoked, err = coredata.DeleteOtherUserSessions(ctx, conn, userID, currentSessionID)
+			if err != nil {
+				return fmt.Errorf("cannot delete sessions: %w", err)
+			}
+
+			return nil
+		},
+	)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return int(revoked), nil
+}
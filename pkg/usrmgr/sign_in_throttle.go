@@ -0,0 +1,408 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package usrmgr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/getprobo/probo/pkg/coredata"
+	"github.com/getprobo/probo/pkg/gid"
+	"go.gearno.de/kit/pg"
+)
+
+type (
+	// ErrTooManySignInAttempts is returned when a sign-in attempt comes in
+	// before the backoff delay of the previous failures elapsed.
+	ErrTooManySignInAttempts struct {
+		RetryAfter time.Duration
+	}
+
+	// ErrAccountLocked is returned when the account or the client address
+	// is locked after too many failed sign-in attempts.
+	ErrAccountLocked struct {
+		RetryAfter time.Duration
+	}
+)
+
+const (
+	// signInFreeAttempts is the number of failed attempts allowed before
+	// the backoff kicks in.
+	signInFreeAttempts = 3
+	signInBaseBackoff  = 1 * time.Second
+	signInMaxBackoff   = 5 * time.Minute
+
+	// Failed attempts are forgotten after this period without failure.
+	signInFailureWindow = 1 * time.Hour
+
+	accountLockoutThreshold = 10
+	ipLockoutThreshold      = 100
+	signInLockoutDuration   = 30 * time.Minute
+
+	// mfaChallengeMaxAttempts is the number of codes which can be tried
+	// for a single multi-factor authentication challenge.
+	mfaChallengeMaxAttempts = 5
+)
+
+var (
+	accountLockedEmailSubject  = "Your Probo account has been locked"
+	accountLockedEmailTemplate = `
+	Your Probo account has been temporarily locked after too many failed sign-in attempts.
+	You will be able to sign in again after %s.
+
+	If these attempts were not made by you, someone may be trying to access
+	your account. We recommend that you reset your password[1].
+
+	[1] %s
+	`
+)
+
+func (e ErrTooManySignInAttempts) Error() string {
+	return fmt.Sprintf("too many sign-in attempts, retry in %s", e.RetryAfter.Round(time.Second))
+}
+
+func (e ErrAccountLocked) Error() string {
+	return fmt.Sprintf("too many failed sign-in attempts, locked for %s", e.RetryAfter.Round(time.Second))
+}
+
+func accountThrottleKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipThrottleKey(ipAddress string) string {
+	return "ip:" + ipAddress
+}
+
+func mfaChallengeThrottleKey(challengeID string) string {
+	return "mfa-challenge:" + challengeID
+}
+
+func mfaVerificationThrottleKey(userID gid.GID) string {
+	return "mfa-verification:" + userID.String()
+}
+
+// signInBackoff returns how long a key must wait after its last failure
+// before it can try again.
+func signInBackoff(failedAttempts int) time.Duration {
+	if failedAttempts < signInFreeAttempts {
+		return 0
+	}
+
+	exponent := failedAttempts - signInFreeAttempts
+	if exponent > 16 {
+		return signInMaxBackoff
+	}
+
+	backoff := signInBaseBackoff << exponent
+	if backoff > signInMaxBackoff {
+		return signInMaxBackoff
+	}
+
+	return backoff
+}
+
+// accountLockedOut reports whether an account gets locked after its
+// failed attempts.
+func accountLockedOut(failedAttempts int) bool {
+	return failedAttempts >= accountLockoutThreshold
+}
+
+// ipLockedOut reports whether a client address gets locked after its
+// failed attempts.
+func ipLockedOut(failedAttempts int) bool {
+	return failedAttempts >= ipLockoutThreshold
+}
+
+// checkSignInThrottle returns an error if the key is locked or still
+// within its backoff delay.
+func checkSignInThrottle(
+	ctx context.Context,
+	conn pg.Conn,
+	key string,
+	now time.Time,
+) error {
+	throttle := &coredata.SignInThrottle{}
+
+	if err := throttle.LoadByKey(ctx, conn, key); err != nil {
+		var errNotFound *coredata.ErrSignInThrottleNotFound
+		if errors.As(err, &errNotFound) {
+			return nil
+		}
+
+		return fmt.Errorf("cannot load sign in throttle: %w", err)
+	}
+
+	if throttle.LockedUntil != nil && now.Before(*throttle.LockedUntil) {
+		return &ErrAccountLocked{RetryAfter: throttle.LockedUntil.Sub(now)}
+	}
+
+	if throttle.LastFailedAt.Before(now.Add(-signInFailureWindow)) {
+		return nil
+	}
+
+	retryAt := throttle.LastFailedAt.Add(signInBackoff(throttle.FailedAttempts))
+	if now.Before(retryAt) {
+		return &ErrTooManySignInAttempts{RetryAfter: retryAt.Sub(now)}
+	}
+
+	return nil
+}
+
+func (s Service) checkSignInThrottles(
+	ctx context.Context,
+	email string,
+	ipAddress string,
+) error {
+	now := time.Now()
+
+	return s.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			if err := checkSignInThrottle(ctx, conn, ipThrottleKey(ipAddress), now); err != nil {
+				return err
+			}
+
+			return checkSignInThrottle(ctx, conn, accountThrottleKey(email), now)
+		},
+	)
+}
+
+// recordSignInFailure counts a failed attempt for both the account and
+// the client address, locking them when they reach their threshold. The
+// account owner is notified by email when their account gets locked.
+func (s Service) recordSignInFailure(
+	ctx context.Context,
+	email string,
+	ipAddress string,
+) error {
+	now := time.Now()
+	resetBefore := now.Add(-signInFailureWindow)
+	lockedUntil := now.Add(signInLockoutDuration)
+
+	return s.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+			// Throttles are only needed for a failure window, expired
+			// ones are purged as new failures come in.
+			if err := coredata.DeleteExpiredSignInThrottles(ctx, tx, resetBefore, now); err != nil {
+				return err
+			}
+
+			ipThrottle := &coredata.SignInThrottle{}
+			if err := ipThrottle.RecordFailure(ctx, tx, ipThrottleKey(ipAddress), now, resetBefore); err != nil {
+				return fmt.Errorf("cannot record ip sign in failure: %w", err)
+			}
+
+			if ipLockedOut(ipThrottle.FailedAttempts) {
+				if err := ipThrottle.Lock(ctx, tx, lockedUntil, now); err != nil {
+					return fmt.Errorf("cannot lock ip address: %w", err)
+				}
+			}
+
+			return s.recordAccountSignInFailure(ctx, tx, email, now)
+		},
+	)
+}
+
+// recordAccountSignInFailure counts a failed attempt for the account,
+// locking it and notifying its owner when it reaches its threshold.
+func (s Service) recordAccountSignInFailure(
+	ctx context.Context,
+	conn pg.Conn,
+	email string,
+	now time.Time,
+) error {
+	resetBefore := now.Add(-signInFailureWindow)
+	lockedUntil := now.Add(signInLockoutDuration)
+
+	accountThrottle := &coredata.SignInThrottle{}
+	if err := accountThrottle.RecordFailure(ctx, conn, accountThrottleKey(email), now, resetBefore); err != nil {
+		return fmt.Errorf("cannot record account sign in failure: %w", err)
+	}
+
+	if !accountLockedOut(accountThrottle.FailedAttempts) {
+		return nil
+	}
+
+	if err := accountThrottle.Lock(ctx, conn, lockedUntil, now); err != nil {
+		return fmt.Errorf("cannot lock account: %w", err)
+	}
+
+	user := &coredata.User{}
+	if err := user.LoadByEmail(ctx, conn, email); err != nil {
+		var errUserNotFound *coredata.ErrUserNotFound
+		if errors.As(err, &errUserNotFound) {
+			return nil
+		}
+
+		return fmt.Errorf("cannot load user by email: %w", err)
+	}
+
+	forgotPasswordUrl := url.URL{
+		Scheme: "https",
+		Host:   s.hostname,
+		Path:   "/forgot-password",
+	}
+
+	lockedEmail := coredata.NewEmail(
+		user.FullName,
+		user.EmailAddress,
+		accountLockedEmailSubject,
+		fmt.Sprintf(
+			accountLockedEmailTemplate,
+			lockedUntil.UTC().Format(time.RFC1123),
+			forgotPasswordUrl.String(),
+		),
+	)
+
+	if err := lockedEmail.Insert(ctx, conn); err != nil {
+		return fmt.Errorf("cannot insert email: %w", err)
+	}
+
+	return nil
+}
+
+func (s Service) resetAccountSignInThrottle(
+	ctx context.Context,
+	email string,
+) error {
+	return s.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			if err := coredata.DeleteSignInThrottle(ctx, conn, accountThrottleKey(email)); err != nil {
+				return fmt.Errorf("cannot delete sign in throttle: %w", err)
+			}
+
+			return nil
+		},
+	)
+}
+
+// recordMFAChallengeAttempt counts an attempt to answer the challenge,
+// before the code is verified so concurrent attempts cannot exceed the
+// limit. The challenge is rejected once the limit is reached.
+func (s Service) recordMFAChallengeAttempt(
+	ctx context.Context,
+	challengeID string,
+) error {
+	// Challenges expire on their own, their attempts are never forgotten
+	// until the throttle is purged.
+	failedAttempts, err := s.recordMFAAttempt(ctx, mfaChallengeThrottleKey(challengeID), time.Time{})
+	if err != nil {
+		return fmt.Errorf("cannot record mfa challenge attempt: %w", err)
+	}
+
+	if failedAttempts > mfaChallengeMaxAttempts {
+		return &ErrInvalidCredentials{message: "invalid or expired mfa challenge"}
+	}
+
+	return nil
+}
+
+// checkMFAVerificationThrottle throttles the codes a signed-in user gives
+// to manage their second factor, as a stolen session must not be able to
+// brute-force them. The account throttle applies as for a sign in, and
+// the attempts are limited like for a challenge, see
+// recordMFAChallengeAttempt, until a code is accepted or they are
+// forgotten after signInFailureWindow.
+func (s Service) checkMFAVerificationThrottle(
+	ctx context.Context,
+	user *coredata.User,
+) error {
+	now := time.Now()
+
+	err := s.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			return checkSignInThrottle(ctx, conn, accountThrottleKey(user.EmailAddress), now)
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	failedAttempts, err := s.recordMFAAttempt(ctx, mfaVerificationThrottleKey(user.ID), now.Add(-signInFailureWindow))
+	if err != nil {
+		return fmt.Errorf("cannot record mfa verification attempt: %w", err)
+	}
+
+	if failedAttempts > mfaChallengeMaxAttempts {
+		return &ErrTooManySignInAttempts{RetryAfter: signInFailureWindow}
+	}
+
+	return nil
+}
+
+// recordMFAVerificationResult counts the failure of a code checked after
+// checkMFAVerificationThrottle against the account, or forgets the
+// attempts once a code is accepted. The verification error is returned.
+func (s Service) recordMFAVerificationResult(
+	ctx context.Context,
+	user *coredata.User,
+	verifyErr error,
+) error {
+	var errInvalidMFACode *ErrInvalidMFACode
+
+	err := s.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+			switch {
+			case verifyErr == nil:
+				if err := coredata.DeleteSignInThrottle(ctx, tx, mfaVerificationThrottleKey(user.ID)); err != nil {
+					return fmt.Errorf("cannot delete sign in throttle: %w", err)
+				}
+			case errors.As(verifyErr, &errInvalidMFACode):
+				if err := s.recordAccountSignInFailure(ctx, tx, user.EmailAddress, time.Now()); err != nil {
+					return fmt.Errorf("cannot record sign in failure: %w", err)
+				}
+			}
+
+			return nil
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	return verifyErr
+}
+
+// recordMFAAttempt counts an attempt to verify a code for the key and
+// returns the number of attempts, before the code is verified so
+// concurrent attempts cannot exceed the limit.
+func (s Service) recordMFAAttempt(
+	ctx context.Context,
+	key string,
+	resetBefore time.Time,
+) (int, error) {
+	throttle := &coredata.SignInThrottle{}
+
+	err := s.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			return throttle.RecordFailure(ctx, conn, key, time.Now(), resetBefore)
+		},
+	)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return throttle.FailedAttempts, nil
+}
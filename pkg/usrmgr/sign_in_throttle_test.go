@@ -0,0 +1,77 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package usrmgr
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSignInBackoff(t *testing.T) {
+	tests := []struct {
+		failedAttempts int
+		want           time.Duration
+	}{
+		{0, 0},
+		{1, 0},
+		{2, 0},
+		{3, 1 * time.Second},
+		{4, 2 * time.Second},
+		{5, 4 * time.Second},
+		{9, 64 * time.Second},
+		{11, 256 * time.Second},
+		{12, 5 * time.Minute},
+		{20, 5 * time.Minute},
+		{1000, 5 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := signInBackoff(tt.failedAttempts); got != tt.want {
+			t.Errorf("signInBackoff(%d) = %s, want %s", tt.failedAttempts, got, tt.want)
+		}
+	}
+}
+
+func TestSignInBackoffIsMonotonic(t *testing.T) {
+	previous := time.Duration(0)
+	for failedAttempts := range 100 {
+		backoff := signInBackoff(failedAttempts)
+		if backoff < previous {
+			t.Fatalf("signInBackoff(%d) = %s is shorter than the previous %s", failedAttempts, backoff, previous)
+		}
+
+		if backoff > signInMaxBackoff {
+			t.Fatalf("signInBackoff(%d) = %s exceeds %s", failedAttempts, backoff, signInMaxBackoff)
+		}
+
+		previous = backoff
+	}
+}
+
+func TestAccountLockedOut(t *testing.T) {
+	for failedAttempts := range 10 {
+		if accountLockedOut(failedAttempts) {
+			t.Errorf("account locked after %d failed attempts", failedAttempts)
+		}
+	}
+
+	if !accountLockedOut(10) {
+		t.Error("account not locked after 10 failed attempts")
+	}
+
+	if ipLockedOut(99) || !ipLockedOut(100) {
+		t.Error("client address must be locked after 100 failed attempts")
+	}
+}
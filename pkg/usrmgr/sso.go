@@ -0,0 +1,284 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package usrmgr
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/getprobo/probo/pkg/coredata"
+	"github.com/getprobo/probo/pkg/gid"
+	"go.gearno.de/kit/pg"
+)
+
+type (
+	ErrEmailDomainNotAllowed struct {
+		email string
+	}
+
+	ErrFederatedAccountNotLinkable struct {
+		message string
+	}
+)
+
+func (e ErrEmailDomainNotAllowed) Error() string {
+	return fmt.Sprintf("email %q is not allowed to sign in with this identity provider", e.email)
+}
+
+func (e ErrFederatedAccountNotLinkable) Error() string {
+	return e.message
+}
+
+// signInFederatedUser opens a session for a user authenticated by the
+// identity provider of an organization, creating the user and enrolling
+// it in the organization just in time when needed. Existing accounts are
+// only signed in when they are already members, see
+// ensureFederatedAccountLinkable. New accounts only get a verified email
+// address, and so only auto-join other organizations, when the
+// organization verified it owns the domain of the address.
+func (s Service) signInFederatedUser(
+	ctx context.Context,
+	organizationID gid.GID,
+	email string,
+	fullName string,
+) (*coredata.User, *coredata.Session, error) {
+	// Federated users have no local password until they reset it.
+	randomPassword := make([]byte, 32)
+	if _, err := rand.Read(randomPassword); err != nil {
+		return nil, nil, fmt.Errorf("cannot generate password: %w", err)
+	}
+
+	hashedPassword, err := s.hp.HashPassword(randomPassword)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot hash password: %w", err)
+	}
+
+	now := time.Now()
+	user := &coredata.User{}
+	session := &coredata.Session{
+		ID:             gid.New(gid.NilTenant, coredata.SessionEntityType),
+		LastActivityAt: now,
+		ExpiredAt:      now.Add(24 * time.Hour),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	err = s.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+			var errUserNotFound *coredata.ErrUserNotFound
+
+			err := user.LoadByEmail(ctx, tx, email)
+			switch {
+			case err == nil:
+				if err := ensureFederatedAccountLinkable(ctx, tx, user, organizationID); err != nil {
+					return err
+				}
+			case errors.As(err, &errUserNotFound):
+				// The identity provider only vouches for the address
+				// when the organization proved it owns its domain.
+				emailAddressVerified, err := organizationOwnsEmailDomain(ctx, tx, organizationID, email)
+				if err != nil {
+					return err
+				}
+
+				user = &coredata.User{
+					ID:                   gid.New(gid.NilTenant, coredata.UserEntityType),
+					EmailAddress:         email,
+					HashedPassword:       hashedPassword,
+					FullName:             fullName,
+					EmailAddressVerified: emailAddressVerified,
+					CreatedAt:            now,
+					UpdatedAt:            now,
+				}
+
+				if err := user.Insert(ctx, tx); err != nil {
+					return fmt.Errorf("cannot insert user: %w", err)
+				}
+
+				if err := autoJoinOrganizations(ctx, tx, user); err != nil {
+					return fmt.Errorf("cannot auto-join organizations: %w", err)
+				}
+			default:
+				return fmt.Errorf("cannot load user: %w", err)
+			}
+
+			uo := coredata.UserOrganization{
+				UserID:         user.ID,
+				OrganizationID: organizationID,
+				Role:           coredata.MembershipRoleMember,
+				CreatedAt:      now,
+			}
+
+			if err := uo.InsertIfNotExists(ctx, tx); err != nil {
+				return fmt.Errorf("cannot enroll user in organization: %w", err)
+			}
+
+			session.UserID = user.ID
+			if err := session.Insert(ctx, tx); err != nil {
+				return fmt.Errorf("cannot insert session: %w", err)
+			}
+
+			return nil
+		},
+	)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, session, nil
+}
+
+// ensureFederatedAccountLinkable refuses to open a session for an existing
+// account through the identity provider of an organization it is not a
+// member of. The identity provider is administered by the organization
+// and could otherwise assert the email address of any user. Accounts
+// protected by a second factor are refused as well, as the identity
+// provider would bypass it.
+func ensureFederatedAccountLinkable(
+	ctx context.Context,
+	conn pg.Conn,
+	user *coredata.User,
+	organizationID gid.GID,
+) error {
+	uo := &coredata.UserOrganization{}
+	if err := uo.LoadByUserIDAndOrganizationID(ctx, conn, user.ID, organizationID); err != nil {
+		var errMembershipNotFound *coredata.ErrMembershipNotFound
+		if errors.As(err, &errMembershipNotFound) {
+			return &ErrFederatedAccountNotLinkable{
+				message: fmt.Sprintf("account %q is not a member of the organization, sign in with your password", user.EmailAddress),
+			}
+		}
+
+		return fmt.Errorf("cannot load membership: %w", err)
+	}
+
+	if user.MFAEnabled() {
+		return &ErrFederatedAccountNotLinkable{
+			message: fmt.Sprintf("account %q has multi-factor authentication enabled, sign in with your password", user.EmailAddress),
+		}
+	}
+
+	return nil
+}
+
+// organizationOwnsEmailDomain reports whether the organization verified it
+// owns the domain of the email address.
+func organizationOwnsEmailDomain(
+	ctx context.Context,
+	conn pg.Conn,
+	organizationID gid.GID,
+	email string,
+) (bool, error) {
+	domain, ok := emailDomain(email)
+	if !ok {
+		return false, nil
+	}
+
+	ed := &coredata.EmailDomain{}
+	if err := ed.LoadVerifiedByOrganizationIDAndDomain(ctx, conn, organizationID, domain); err != nil {
+		var errEmailDomainNotFound *coredata.ErrEmailDomainNotFound
+		if errors.As(err, &errEmailDomainNotFound) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("cannot load email domain: %w", err)
+	}
+
+	return true, nil
+}
+
+func emailDomainAllowed(email string, allowedDomains []string) bool {
+	domain, ok := emailDomain(email)
+	if !ok {
+		return false
+	}
+
+	return slices.Contains(allowedDomains, domain)
+}
+
+// emailDomain returns the lower-cased domain of the email address.
+func emailDomain(email string) (string, bool) {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return "", false
+	}
+
+	return strings.ToLower(email[at+1:]), true
+}
+
+// autoJoinOrganizations enrolls the user in the organizations which opted
+// in to auto-join for the domain of their email address. The address must
+// be verified, otherwise anyone could join by signing up with it.
+func autoJoinOrganizations(
+	ctx context.Context,
+	conn pg.Conn,
+	user *coredata.User,
+) error {
+	if !user.EmailAddressVerified {
+		return nil
+	}
+
+	domain, ok := emailDomain(user.EmailAddress)
+	if !ok {
+		return nil
+	}
+
+	var organizations coredata.Organizations
+	if err := organizations.LoadByAutoJoinEmailDomain(ctx, conn, domain); err != nil {
+		return fmt.Errorf("cannot load auto-join organizations: %w", err)
+	}
+
+	now := time.Now()
+	for _, organization := range organizations {
+		uo := coredata.UserOrganization{
+			UserID:         user.ID,
+			OrganizationID: organization.ID,
+			Role:           coredata.MembershipRoleMember,
+			CreatedAt:      now,
+		}
+
+		if err := uo.InsertIfNotExists(ctx, conn); err != nil {
+			return fmt.Errorf("cannot insert user organization: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ssoURL returns the absolute URL of a single sign-on endpoint, to be
+// registered in the identity provider of an organization.
+func (s Service) ssoURL(path string) string {
+	u := url.URL{
+		Scheme: "https",
+		Host:   s.hostname,
+		Path:   path,
+	}
+
+	// Allows testing against a local identity provider without TLS.
+	if host, _, err := net.SplitHostPort(s.hostname); err == nil && host == "localhost" {
+		u.Scheme = "http"
+	}
+
+	return u.String()
+}
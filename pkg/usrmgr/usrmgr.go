@@ -16,6 +16,10 @@ package usrmgr
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"net/url"
@@ -23,10 +27,13 @@ import (
 	"time"
 
 	"github.com/getprobo/probo/pkg/coredata"
+	"github.com/getprobo/probo/pkg/crypto/keyring"
 	"github.com/getprobo/probo/pkg/crypto/passwdhash"
 	"github.com/getprobo/probo/pkg/gid"
 	"github.com/getprobo/probo/pkg/page"
 	"github.com/getprobo/probo/pkg/statelesstoken"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"go.gearno.de/kit/log"
 	"go.gearno.de/kit/pg"
 )
 
@@ -35,8 +42,13 @@ type (
 		pg            *pg.Client
 		hp            *passwdhash.Profile
 		hostname      string
-		tokenSecret   string
+		tokenKeyring  *keyring.Keyring
 		disableSignup bool
+		// signupAllowedEmailDomains restricts self-service signup to these
+		// email domains, any domain is allowed when empty.
+		signupAllowedEmailDomains []string
+		webauthn                  *webauthn.WebAuthn
+		logger                    *log.Logger
 	}
 
 	ErrInvalidCredentials struct {
@@ -73,22 +85,49 @@ type (
 
 	ErrSignupDisabled struct{}
 
+	ErrSignupEmailDomainNotAllowed struct {
+		email string
+	}
+
+	ErrInvalidPasswordResetToken struct {
+		message string
+	}
+
 	EmailConfirmationData struct {
 		UserID gid.GID `json:"uid"`
 		Email  string  `json:"email"`
 	}
 
 	InvitationData struct {
+		// InvitationID is not set for tokens issued before invitations
+		// were persisted.
+		InvitationID   gid.GID `json:"invitation_id"`
 		OrganizationID gid.GID `json:"organization_id"`
 		Email          string  `json:"email"`
 		FullName       string  `json:"full_name"`
 	}
+
+	PasswordResetData struct {
+		UserID gid.GID `json:"uid"`
+		Email  string  `json:"email"`
+		// PasswordHash is a digest of the password hash at the time the
+		// token was issued, it makes the token single-use as any password
+		// change invalidates it.
+		PasswordHash string `json:"pwd"`
+	}
 )
 
 // Token types
 const (
 	TokenTypeEmailConfirmation      = "email_confirmation"
 	TokenTypeOrganizationInvitation = "organization_invitation"
+	TokenTypePasswordReset          = "password_reset"
+	TokenTypeMFAChallenge           = "mfa_challenge"
+	TokenTypeWebAuthnRegistration   = "webauthn_registration"
+	TokenTypeWebAuthnLogin          = "webauthn_login"
+	TokenTypeOIDCLogin              = "oidc_login"
+	TokenTypeSAMLLogin              = "saml_login"
+	TokenTypeEmailChange            = "email_change"
 )
 
 var (
@@ -107,6 +146,16 @@ var (
 
 	[1] %s
 	`
+
+	passwordResetEmailSubject  = "Reset your password"
+	passwordResetEmailTemplate = `
+	Someone requested a password reset for your Probo account.
+	If it was you, please click the link below to choose a new password[1]
+
+	If you did not request a password reset, you can safely ignore this email.
+
+	[1] %s
+	`
 )
 
 func (e ErrInvalidCredentials) Error() string {
@@ -145,20 +194,38 @@ func (e ErrSignupDisabled) Error() string {
 	return "signup is disabled, contact the owner of the Probo instance"
 }
 
+func (e ErrSignupEmailDomainNotAllowed) Error() string {
+	return fmt.Sprintf("signup is not allowed for %q, contact the owner of the Probo instance", e.email)
+}
+
+func (e ErrInvalidPasswordResetToken) Error() string {
+	return e.message
+}
+
 func NewService(
 	ctx context.Context,
 	pgClient *pg.Client,
+	logger *log.Logger,
 	hp *passwdhash.Profile,
-	tokenSecret string,
+	tokenKeyring *keyring.Keyring,
 	hostname string,
 	disableSignup bool,
+	signupAllowedEmailDomains []string,
 ) (*Service, error) {
+	wa, err := newWebAuthn(hostname)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create webauthn relying party: %w", err)
+	}
+
 	return &Service{
-		pg:            pgClient,
-		hp:            hp,
-		hostname:      hostname,
-		tokenSecret:   tokenSecret,
-		disableSignup: disableSignup,
+		pg:                        pgClient,
+		hp:                        hp,
+		hostname:                  hostname,
+		tokenKeyring:              tokenKeyring,
+		disableSignup:             disableSignup,
+		signupAllowedEmailDomains: signupAllowedEmailDomains,
+		webauthn:                  wa,
+		logger:                    logger,
 	}, nil
 }
 
@@ -174,6 +241,10 @@ func (s Service) SignUp(
 		return nil, nil, &ErrInvalidEmail{email}
 	}
 
+	if len(s.signupAllowedEmailDomains) > 0 && !emailDomainAllowed(email, s.signupAllowedEmailDomains) {
+		return nil, nil, &ErrSignupEmailDomainNotAllowed{email}
+	}
+
 	if len(password) < 8 {
 		return nil, nil, &ErrInvalidPassword{len(password)}
 	}
@@ -198,15 +269,16 @@ func (s Service) SignUp(
 	}
 
 	session := &coredata.Session{
-		ID:        gid.New(gid.NilTenant, coredata.SessionEntityType),
-		UserID:    user.ID,
-		ExpiredAt: now.Add(24 * time.Hour),
-		CreatedAt: now,
-		UpdatedAt: now,
+		ID:             gid.New(gid.NilTenant, coredata.SessionEntityType),
+		UserID:         user.ID,
+		LastActivityAt: now,
+		ExpiredAt:      now.Add(24 * time.Hour),
+		CreatedAt:      now,
+		UpdatedAt:      now,
 	}
 
 	confirmationToken, err := statelesstoken.NewToken(
-		s.tokenSecret,
+		s.tokenKeyring,
 		TokenTypeEmailConfirmation,
 		1*time.Hour,
 		EmailConfirmationData{UserID: user.ID, Email: user.EmailAddress},
@@ -257,18 +329,25 @@ func (s Service) SignUp(
 	return user, session, nil
 }
 
+// SignIn authenticates the user with a password. Failed attempts are
+// throttled per account and per client address, see checkSignInThrottles.
 func (s Service) SignIn(
 	ctx context.Context,
-	email, password string,
+	email, password, ipAddress string,
 ) (*coredata.User, *coredata.Session, error) {
+	if err := s.checkSignInThrottles(ctx, email, ipAddress); err != nil {
+		return nil, nil, err
+	}
+
 	now := time.Now()
 	user := &coredata.User{}
 	session := &coredata.Session{
-		ID:        gid.New(gid.NilTenant, coredata.SessionEntityType),
-		UserID:    gid.Nil,
-		ExpiredAt: now.Add(24 * time.Hour),
-		CreatedAt: now,
-		UpdatedAt: now,
+		ID:             gid.New(gid.NilTenant, coredata.SessionEntityType),
+		UserID:         gid.Nil,
+		LastActivityAt: now,
+		ExpiredAt:      now.Add(24 * time.Hour),
+		CreatedAt:      now,
+		UpdatedAt:      now,
 	}
 
 	err := s.pg.WithTx(
@@ -285,6 +364,18 @@ func (s Service) SignIn(
 			}
 
 			ok, err := s.hp.ComparePasswordAndHash([]byte(password), user.HashedPassword)
+			if errors.Is(err, passwdhash.ErrUnknownPepper) {
+				// The user cannot do anything about a missing pepper, the
+				// attempt is reported as invalid credentials so the
+				// password reset flow stays available.
+				s.logger.WarnCtx(
+					ctx,
+					"cannot verify password, the pepper of the hash is not configured",
+					log.String("user_id", user.ID.String()),
+				)
+
+				return &ErrInvalidCredentials{message: "invalid email or password"}
+			}
 			if err != nil {
 				return fmt.Errorf("cannot compare password: %w", err)
 			}
@@ -293,6 +384,28 @@ func (s Service) SignIn(
 				return &ErrInvalidCredentials{message: "invalid email or password"}
 			}
 
+			if user.MFAEnabled() {
+				challengeID := make([]byte, 16)
+				if _, err := rand.Read(challengeID); err != nil {
+					return fmt.Errorf("cannot generate mfa challenge id: %w", err)
+				}
+
+				challengeToken, err := statelesstoken.NewToken(
+					s.tokenKeyring,
+					TokenTypeMFAChallenge,
+					5*time.Minute,
+					MFAChallengeData{
+						UserID:      user.ID,
+						ChallengeID: hex.EncodeToString(challengeID),
+					},
+				)
+				if err != nil {
+					return fmt.Errorf("cannot generate mfa challenge token: %w", err)
+				}
+
+				return &ErrMFARequired{ChallengeToken: challengeToken}
+			}
+
 			session.UserID = user.ID
 
 			if err := session.Insert(ctx, tx); err != nil {
@@ -303,6 +416,32 @@ func (s Service) SignIn(
 		},
 	)
 
+	var (
+		errInvalidCredentials *ErrInvalidCredentials
+		errMFARequired        *ErrMFARequired
+	)
+
+	switch {
+	case err == nil, errors.As(err, &errMFARequired):
+		// The account failures are only forgotten once the sign in is
+		// complete, VerifyMFAChallenge resets them when a second factor
+		// is required. The client address counter is left untouched, a
+		// valid account must not clear it.
+		if err == nil {
+			if err := s.resetAccountSignInThrottle(ctx, email); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		if err := s.rehashPasswordIfNeeded(ctx, user, password); err != nil {
+			return nil, nil, err
+		}
+	case errors.As(err, &errInvalidCredentials):
+		if err := s.recordSignInFailure(ctx, email, ipAddress); err != nil {
+			return nil, nil, fmt.Errorf("cannot record sign in failure: %w", err)
+		}
+	}
+
 	if err != nil {
 		return nil, nil, err
 	}
@@ -310,6 +449,35 @@ func (s Service) SignIn(
 	return user, session, nil
 }
 
+// rehashPasswordIfNeeded upgrades the password hash of the user when it
+// was computed with different settings than the current profile ones. It
+// must only be called once the password has been verified.
+func (s Service) rehashPasswordIfNeeded(
+	ctx context.Context,
+	user *coredata.User,
+	password string,
+) error {
+	if !s.hp.NeedsRehash(user.HashedPassword) {
+		return nil
+	}
+
+	hashedPassword, err := s.hp.HashPassword([]byte(password))
+	if err != nil {
+		return fmt.Errorf("cannot hash password: %w", err)
+	}
+
+	return s.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			if err := user.ReplaceHashedPassword(ctx, conn, hashedPassword); err != nil {
+				return fmt.Errorf("cannot rehash password: %w", err)
+			}
+
+			return nil
+		},
+	)
+}
+
 func (s Service) SignOut(
 	ctx context.Context,
 	sessionID gid.GID,
@@ -428,6 +596,31 @@ func (s Service) ListOrganizationsForUserID(
 	return organizations, nil
 }
 
+func (s Service) ListMFARequiredTenantsForUserID(
+	ctx context.Context,
+	userID gid.GID,
+) ([]gid.TenantID, error) {
+	organizations := coredata.Organizations{}
+
+	err := s.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			return organizations.LoadMFARequiredByUserID(ctx, conn, userID)
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	tenantIDs := make([]gid.TenantID, 0, len(organizations))
+	for _, organization := range organizations {
+		tenantIDs = append(tenantIDs, organization.TenantID)
+	}
+
+	return tenantIDs, nil
+}
+
 func (s Service) ListTenantsForUserID(
 	ctx context.Context,
 	userID gid.GID,
@@ -458,11 +651,13 @@ func (s Service) EnrollUserInOrganization(
 	ctx context.Context,
 	userID gid.GID,
 	organizationID gid.GID,
+	role coredata.MembershipRole,
 ) error {
 
 	uo := coredata.UserOrganization{
 		UserID:         userID,
 		OrganizationID: organizationID,
+		Role:           role,
 		CreatedAt:      time.Now(),
 	}
 
@@ -478,8 +673,10 @@ func (s Service) UpdateSession(
 	ctx context.Context,
 	session *coredata.Session,
 ) error {
-	session.UpdatedAt = time.Now()
-	session.ExpiredAt = time.Now().Add(24 * time.Hour)
+	now := time.Now()
+	session.UpdatedAt = now
+	session.LastActivityAt = now
+	session.ExpiredAt = now.Add(24 * time.Hour)
 
 	return s.pg.WithTx(
 		ctx,
@@ -491,7 +688,7 @@ func (s Service) UpdateSession(
 
 func (s Service) ConfirmEmail(ctx context.Context, tokenString string) error {
 	token, err := statelesstoken.ValidateToken[EmailConfirmationData](
-		s.tokenSecret,
+		s.tokenKeyring,
 		TokenTypeEmailConfirmation,
 		tokenString,
 	)
@@ -516,6 +713,10 @@ func (s Service) ConfirmEmail(ctx context.Context, tokenString string) error {
 				return fmt.Errorf("cannot update user email verification: %w", err)
 			}
 
+			if err := autoJoinOrganizations(ctx, tx, user); err != nil {
+				return fmt.Errorf("cannot auto-join organizations: %w", err)
+			}
+
 			return nil
 		},
 	)
@@ -542,89 +743,59 @@ func (s Service) ListUsersForTenant(
 	return page.NewPage(users, cursor), nil
 }
 
-func (s Service) InviteUser(
-	ctx context.Context,
-	organizationID gid.GID,
-	fullName string,
-	emailAddress string,
-) error {
-	if !strings.Contains(emailAddress, "@") {
-		return &ErrInvalidEmail{emailAddress}
-	}
-	if fullName == "" {
-		return &ErrInvalidFullName{fullName}
+func (s Service) RequestPasswordReset(ctx context.Context, email string) error {
+	if !strings.Contains(email, "@") {
+		return &ErrInvalidEmail{email}
 	}
 
-	var userExists bool
-	err := s.pg.WithConn(
+	return s.pg.WithTx(
 		ctx,
 		func(tx pg.Conn) error {
 			user := &coredata.User{}
 
-			if err := user.LoadByEmail(ctx, tx, emailAddress); err != nil {
+			if err := user.LoadByEmail(ctx, tx, email); err != nil {
 				var errUserNotFound *coredata.ErrUserNotFound
 
+				// Do not disclose whether an account exists for the
+				// given email address.
 				if errors.As(err, &errUserNotFound) {
-					userExists = false
 					return nil
 				}
 
 				return fmt.Errorf("cannot load user by email: %w", err)
 			}
 
-			userExists = true
-			uo := coredata.UserOrganization{
-				UserID:         user.ID,
-				OrganizationID: organizationID,
-				CreatedAt:      time.Now(),
+			resetToken, err := statelesstoken.NewToken(
+				s.tokenKeyring,
+				TokenTypePasswordReset,
+				1*time.Hour,
+				PasswordResetData{
+					UserID:       user.ID,
+					Email:        user.EmailAddress,
+					PasswordHash: passwordHashDigest(user.HashedPassword),
+				},
+			)
+			if err != nil {
+				return fmt.Errorf("cannot generate password reset token: %w", err)
 			}
 
-			if err := uo.Insert(ctx, tx); err != nil {
-				return fmt.Errorf("cannot insert user organization: %w", err)
+			resetPasswordUrl := url.URL{
+				Scheme: "https",
+				Host:   s.hostname,
+				Path:   "/reset-password",
+				RawQuery: url.Values{
+					"token": []string{resetToken},
+				}.Encode(),
 			}
 
-			return nil
-		},
-	)
-
-	if err != nil {
-		return err
-	}
-
-	if userExists {
-		return nil
-	}
-
-	confirmationToken, err := statelesstoken.NewToken(
-		s.tokenSecret,
-		TokenTypeOrganizationInvitation,
-		12*time.Hour,
-		InvitationData{OrganizationID: organizationID, Email: emailAddress, FullName: fullName},
-	)
-	if err != nil {
-		return fmt.Errorf("cannot generate confirmation token: %w", err)
-	}
-
-	confirmationInvitationUrl := url.URL{
-		Scheme: "https",
-		Host:   s.hostname,
-		Path:   "/confirm-invitation",
-		RawQuery: url.Values{
-			"token": []string{confirmationToken},
-		}.Encode(),
-	}
+			resetPasswordEmail := coredata.NewEmail(
+				user.FullName,
+				user.EmailAddress,
+				passwordResetEmailSubject,
+				fmt.Sprintf(passwordResetEmailTemplate, resetPasswordUrl.String()),
+			)
 
-	confirmationEmail := coredata.NewEmail(
-		fullName,
-		emailAddress,
-		invitationEmailSubject,
-		fmt.Sprintf(invitationEmailTemplate, confirmationInvitationUrl.String()),
-	)
-
-	return s.pg.WithConn(
-		ctx,
-		func(conn pg.Conn) error {
-			if err := confirmationEmail.Insert(ctx, conn); err != nil {
+			if err := resetPasswordEmail.Insert(ctx, tx); err != nil {
 				return fmt.Errorf("cannot insert email: %w", err)
 			}
 
@@ -633,22 +804,20 @@ func (s Service) InviteUser(
 	)
 }
 
-func (s Service) ConfirmInvitation(ctx context.Context, tokenString string, password string) error {
-	token, err := statelesstoken.ValidateToken[InvitationData](
-		s.tokenSecret,
-		TokenTypeOrganizationInvitation,
+func (s Service) ResetPassword(ctx context.Context, tokenString string, password string) error {
+	token, err := statelesstoken.ValidateToken[PasswordResetData](
+		s.tokenKeyring,
+		TokenTypePasswordReset,
 		tokenString,
 	)
 	if err != nil {
-		return fmt.Errorf("cannot validate organization invitation token: %w", err)
+		return &ErrInvalidPasswordResetToken{message: fmt.Sprintf("invalid password reset token: %s", err)}
 	}
 
 	if len(password) < 8 {
 		return &ErrInvalidPassword{len(password)}
 	}
 
-	now := time.Now()
-
 	hashedPassword, err := s.hp.HashPassword([]byte(password))
 	if err != nil {
 		return fmt.Errorf("cannot hash password: %w", err)
@@ -659,55 +828,38 @@ func (s Service) ConfirmInvitation(ctx context.Context, tokenString string, pass
 		func(tx pg.Conn) error {
 			user := &coredata.User{}
 
-			if err := user.LoadByEmail(ctx, tx, token.Data.Email); err != nil {
+			if err := user.LoadByID(ctx, tx, token.Data.UserID); err != nil {
 				var errUserNotFound *coredata.ErrUserNotFound
 
 				if errors.As(err, &errUserNotFound) {
-					user = &coredata.User{
-						ID:                   gid.New(gid.NilTenant, coredata.UserEntityType),
-						EmailAddress:         token.Data.Email,
-						HashedPassword:       hashedPassword,
-						EmailAddressVerified: true,
-						FullName:             token.Data.FullName,
-						CreatedAt:            now,
-						UpdatedAt:            now,
-					}
-
-					if err := user.Insert(ctx, tx); err != nil {
-						return fmt.Errorf("cannot insert user: %w", err)
-					}
+					return &ErrInvalidPasswordResetToken{message: "invalid password reset token: user not found"}
 				}
-			}
 
-			uo := coredata.UserOrganization{
-				UserID:         user.ID,
-				OrganizationID: token.Data.OrganizationID,
-				CreatedAt:      now,
+				return fmt.Errorf("cannot load user by id: %w", err)
 			}
 
-			if err := uo.Insert(ctx, tx); err != nil {
-				return fmt.Errorf("cannot insert user organization: %w", err)
+			if user.EmailAddress != token.Data.Email {
+				return &ErrInvalidPasswordResetToken{message: "invalid password reset token: email does not match"}
 			}
 
-			return nil
-		},
-	)
-}
+			if passwordHashDigest(user.HashedPassword) != token.Data.PasswordHash {
+				return &ErrInvalidPasswordResetToken{message: "invalid password reset token: token already used"}
+			}
 
-func (s Service) RemoveUser(ctx context.Context, organizationID gid.GID, userID gid.GID) error {
-	return s.pg.WithConn(
-		ctx,
-		func(tx pg.Conn) error {
-			uo := coredata.UserOrganization{
-				UserID:         userID,
-				OrganizationID: organizationID,
+			if err := user.UpdatePassword(ctx, tx, hashedPassword); err != nil {
+				return fmt.Errorf("cannot update user password: %w", err)
 			}
 
-			if err := uo.Delete(ctx, tx); err != nil {
-				return fmt.Errorf("cannot delete user organization: %w", err)
+			if err := coredata.DeleteUserSessions(ctx, tx, user.ID); err != nil {
+				return fmt.Errorf("cannot delete user sessions: %w", err)
 			}
 
 			return nil
 		},
 	)
 }
+
+func passwordHashDigest(hashedPassword []byte) string {
+	sum := sha256.Sum256(hashedPassword)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,490 @@
+// Copyright (c) 2025 Probo Inc <hello@getprobo.com>.
+//
+// Permission to use, copy, modify, and/or distribute this software for any
+// purpose with or without fee is hereby granted, provided that the above
+// copyright notice and this permission notice appear in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH
+// REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY
+// AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT,
+// INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM
+// LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR
+// OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR
+// PERFORMANCE OF THIS SOFTWARE.
+
+package usrmgr
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/getprobo/probo/pkg/coredata"
+	"github.com/getprobo/probo/pkg/gid"
+	"github.com/getprobo/probo/pkg/statelesstoken"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"go.gearno.de/kit/pg"
+)
+
+type (
+	ErrInvalidWebAuthnResponse struct {
+		message string
+	}
+
+	ErrWebAuthnCredentialNotFound struct {
+		message string
+	}
+
+	ErrInvalidWebAuthnCredentialName struct {
+		name string
+	}
+
+	WebAuthnCeremonyData struct {
+		UserID  gid.GID              `json:"uid"`
+		Session webauthn.SessionData `json:"session"`
+	}
+
+	// webAuthnUser adapts a user and its registered credentials to the
+	// interface expected by the webauthn library.
+	webAuthnUser struct {
+		user        *coredata.User
+		credentials coredata.WebAuthnCredentials
+	}
+)
+
+const (
+	webAuthnRPDisplayName           = "Probo"
+	webAuthnCeremonyTimeout         = 5 * time.Minute
+	defaultWebAuthnCredentialName   = "Passkey"
+	maxWebAuthnCredentialNameLength = 100
+)
+
+func (e ErrInvalidWebAuthnResponse) Error() string {
+	return e.message
+}
+
+func (e ErrWebAuthnCredentialNotFound) Error() string {
+	return e.message
+}
+
+func (e ErrInvalidWebAuthnCredentialName) Error() string {
+	return fmt.Sprintf("invalid passkey name %q", e.name)
+}
+
+func (u *webAuthnUser) WebAuthnID() []byte {
+	return []byte(u.user.ID.String())
+}
+
+func (u *webAuthnUser) WebAuthnName() string {
+	return u.user.EmailAddress
+}
+
+func (u *webAuthnUser) WebAuthnDisplayName() string {
+	return u.user.FullName
+}
+
+func (u *webAuthnUser) WebAuthnIcon() string {
+	return ""
+}
+
+func (u *webAuthnUser) WebAuthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, 0, len(u.credentials))
+	for _, c := range u.credentials {
+		transports := make([]protocol.AuthenticatorTransport, 0, len(c.Transports))
+		for _, t := range c.Transports {
+			transports = append(transports, protocol.AuthenticatorTransport(t))
+		}
+
+		credentials = append(
+			credentials,
+			webauthn.Credential{
+				ID:              c.CredentialID,
+				PublicKey:       c.PublicKey,
+				AttestationType: c.AttestationType,
+				Transport:       transports,
+				Flags: webauthn.CredentialFlags{
+					BackupEligible: c.BackupEligible,
+					BackupState:    c.BackupState,
+				},
+				Authenticator: webauthn.Authenticator{
+					AAGUID:    c.AAGUID,
+					SignCount: uint32(c.SignCount),
+				},
+			},
+		)
+	}
+
+	return credentials
+}
+
+func newWebAuthn(hostname string) (*webauthn.WebAuthn, error) {
+	rpID := hostname
+	if host, _, err := net.SplitHostPort(hostname); err == nil {
+		rpID = host
+	}
+
+	origins := []string{"https://" + hostname}
+	if rpID == "localhost" {
+		origins = append(origins, "http://"+hostname)
+	}
+
+	timeout := webauthn.TimeoutConfig{
+		Enforce:    true,
+		Timeout:    webAuthnCeremonyTimeout,
+		TimeoutUVD: webAuthnCeremonyTimeout,
+	}
+
+	return webauthn.New(
+		&webauthn.Config{
+			RPID:          rpID,
+			RPDisplayName: webAuthnRPDisplayName,
+			RPOrigins:     origins,
+			AuthenticatorSelection: protocol.AuthenticatorSelection{
+				ResidentKey:      protocol.ResidentKeyRequirementRequired,
+				UserVerification: protocol.VerificationRequired,
+			},
+			AttestationPreference: protocol.PreferNoAttestation,
+			Timeouts: webauthn.TimeoutsConfig{
+				Login:        timeout,
+				Registration: timeout,
+			},
+		},
+	)
+}
+
+// BeginWebAuthnRegistration starts a passkey registration ceremony for
+// the user. It returns the options to pass to
+// navigator.credentials.create() and a signed token holding the ceremony
+// state, which must be sent back to FinishWebAuthnRegistration.
+func (s Service) BeginWebAuthnRegistration(
+	ctx context.Context,
+	userID gid.GID,
+) (*protocol.CredentialCreation, string, error) {
+	user := &webAuthnUser{user: &coredata.User{}}
+
+	err := s.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			if err := user.user.LoadByID(ctx, conn, userID); err != nil {
+				return fmt.Errorf("cannot load user: %w", err)
+			}
+
+			if err := user.credentials.LoadByUserID(ctx, conn, userID); err != nil {
+				return fmt.Errorf("cannot load webauthn credentials: %w", err)
+			}
+
+			return nil
+		},
+	)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	exclusions := make([]protocol.CredentialDescriptor, 0, len(user.credentials))
+	for _, c := range user.WebAuthnCredentials() {
+		exclusions = append(exclusions, c.Descriptor())
+	}
+
+	creation, session, err := s.webauthn.BeginRegistration(
+		user,
+		webauthn.WithExclusions(exclusions),
+	)
+	if err != nil {
+		return nil, "", fmt.Errorf("cannot begin webauthn registration: %w", err)
+	}
+
+	ceremonyToken, err := statelesstoken.NewToken(
+		s.tokenKeyring,
+		TokenTypeWebAuthnRegistration,
+		webAuthnCeremonyTimeout,
+		WebAuthnCeremonyData{UserID: userID, Session: *session},
+	)
+	if err != nil {
+		return nil, "", fmt.Errorf("cannot generate webauthn registration token: %w", err)
+	}
+
+	return creation, ceremonyToken, nil
+}
+
+// FinishWebAuthnRegistration verifies the authenticator response of a
+// registration ceremony started with BeginWebAuthnRegistration and stores
+// the new credential.
+func (s Service) FinishWebAuthnRegistration(
+	ctx context.Context,
+	userID gid.GID,
+	ceremonyToken string,
+	name string,
+	response *protocol.ParsedCredentialCreationData,
+) (*coredata.WebAuthnCredential, error) {
+	if name == "" {
+		name = defaultWebAuthnCredentialName
+	}
+
+	if len(name) > maxWebAuthnCredentialNameLength {
+		return nil, &ErrInvalidWebAuthnCredentialName{name: name}
+	}
+
+	token, err := statelesstoken.ValidateToken[WebAuthnCeremonyData](
+		s.tokenKeyring,
+		TokenTypeWebAuthnRegistration,
+		ceremonyToken,
+	)
+	if err != nil {
+		return nil, &ErrInvalidWebAuthnResponse{message: "invalid or expired registration ceremony"}
+	}
+
+	if token.Data.UserID != userID {
+		return nil, &ErrInvalidWebAuthnResponse{message: "registration ceremony does not belong to user"}
+	}
+
+	now := time.Now()
+	credential := &coredata.WebAuthnCredential{
+		ID:        gid.New(gid.NilTenant, coredata.WebAuthnCredentialEntityType),
+		UserID:    userID,
+		Name:      name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	err = s.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+			user := &webAuthnUser{user: &coredata.User{}}
+			if err := user.user.LoadByID(ctx, tx, userID); err != nil {
+				return fmt.Errorf("cannot load user: %w", err)
+			}
+
+			c, err := s.webauthn.CreateCredential(user, token.Data.Session, response)
+			if err != nil {
+				return &ErrInvalidWebAuthnResponse{message: fmt.Sprintf("cannot verify registration: %s", err)}
+			}
+
+			transports := make([]string, 0, len(c.Transport))
+			for _, t := range c.Transport {
+				transports = append(transports, string(t))
+			}
+
+			credential.CredentialID = c.ID
+			credential.PublicKey = c.PublicKey
+			credential.AttestationType = c.AttestationType
+			credential.Transports = transports
+			credential.AAGUID = c.Authenticator.AAGUID
+			credential.SignCount = int64(c.Authenticator.SignCount)
+			credential.BackupEligible = c.Flags.BackupEligible
+			credential.BackupState = c.Flags.BackupState
+
+			if err := credential.Insert(ctx, tx); err != nil {
+				return fmt.Errorf("cannot insert webauthn credential: %w", err)
+			}
+
+			return nil
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return credential, nil
+}
+
+// BeginWebAuthnLogin starts a passkey sign in ceremony. The ceremony is
+// discoverable: the authenticator tells which user is signing in, so
+// no email address is required.
+func (s Service) BeginWebAuthnLogin(
+	ctx context.Context,
+) (*protocol.CredentialAssertion, string, error) {
+	assertion, session, err := s.webauthn.BeginDiscoverableLogin()
+	if err != nil {
+		return nil, "", fmt.Errorf("cannot begin webauthn login: %w", err)
+	}
+
+	ceremonyToken, err := statelesstoken.NewToken(
+		s.tokenKeyring,
+		TokenTypeWebAuthnLogin,
+		webAuthnCeremonyTimeout,
+		WebAuthnCeremonyData{Session: *session},
+	)
+	if err != nil {
+		return nil, "", fmt.Errorf("cannot generate webauthn login token: %w", err)
+	}
+
+	return assertion, ceremonyToken, nil
+}
+
+// FinishWebAuthnLogin verifies the authenticator assertion of a sign in
+// ceremony started with BeginWebAuthnLogin and opens a new session. A
+// passkey proves both possession and user verification, so the TOTP
+// challenge is not required.
+func (s Service) FinishWebAuthnLogin(
+	ctx context.Context,
+	ceremonyToken string,
+	response *protocol.ParsedCredentialAssertionData,
+) (*coredata.User, *coredata.Session, error) {
+	token, err := statelesstoken.ValidateToken[WebAuthnCeremonyData](
+		s.tokenKeyring,
+		TokenTypeWebAuthnLogin,
+		ceremonyToken,
+	)
+	if err != nil {
+		return nil, nil, &ErrInvalidCredentials{message: "invalid or expired login ceremony"}
+	}
+
+	now := time.Now()
+	user := &webAuthnUser{user: &coredata.User{}}
+	session := &coredata.Session{
+		ID:             gid.New(gid.NilTenant, coredata.SessionEntityType),
+		LastActivityAt: now,
+		ExpiredAt:      now.Add(24 * time.Hour),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	err = s.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+			handler := func(rawID, userHandle []byte) (webauthn.User, error) {
+				userID, err := gid.ParseGID(string(userHandle))
+				if err != nil {
+					return nil, fmt.Errorf("cannot parse user handle: %w", err)
+				}
+
+				if err := user.user.LoadByID(ctx, tx, userID); err != nil {
+					return nil, fmt.Errorf("cannot load user: %w", err)
+				}
+
+				if err := user.credentials.LoadByUserID(ctx, tx, userID); err != nil {
+					return nil, fmt.Errorf("cannot load webauthn credentials: %w", err)
+				}
+
+				return user, nil
+			}
+
+			c, err := s.webauthn.ValidateDiscoverableLogin(handler, token.Data.Session, response)
+			if err != nil {
+				return &ErrInvalidCredentials{message: "invalid passkey"}
+			}
+
+			if c.Authenticator.CloneWarning {
+				return &ErrInvalidCredentials{message: "passkey signature counter mismatch"}
+			}
+
+			var credential *coredata.WebAuthnCredential
+			for _, wc := range user.credentials {
+				if bytes.Equal(wc.CredentialID, c.ID) {
+					credential = wc
+					break
+				}
+			}
+
+			if credential == nil {
+				return &ErrInvalidCredentials{message: "invalid passkey"}
+			}
+
+			credential.SignCount = int64(c.Authenticator.SignCount)
+			credential.BackupState = c.Flags.BackupState
+			credential.LastUsedAt = &now
+			credential.UpdatedAt = now
+
+			if err := credential.Update(ctx, tx); err != nil {
+				return fmt.Errorf("cannot update webauthn credential: %w", err)
+			}
+
+			session.UserID = user.user.ID
+			if err := session.Insert(ctx, tx); err != nil {
+				return fmt.Errorf("cannot insert session: %w", err)
+			}
+
+			return nil
+		},
+	)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user.user, session, nil
+}
+
+func (s Service) ListWebAuthnCredentials(
+	ctx context.Context,
+	userID gid.GID,
+) (coredata.WebAuthnCredentials, error) {
+	var credentials coredata.WebAuthnCredentials
+
+	err := s.pg.WithConn(
+		ctx,
+		func(conn pg.Conn) error {
+			return credentials.LoadByUserID(ctx, conn, userID)
+		},
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot list webauthn credentials: %w", err)
+	}
+
+	return credentials, nil
+}
+
+func (s Service) RenameWebAuthnCredential(
+	ctx context.Context,
+	userID gid.GID,
+	credentialID gid.GID,
+	name string,
+) (*coredata.WebAuthnCredential, error) {
+	if name == "" || len(name) > maxWebAuthnCredentialNameLength {
+		return nil, &ErrInvalidWebAuthnCredentialName{name: name}
+	}
+
+	credential := &coredata.WebAuthnCredential{}
+
+	err := s.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+			if err := credential.LoadByUserIDAndID(ctx, tx, userID, credentialID); err != nil {
+				return &ErrWebAuthnCredentialNotFound{message: "passkey not found"}
+			}
+
+			credential.Name = name
+			credential.UpdatedAt = time.Now()
+
+			if err := credential.Update(ctx, tx); err != nil {
+				return fmt.Errorf("cannot update webauthn credential: %w", err)
+			}
+
+			return nil
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return credential, nil
+}
+
+func (s Service) DeleteWebAuthnCredential(
+	ctx context.Context,
+	userID gid.GID,
+	credentialID gid.GID,
+) error {
+	return s.pg.WithTx(
+		ctx,
+		func(tx pg.Conn) error {
+			credential := &coredata.WebAuthnCredential{}
+			if err := credential.LoadByUserIDAndID(ctx, tx, userID, credentialID); err != nil {
+				return &ErrWebAuthnCredentialNotFound{message: "passkey not found"}
+			}
+
+			if err := credential.Delete(ctx, tx); err != nil {
+				return fmt.Errorf("cannot delete webauthn credential: %w", err)
+			}
+
+			return nil
+		},
+	)
+}